@@ -42,6 +42,9 @@ func (src *MetalCluster) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 
+	dst.Spec.FailureDomains = restored.Spec.FailureDomains
+	dst.Status.FailureDomains = restored.Status.FailureDomains
+
 	return nil
 }
 
@@ -27,6 +27,8 @@ func (src *MetalMachine) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 
+	dst.Spec.ConfigPatchRefs = restored.Spec.ConfigPatchRefs
+
 	return nil
 }
 
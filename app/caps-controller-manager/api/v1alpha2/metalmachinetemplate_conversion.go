@@ -25,6 +25,8 @@ func (src *MetalMachineTemplate) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 
+	dst.Spec.Template.Spec.ConfigPatchRefs = restored.Spec.Template.Spec.ConfigPatchRefs
+
 	return nil
 }
 
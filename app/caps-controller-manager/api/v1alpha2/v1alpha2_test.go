@@ -4,11 +4,83 @@
 
 package v1alpha2_test
 
-import "testing"
+import (
+	"testing"
 
-func TestEmpty(t *testing.T) {
-	// added for accurate coverage estimation
-	//
-	// please remove it once any unit-test is added
-	// for this package
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1alpha2 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha2"
+	infrav1alpha3 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
+)
+
+func TestMetalClusterConversionPreservesFailureDomains(t *testing.T) {
+	hub := &infrav1alpha3.MetalCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "cluster",
+		},
+		Spec: infrav1alpha3.MetalClusterSpec{
+			ControlPlaneEndpoint: capiv1.APIEndpoint{
+				Host: "172.20.0.1",
+				Port: 6443,
+			},
+			FailureDomains: []infrav1alpha3.MetalClusterFailureDomain{
+				{
+					Name:         "rack-1",
+					ControlPlane: true,
+					Selector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"rack": "1"},
+					},
+				},
+			},
+		},
+		Status: infrav1alpha3.MetalClusterStatus{
+			Ready: true,
+			FailureDomains: capiv1.FailureDomains{
+				"rack-1": capiv1.FailureDomainSpec{ControlPlane: true},
+			},
+		},
+	}
+
+	var spoke infrav1alpha2.MetalCluster
+
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	var restored infrav1alpha3.MetalCluster
+
+	require.NoError(t, spoke.ConvertTo(&restored))
+
+	assert.Equal(t, hub.Spec, restored.Spec)
+	assert.Equal(t, hub.Status.FailureDomains, restored.Status.FailureDomains)
+}
+
+func TestMetalMachineTemplateConversionPreservesConfigPatchRefs(t *testing.T) {
+	hub := &infrav1alpha3.MetalMachineTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "workers",
+		},
+		Spec: infrav1alpha3.MetalMachineTemplateSpec{
+			Template: infrav1alpha3.MetalMachineTemplateResource{
+				Spec: infrav1alpha3.MetalMachineSpec{
+					ConfigPatchRefs: []corev1.LocalObjectReference{
+						{Name: "ntp"},
+						{Name: "registry-mirrors"},
+					},
+				},
+			},
+		},
+	}
+
+	var spoke infrav1alpha2.MetalMachineTemplate
+
+	require.NoError(t, spoke.ConvertFrom(hub))
+
+	var restored infrav1alpha3.MetalMachineTemplate
+
+	require.NoError(t, spoke.ConvertTo(&restored))
+
+	assert.Equal(t, hub.Spec, restored.Spec)
 }
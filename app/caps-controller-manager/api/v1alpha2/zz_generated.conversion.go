@@ -240,6 +240,7 @@ func autoConvert_v1alpha2_MetalClusterSpec_To_v1alpha3_MetalClusterSpec(in *Meta
 
 func autoConvert_v1alpha3_MetalClusterSpec_To_v1alpha2_MetalClusterSpec(in *v1alpha3.MetalClusterSpec, out *MetalClusterSpec, s conversion.Scope) error {
 	// WARNING: in.ControlPlaneEndpoint requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureDomains requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -251,6 +252,7 @@ func autoConvert_v1alpha2_MetalClusterStatus_To_v1alpha3_MetalClusterStatus(in *
 
 func autoConvert_v1alpha3_MetalClusterStatus_To_v1alpha2_MetalClusterStatus(in *v1alpha3.MetalClusterStatus, out *MetalClusterStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
+	// WARNING: in.FailureDomains requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -338,6 +340,7 @@ func autoConvert_v1alpha3_MetalMachineSpec_To_v1alpha2_MetalMachineSpec(in *v1al
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.ServerRef = (*v1.ObjectReference)(unsafe.Pointer(in.ServerRef))
 	// WARNING: in.ServerClassRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConfigPatchRefs requires manual conversion: does not exist in peer-type
 	return nil
 }
 
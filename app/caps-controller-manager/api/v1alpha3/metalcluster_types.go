@@ -19,11 +19,44 @@ type MetalClusterSpec struct {
 	// ControlPlaneEndpoint represents the endpoint used to communicate with the control plane.
 	// +optional
 	ControlPlaneEndpoint capiv1.APIEndpoint `json:"controlPlaneEndpoint"`
+	// FailureDomains defines the failure domains of the cluster mapped to the servers.
+	//
+	// Machines placed into the failure domain are allocated only from the servers matching its selector.
+	// +optional
+	FailureDomains []MetalClusterFailureDomain `json:"failureDomains,omitempty"`
+}
+
+// MetalClusterFailureDomain defines a failure domain (e.g. a rack) as a set of servers.
+type MetalClusterFailureDomain struct {
+	// Name of the failure domain.
+	Name string `json:"name"`
+	// ControlPlane determines if this failure domain is suitable for use by control plane machines.
+	// +optional
+	ControlPlane bool `json:"controlPlane,omitempty"`
+	// Selector is the Server label selector matching the servers in the failure domain.
+	Selector metav1.LabelSelector `json:"selector"`
+	// Attributes is a free form map of attributes published in the Cluster API failure domain.
+	// +optional
+	Attributes map[string]string `json:"attributes,omitempty"`
 }
 
 // MetalClusterStatus defines the observed state of MetalCluster.
 type MetalClusterStatus struct {
 	Ready bool `json:"ready"`
+	// FailureDomains is the list of failure domains published to the Cluster API.
+	// +optional
+	FailureDomains capiv1.FailureDomains `json:"failureDomains,omitempty"`
+}
+
+// FailureDomain returns the failure domain by name.
+func (cluster *MetalCluster) FailureDomain(name string) *MetalClusterFailureDomain {
+	for i := range cluster.Spec.FailureDomains {
+		if cluster.Spec.FailureDomains[i].Name == name {
+			return &cluster.Spec.FailureDomains[i]
+		}
+	}
+
+	return nil
 }
 
 // +kubebuilder:object:root=true
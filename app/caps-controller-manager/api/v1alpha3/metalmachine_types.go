@@ -26,6 +26,10 @@ type MetalMachineSpec struct {
 
 	ServerRef      *corev1.ObjectReference `json:"serverRef,omitempty"`
 	ServerClassRef *corev1.ObjectReference `json:"serverClassRef,omitempty"`
+
+	// ConfigPatchRefs references the MachineConfigPatches to apply to the server allocated to the machine.
+	// +optional
+	ConfigPatchRefs []corev1.LocalObjectReference `json:"configPatchRefs,omitempty"`
 }
 
 // MetalMachineStatus defines the observed state of MetalMachine.
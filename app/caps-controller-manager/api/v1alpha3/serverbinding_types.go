@@ -48,6 +48,43 @@ type ServerBindingState struct {
 	// Conditions defines current state of the ServerBinding.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// ConfigToken is the one-time token issued to the server on boot to fetch the machine configuration.
+	// +optional
+	ConfigToken *ConfigToken `json:"configToken,omitempty"`
+
+	// AppliedConfigPatches lists the MachineConfigPatches applied to the machine configuration in the order they were applied.
+	// +optional
+	AppliedConfigPatches []AppliedConfigPatch `json:"appliedConfigPatches,omitempty"`
+}
+
+// AppliedConfigPatch describes the MachineConfigPatch applied to the machine configuration.
+type AppliedConfigPatch struct {
+	// Name is the name of the MachineConfigPatch.
+	Name string `json:"name"`
+
+	// Generation is the generation of the MachineConfigPatch which was applied.
+	Generation int64 `json:"generation"`
+}
+
+// ConfigToken describes the one-time token to fetch the machine configuration.
+type ConfigToken struct {
+	// Hash is the hex-encoded SHA256 hash of the token, the token itself is not stored.
+	Hash string `json:"hash"`
+
+	// Address is the IP address of the client the token was issued to, the token is accepted only from this address.
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// IssuedAt is the time the token was issued.
+	IssuedAt metav1.Time `json:"issuedAt"`
+
+	// ExpiresAt is the time the token expires.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+
+	// UsedAt is the time the token was used, the token can't be used again.
+	// +optional
+	UsedAt *metav1.Time `json:"usedAt,omitempty"`
 }
 
 // +kubebuilder:object:root=true
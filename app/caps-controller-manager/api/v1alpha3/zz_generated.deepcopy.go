@@ -15,13 +15,49 @@ import (
 	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedConfigPatch) DeepCopyInto(out *AppliedConfigPatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedConfigPatch.
+func (in *AppliedConfigPatch) DeepCopy() *AppliedConfigPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedConfigPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigToken) DeepCopyInto(out *ConfigToken) {
+	*out = *in
+	in.IssuedAt.DeepCopyInto(&out.IssuedAt)
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+	if in.UsedAt != nil {
+		in, out := &in.UsedAt, &out.UsedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigToken.
+func (in *ConfigToken) DeepCopy() *ConfigToken {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetalCluster) DeepCopyInto(out *MetalCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalCluster.
@@ -42,6 +78,29 @@ func (in *MetalCluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetalClusterFailureDomain) DeepCopyInto(out *MetalClusterFailureDomain) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalClusterFailureDomain.
+func (in *MetalClusterFailureDomain) DeepCopy() *MetalClusterFailureDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(MetalClusterFailureDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetalClusterList) DeepCopyInto(out *MetalClusterList) {
 	*out = *in
@@ -78,6 +137,13 @@ func (in *MetalClusterList) DeepCopyObject() runtime.Object {
 func (in *MetalClusterSpec) DeepCopyInto(out *MetalClusterSpec) {
 	*out = *in
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]MetalClusterFailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalClusterSpec.
@@ -93,6 +159,13 @@ func (in *MetalClusterSpec) DeepCopy() *MetalClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetalClusterStatus) DeepCopyInto(out *MetalClusterStatus) {
 	*out = *in
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make(v1beta1.FailureDomains, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalClusterStatus.
@@ -182,6 +255,11 @@ func (in *MetalMachineSpec) DeepCopyInto(out *MetalMachineSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.ConfigPatchRefs != nil {
+		in, out := &in.ConfigPatchRefs, &out.ConfigPatchRefs
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetalMachineSpec.
@@ -433,6 +511,16 @@ func (in *ServerBindingState) DeepCopyInto(out *ServerBindingState) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ConfigToken != nil {
+		in, out := &in.ConfigToken, &out.ConfigToken
+		*out = new(ConfigToken)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AppliedConfigPatches != nil {
+		in, out := &in.AppliedConfigPatches, &out.AppliedConfigPatches
+		*out = make([]AppliedConfigPatch, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerBindingState.
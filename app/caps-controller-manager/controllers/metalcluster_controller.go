@@ -12,6 +12,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -97,6 +98,19 @@ func (r *MetalClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		return ctrl.Result{}, nil
 	}
 
+	metalCluster.Status.FailureDomains = nil
+
+	for _, failureDomain := range metalCluster.Spec.FailureDomains {
+		if metalCluster.Status.FailureDomains == nil {
+			metalCluster.Status.FailureDomains = capiv1.FailureDomains{}
+		}
+
+		metalCluster.Status.FailureDomains[failureDomain.Name] = capiv1.FailureDomainSpec{
+			ControlPlane: failureDomain.ControlPlane,
+			Attributes:   failureDomain.Attributes,
+		}
+	}
+
 	metalCluster.Status.Ready = true
 
 	return ctrl.Result{}, nil
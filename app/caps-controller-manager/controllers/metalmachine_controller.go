@@ -8,12 +8,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/siderolabs/go-pointer"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -48,6 +52,7 @@ type MetalMachineReconciler struct {
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=metalmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=metalmachines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=metalclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups=metal.sidero.dev,resources=serverclasses,verbs=get;list;watch;
 // +kubebuilder:rbac:groups=metal.sidero.dev,resources=serverclasses/status,verbs=get;list;watch;
@@ -145,7 +150,12 @@ func (r *MetalMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request
 			return ctrl.Result{}, fmt.Errorf("either a server or serverclass ref must be supplied")
 		}
 
-		serverResource, err := r.fetchServerFromClass(ctx, logger, metalMachine.Spec.ServerClassRef, metalMachine)
+		failureDomain, err := r.fetchFailureDomain(ctx, cluster, machine)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		serverResource, err := r.fetchServerFromClass(ctx, logger, metalMachine.Spec.ServerClassRef, metalMachine, failureDomain)
 		if err != nil {
 			if errors.Is(err, ErrNoServersInServerClass) {
 				return ctrl.Result{RequeueAfter: constants.DefaultRequeueAfter}, nil
@@ -304,7 +314,38 @@ func (r *MetalMachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.
 		Complete(r)
 }
 
-func (r *MetalMachineReconciler) fetchServerFromClass(ctx context.Context, logger logr.Logger, classRef *corev1.ObjectReference, metalMachine *infrav1.MetalMachine) (*metalv1.Server, error) {
+// fetchFailureDomain returns the MetalCluster failure domain the machine should be placed into.
+//
+// If the machine is not placed into a failure domain, nil is returned.
+func (r *MetalMachineReconciler) fetchFailureDomain(ctx context.Context, cluster *capiv1.Cluster, machine *capiv1.Machine) (*infrav1.MetalClusterFailureDomain, error) {
+	if pointer.SafeDeref(machine.Spec.FailureDomain) == "" {
+		return nil, nil
+	}
+
+	if cluster.Spec.InfrastructureRef == nil {
+		return nil, fmt.Errorf("cluster %q has no infrastructure ref", cluster.Name)
+	}
+
+	var metalCluster infrav1.MetalCluster
+
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Spec.InfrastructureRef.Name}, &metalCluster); err != nil {
+		return nil, err
+	}
+
+	failureDomain := metalCluster.FailureDomain(*machine.Spec.FailureDomain)
+	if failureDomain == nil {
+		return nil, fmt.Errorf("failure domain %q is not defined in metal cluster %q", *machine.Spec.FailureDomain, metalCluster.Name)
+	}
+
+	return failureDomain, nil
+}
+
+// fetchServerFromClass allocates the server from the server class.
+//
+// If the failure domain is set, only servers matching its selector are allocated.
+func (r *MetalMachineReconciler) fetchServerFromClass(ctx context.Context, logger logr.Logger, classRef *corev1.ObjectReference, metalMachine *infrav1.MetalMachine,
+	failureDomain *infrav1.MetalClusterFailureDomain,
+) (*metalv1.Server, error) {
 	// First, check if there is already existing serverBinding for this metalmachine
 	var serverBindingList infrav1.ServerBindingList
 
@@ -333,7 +374,13 @@ func (r *MetalMachineReconciler) fetchServerFromClass(ctx context.Context, logge
 		return nil, err
 	}
 
-	if len(serverClassResource.Status.ServersAvailable) == 0 {
+	candidateNames := slices.Clone(serverClassResource.Status.ServersAvailable)
+
+	for _, reserved := range serverClassResource.Status.ServersReserved {
+		candidateNames = append(candidateNames, reserved.Server)
+	}
+
+	if len(candidateNames) == 0 {
 		return nil, ErrNoServersInServerClass
 	}
 
@@ -342,10 +389,26 @@ func (r *MetalMachineReconciler) fetchServerFromClass(ctx context.Context, logge
 		return nil, err
 	}
 
-	// Fetch server from available list
-	// NB: we added this loop to double check that an available server isn't "in use" because
+	var failureDomainName string
+
+	failureDomainSelector := labels.Everything()
+
+	if failureDomain != nil {
+		failureDomainName = failureDomain.Name
+
+		if failureDomainSelector, err = metav1.LabelSelectorAsSelector(&failureDomain.Selector); err != nil {
+			return nil, fmt.Errorf("failed to get selector of failure domain %q: %w", failureDomain.Name, err)
+		}
+	}
+
+	// Fetch servers from available and reserved lists
+	// NB: we added this check to double check that an available server isn't "in use" because
 	//     we saw raciness between server selection and it being removed from the ServersAvailable list.
-	for _, availServer := range serverClassResource.Status.ServersAvailable {
+	var reservedServers, availServers []metalv1.Server
+
+	now := time.Now()
+
+	for _, availServer := range candidateNames {
 		serverObj := &metalv1.Server{}
 
 		namespacedName := types.NamespacedName{
@@ -357,11 +420,6 @@ func (r *MetalMachineReconciler) fetchServerFromClass(ctx context.Context, logge
 			return nil, err
 		}
 
-		serverRef, err := reference.GetReference(r.Scheme, serverObj)
-		if err != nil {
-			return nil, err
-		}
-
 		if serverObj.Status.InUse {
 			continue
 		}
@@ -370,6 +428,54 @@ func (r *MetalMachineReconciler) fetchServerFromClass(ctx context.Context, logge
 			continue
 		}
 
+		if !failureDomainSelector.Matches(labels.Set(serverObj.Labels)) {
+			continue
+		}
+
+		if reservation := serverObj.Spec.ReservedFor; reservation.Active(now) {
+			match, err := reservation.Matches(metalMachine.Namespace, metalMachine.Labels)
+			if err != nil {
+				return nil, err
+			}
+
+			// servers reserved for someone else are skipped
+			if match {
+				reservedServers = append(reservedServers, *serverObj)
+			}
+
+			continue
+		}
+
+		availServers = append(availServers, *serverObj)
+	}
+
+	strategy := serverClassResource.Spec.AllocationStrategy
+
+	var allocatedServers []metalv1.Server
+
+	if strategyType := strategy.StrategyType(); strategyType == metalv1.AllocationStrategySpread || strategyType == metalv1.AllocationStrategyPack {
+		if allocatedServers, err = r.fetchClusterServers(ctx, metalMachine); err != nil {
+			return nil, err
+		}
+	}
+
+	// servers reserved for the machine are preferred over the unreserved ones
+	candidates := strategy.OrderServers(reservedServers, allocatedServers)
+
+	for i := range candidates {
+		candidates[i].Reason = fmt.Sprintf("reserved for %s, %s", candidates[i].Server.Spec.ReservedFor, candidates[i].Reason)
+	}
+
+	candidates = append(candidates, strategy.OrderServers(availServers, allocatedServers)...)
+
+	for _, candidate := range candidates {
+		serverObj := &candidate.Server
+
+		serverRef, err := reference.GetReference(r.Scheme, serverObj)
+		if err != nil {
+			return nil, err
+		}
+
 		if err := r.createServerBinding(ctx, serverClassRef, serverObj, metalMachine); err != nil {
 			// the server we picked was updated by another metalmachine before we finished.
 			// move on to the next one.
@@ -380,9 +486,11 @@ func (r *MetalMachineReconciler) fetchServerFromClass(ctx context.Context, logge
 			return nil, err
 		}
 
-		r.Recorder.Event(serverRef, corev1.EventTypeNormal, "Server Allocation", fmt.Sprintf("Server is allocated via serverclass %q for metal machine %q.", serverClassResource.Name, metalMachine.Name))
+		r.Recorder.Event(serverRef, corev1.EventTypeNormal, "Server Allocation",
+			fmt.Sprintf("Server is allocated via serverclass %q for metal machine %q using %s strategy: %s.", serverClassResource.Name, metalMachine.Name, strategy.StrategyType(), candidate.Reason))
 
-		logger.Info("allocated new server", "metalmachine", metalMachine.Name, "server", serverObj.Name, "serverclass", serverClassResource.Name)
+		logger.Info("allocated new server", "metalmachine", metalMachine.Name, "server", serverObj.Name, "serverclass", serverClassResource.Name,
+			"strategy", strategy.StrategyType(), "reason", candidate.Reason, "failureDomain", failureDomainName)
 
 		return serverObj, nil
 	}
@@ -453,6 +561,38 @@ func (r *MetalMachineReconciler) createServerBinding(ctx context.Context, server
 	return r.Create(ctx, &serverBinding)
 }
 
+// fetchClusterServers returns the servers already allocated to the cluster of the metal machine.
+func (r *MetalMachineReconciler) fetchClusterServers(ctx context.Context, metalMachine *infrav1.MetalMachine) ([]metalv1.Server, error) {
+	clusterName, ok := metalMachine.Labels[capiv1.ClusterNameLabel]
+	if !ok {
+		return nil, nil
+	}
+
+	var serverBindingList infrav1.ServerBindingList
+
+	if err := r.List(ctx, &serverBindingList, client.MatchingLabels{capiv1.ClusterNameLabel: clusterName}); err != nil {
+		return nil, err
+	}
+
+	servers := make([]metalv1.Server, 0, len(serverBindingList.Items))
+
+	for _, serverBinding := range serverBindingList.Items {
+		var server metalv1.Server
+
+		if err := r.Get(ctx, types.NamespacedName{Namespace: serverBinding.Namespace, Name: serverBinding.Name}, &server); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		servers = append(servers, server)
+	}
+
+	return servers, nil
+}
+
 func (r *MetalMachineReconciler) fetchServerClass(ctx context.Context, classRef *corev1.ObjectReference) (*metalv1.ServerClass, error) {
 	serverClassResource := &metalv1.ServerClass{}
 
@@ -25,6 +25,20 @@ func (src *Environment) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 
+	dst.Spec.UKI = restored.Spec.UKI
+	dst.Spec.Sources = restored.Spec.Sources
+	dst.Spec.ImageFactory = restored.Spec.ImageFactory
+	dst.Status.Replicas = restored.Status.Replicas
+
+	for i := range dst.Status.Conditions {
+		for _, cond := range restored.Status.Conditions {
+			if cond.URL == dst.Status.Conditions[i].URL && cond.Type == dst.Status.Conditions[i].Type {
+				dst.Status.Conditions[i].Reason = cond.Reason
+				dst.Status.Conditions[i].Message = cond.Message
+			}
+		}
+	}
+
 	return nil
 }
 
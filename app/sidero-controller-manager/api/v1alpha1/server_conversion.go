@@ -131,3 +131,9 @@ func Convert_v1alpha2_ServerSpec_To_v1alpha1_ServerSpec(in *metalv1alpha2.Server
 func Convert_v1alpha2_SystemInformation_To_v1alpha1_SystemInformation(in *metalv1alpha2.SystemInformation, out *SystemInformation, s apiconversion.Scope) error {
 	return autoConvert_v1alpha2_SystemInformation_To_v1alpha1_SystemInformation(in, out, s)
 }
+
+// Convert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus converts from the Hub version (v1alpha2).
+func Convert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus(in *metalv1alpha2.ServerStatus, out *ServerStatus, s apiconversion.Scope) error {
+	// WipeProgress, HardwareValidation and LastAllocated are not supported in v1alpha1, they are preserved via annotations.
+	return autoConvert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus(in, out, s)
+}
@@ -124,3 +124,9 @@ func Convert_v1alpha2_Qualifiers_To_v1alpha1_Qualifiers(in *metalv1alpha2.Qualif
 
 	return nil
 }
+
+// Convert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus converts from the Hub version (v1alpha2).
+func Convert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus(in *metalv1alpha2.ServerClassStatus, out *ServerClassStatus, s apiconversion.Scope) error {
+	// ServersReserved is not supported in v1alpha1, it is preserved via annotations.
+	return autoConvert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus(in, out, s)
+}
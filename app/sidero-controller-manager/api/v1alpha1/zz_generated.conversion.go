@@ -203,11 +203,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha2.ServerClassStatus)(nil), (*ServerClassStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus(a.(*v1alpha2.ServerClassStatus), b.(*ServerClassStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ServerList)(nil), (*v1alpha2.ServerList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ServerList_To_v1alpha2_ServerList(a.(*ServerList), b.(*v1alpha2.ServerList), scope)
 	}); err != nil {
@@ -223,11 +218,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1alpha2.ServerStatus)(nil), (*ServerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus(a.(*v1alpha2.ServerStatus), b.(*ServerStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*SystemInformation)(nil), (*v1alpha2.SystemInformation)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SystemInformation_To_v1alpha2_SystemInformation(a.(*SystemInformation), b.(*v1alpha2.SystemInformation), scope)
 	}); err != nil {
@@ -248,11 +238,21 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha2.ServerClassStatus)(nil), (*ServerClassStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus(a.(*v1alpha2.ServerClassStatus), b.(*ServerClassStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha2.ServerSpec)(nil), (*ServerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ServerSpec_To_v1alpha1_ServerSpec(a.(*v1alpha2.ServerSpec), b.(*ServerSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1alpha2.ServerStatus)(nil), (*ServerStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus(a.(*v1alpha2.ServerStatus), b.(*ServerStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1alpha2.SystemInformation)(nil), (*SystemInformation)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_SystemInformation_To_v1alpha1_SystemInformation(a.(*v1alpha2.SystemInformation), b.(*SystemInformation), scope)
 	}); err != nil {
@@ -303,6 +303,8 @@ func autoConvert_v1alpha2_AssetCondition_To_v1alpha1_AssetCondition(in *v1alpha2
 	}
 	out.Status = in.Status
 	out.Type = in.Type
+	// INFO: in.Reason opted out of conversion generation
+	// INFO: in.Message opted out of conversion generation
 	return nil
 }
 
@@ -421,7 +423,17 @@ func Convert_v1alpha2_Environment_To_v1alpha1_Environment(in *v1alpha2.Environme
 
 func autoConvert_v1alpha1_EnvironmentList_To_v1alpha2_EnvironmentList(in *EnvironmentList, out *v1alpha2.EnvironmentList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1alpha2.Environment)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1alpha2.Environment, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_Environment_To_v1alpha2_Environment(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -432,7 +444,17 @@ func Convert_v1alpha1_EnvironmentList_To_v1alpha2_EnvironmentList(in *Environmen
 
 func autoConvert_v1alpha2_EnvironmentList_To_v1alpha1_EnvironmentList(in *v1alpha2.EnvironmentList, out *EnvironmentList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]Environment)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Environment, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_Environment_To_v1alpha1_Environment(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -463,6 +485,9 @@ func autoConvert_v1alpha2_EnvironmentSpec_To_v1alpha1_EnvironmentSpec(in *v1alph
 	if err := Convert_v1alpha2_Initrd_To_v1alpha1_Initrd(&in.Initrd, &out.Initrd, s); err != nil {
 		return err
 	}
+	// INFO: in.UKI opted out of conversion generation
+	// INFO: in.Sources opted out of conversion generation
+	// INFO: in.ImageFactory opted out of conversion generation
 	return nil
 }
 
@@ -472,7 +497,17 @@ func Convert_v1alpha2_EnvironmentSpec_To_v1alpha1_EnvironmentSpec(in *v1alpha2.E
 }
 
 func autoConvert_v1alpha1_EnvironmentStatus_To_v1alpha2_EnvironmentStatus(in *EnvironmentStatus, out *v1alpha2.EnvironmentStatus, s conversion.Scope) error {
-	out.Conditions = *(*[]v1alpha2.AssetCondition)(unsafe.Pointer(&in.Conditions))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1alpha2.AssetCondition, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_AssetCondition_To_v1alpha2_AssetCondition(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Conditions = nil
+	}
 	return nil
 }
 
@@ -482,7 +517,18 @@ func Convert_v1alpha1_EnvironmentStatus_To_v1alpha2_EnvironmentStatus(in *Enviro
 }
 
 func autoConvert_v1alpha2_EnvironmentStatus_To_v1alpha1_EnvironmentStatus(in *v1alpha2.EnvironmentStatus, out *EnvironmentStatus, s conversion.Scope) error {
-	out.Conditions = *(*[]AssetCondition)(unsafe.Pointer(&in.Conditions))
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]AssetCondition, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_AssetCondition_To_v1alpha1_AssetCondition(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Conditions = nil
+	}
+	// INFO: in.Replicas opted out of conversion generation
 	return nil
 }
 
@@ -571,6 +617,7 @@ func autoConvert_v1alpha1_Qualifiers_To_v1alpha2_Qualifiers(in *Qualifiers, out
 func autoConvert_v1alpha2_Qualifiers_To_v1alpha1_Qualifiers(in *v1alpha2.Qualifiers, out *Qualifiers, s conversion.Scope) error {
 	// WARNING: in.Hardware requires manual conversion: does not exist in peer-type
 	out.LabelSelectors = *(*[]map[string]string)(unsafe.Pointer(&in.LabelSelectors))
+	// WARNING: in.Expressions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -728,7 +775,13 @@ func autoConvert_v1alpha2_ServerClassSpec_To_v1alpha1_ServerClassSpec(in *v1alph
 	out.Selector = in.Selector
 	out.ConfigPatches = *(*[]ConfigPatches)(unsafe.Pointer(&in.ConfigPatches))
 	// INFO: in.StrategicPatches opted out of conversion generation
+	// INFO: in.ConfigPatchRefs opted out of conversion generation
+	// INFO: in.PatchTemplating opted out of conversion generation
 	out.BootFromDiskMethod = types.BootFromDisk(in.BootFromDiskMethod)
+	// INFO: in.WipePolicy opted out of conversion generation
+	// INFO: in.InstallDisk opted out of conversion generation
+	// INFO: in.Validation opted out of conversion generation
+	// INFO: in.AllocationStrategy opted out of conversion generation
 	return nil
 }
 
@@ -751,14 +804,10 @@ func Convert_v1alpha1_ServerClassStatus_To_v1alpha2_ServerClassStatus(in *Server
 func autoConvert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus(in *v1alpha2.ServerClassStatus, out *ServerClassStatus, s conversion.Scope) error {
 	out.ServersAvailable = *(*[]string)(unsafe.Pointer(&in.ServersAvailable))
 	out.ServersInUse = *(*[]string)(unsafe.Pointer(&in.ServersInUse))
+	// WARNING: in.ServersReserved requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus is an autogenerated conversion function.
-func Convert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus(in *v1alpha2.ServerClassStatus, out *ServerClassStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha2_ServerClassStatus_To_v1alpha1_ServerClassStatus(in, out, s)
-}
-
 func autoConvert_v1alpha1_ServerList_To_v1alpha2_ServerList(in *ServerList, out *v1alpha2.ServerList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
@@ -822,14 +871,21 @@ func autoConvert_v1alpha2_ServerSpec_To_v1alpha1_ServerSpec(in *v1alpha2.ServerS
 	// WARNING: in.Hardware requires manual conversion: does not exist in peer-type
 	out.Hostname = in.Hostname
 	out.BMC = (*BMC)(unsafe.Pointer(in.BMC))
+	// WARNING: in.Redfish requires manual conversion: does not exist in peer-type
 	out.ManagementAPI = (*ManagementAPI)(unsafe.Pointer(in.ManagementAPI))
 	out.ConfigPatches = *(*[]ConfigPatches)(unsafe.Pointer(&in.ConfigPatches))
 	// WARNING: in.StrategicPatches requires manual conversion: does not exist in peer-type
+	// WARNING: in.ConfigPatchRefs requires manual conversion: does not exist in peer-type
+	// WARNING: in.PatchTemplating requires manual conversion: does not exist in peer-type
 	out.Accepted = in.Accepted
 	out.Cordoned = in.Cordoned
 	out.PXEBootAlways = in.PXEBootAlways
 	out.BootFromDiskMethod = types.BootFromDisk(in.BootFromDiskMethod)
 	out.PXEMode = types.PXEMode(in.PXEMode)
+	// WARNING: in.WipePolicy requires manual conversion: does not exist in peer-type
+	// WARNING: in.InstallDisk requires manual conversion: does not exist in peer-type
+	// WARNING: in.Validation requires manual conversion: does not exist in peer-type
+	// WARNING: in.ReservedFor requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -855,14 +911,12 @@ func autoConvert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus(in *v1alpha2.Ser
 	out.Conditions = *(*[]v1beta1.Condition)(unsafe.Pointer(&in.Conditions))
 	out.Addresses = *(*[]v1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.Power = in.Power
+	// WARNING: in.WipeProgress requires manual conversion: does not exist in peer-type
+	// WARNING: in.HardwareValidation requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastAllocated requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus is an autogenerated conversion function.
-func Convert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus(in *v1alpha2.ServerStatus, out *ServerStatus, s conversion.Scope) error {
-	return autoConvert_v1alpha2_ServerStatus_To_v1alpha1_ServerStatus(in, out, s)
-}
-
 func autoConvert_v1alpha1_SystemInformation_To_v1alpha2_SystemInformation(in *SystemInformation, out *v1alpha2.SystemInformation, s conversion.Scope) error {
 	out.Manufacturer = in.Manufacturer
 	out.ProductName = in.ProductName
@@ -0,0 +1,233 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// AllocationStrategyType is the way servers are picked from the server class.
+type AllocationStrategyType string
+
+// Allocation strategies.
+const (
+	// AllocationStrategyFirst picks the first available server sorted by name.
+	AllocationStrategyFirst AllocationStrategyType = "first"
+	// AllocationStrategySpread picks the server from the failure domain with the least servers allocated to the cluster.
+	AllocationStrategySpread AllocationStrategyType = "spread"
+	// AllocationStrategyPack picks the server from the failure domain with the most servers allocated to the cluster.
+	AllocationStrategyPack AllocationStrategyType = "pack"
+	// AllocationStrategySmallestFit picks the server with the least CPU cores, memory and storage.
+	AllocationStrategySmallestFit AllocationStrategyType = "smallest-fit"
+	// AllocationStrategyLeastRecentlyUsed picks the server which was allocated least recently.
+	AllocationStrategyLeastRecentlyUsed AllocationStrategyType = "least-recently-used"
+	// AllocationStrategyRandom picks a random server.
+	AllocationStrategyRandom AllocationStrategyType = "random"
+)
+
+// AllocationStrategy specifies how the servers are picked from the server class for the metal machines.
+type AllocationStrategy struct {
+	// Type of the allocation strategy.
+	//
+	// If not set, the first available server (sorted by name) is picked.
+	// +kubebuilder:validation:Enum=first;spread;pack;smallest-fit;least-recently-used;random
+	// +optional
+	Type AllocationStrategyType `json:"type,omitempty"`
+	// TopologyKey is the Server label which groups servers into failure domains (e.g. a rack), required for spread and pack strategies.
+	//
+	// Servers without the label are considered to be in the same failure domain.
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+}
+
+// Validate the allocation strategy.
+func (strategy *AllocationStrategy) Validate(fldPath *field.Path) (allErrs field.ErrorList) {
+	if strategy == nil {
+		return nil
+	}
+
+	switch strategy.Type {
+	case "", AllocationStrategyFirst, AllocationStrategySmallestFit, AllocationStrategyLeastRecentlyUsed, AllocationStrategyRandom:
+	case AllocationStrategySpread, AllocationStrategyPack:
+		if strategy.TopologyKey == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Child("topologyKey"), fmt.Sprintf("topology key is required for the %s strategy", strategy.Type)))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), strategy.Type, []string{
+			string(AllocationStrategyFirst),
+			string(AllocationStrategySpread),
+			string(AllocationStrategyPack),
+			string(AllocationStrategySmallestFit),
+			string(AllocationStrategyLeastRecentlyUsed),
+			string(AllocationStrategyRandom),
+		}))
+	}
+
+	return allErrs
+}
+
+// AllocationCandidate is a server which can be allocated with the reason of its preference.
+//
+// +kubebuilder:object:generate=false
+type AllocationCandidate struct {
+	Server Server
+	Reason string
+}
+
+// allocationOrder orders the candidates (sorted by name) from the most preferred, allocated are the servers already allocated to the cluster.
+type allocationOrder func(strategy *AllocationStrategy, candidates []AllocationCandidate, allocated []Server)
+
+var allocationOrders = map[AllocationStrategyType]allocationOrder{
+	AllocationStrategyFirst:             orderFirst,
+	AllocationStrategySpread:            orderByTopology(false),
+	AllocationStrategyPack:              orderByTopology(true),
+	AllocationStrategySmallestFit:       orderSmallestFit,
+	AllocationStrategyLeastRecentlyUsed: orderLeastRecentlyUsed,
+	AllocationStrategyRandom:            orderRandom,
+}
+
+// StrategyType returns the allocation strategy type with the default applied.
+func (strategy *AllocationStrategy) StrategyType() AllocationStrategyType {
+	if strategy == nil || strategy.Type == "" {
+		return AllocationStrategyFirst
+	}
+
+	return strategy.Type
+}
+
+// OrderServers returns the servers which can be allocated from the most preferred one according to the strategy.
+//
+// Servers already allocated to the same cluster are used by the topology-aware strategies.
+func (strategy *AllocationStrategy) OrderServers(servers, allocated []Server) []AllocationCandidate {
+	candidates := make([]AllocationCandidate, 0, len(servers))
+
+	for _, server := range servers {
+		candidates = append(candidates, AllocationCandidate{Server: server})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b AllocationCandidate) int { return cmp.Compare(a.Server.Name, b.Server.Name) })
+
+	order, ok := allocationOrders[strategy.StrategyType()]
+	if !ok {
+		order = orderFirst
+	}
+
+	order(strategy, candidates, allocated)
+
+	return candidates
+}
+
+func orderFirst(_ *AllocationStrategy, candidates []AllocationCandidate, _ []Server) {
+	for i := range candidates {
+		candidates[i].Reason = "first available server"
+	}
+}
+
+func orderByTopology(pack bool) allocationOrder {
+	return func(strategy *AllocationStrategy, candidates []AllocationCandidate, allocated []Server) {
+		domainCount := map[string]int{}
+
+		for _, server := range allocated {
+			domainCount[server.Labels[strategy.TopologyKey]]++
+		}
+
+		for i := range candidates {
+			domain := candidates[i].Server.Labels[strategy.TopologyKey]
+
+			candidates[i].Reason = fmt.Sprintf("%s=%q has %d server(s) allocated to the cluster", strategy.TopologyKey, domain, domainCount[domain])
+		}
+
+		slices.SortStableFunc(candidates, func(a, b AllocationCandidate) int {
+			countA, countB := domainCount[a.Server.Labels[strategy.TopologyKey]], domainCount[b.Server.Labels[strategy.TopologyKey]]
+
+			if pack {
+				return cmp.Compare(countB, countA)
+			}
+
+			return cmp.Compare(countA, countB)
+		})
+	}
+}
+
+// hardwareSize returns the CPU core count, memory and storage size (GB) of the server.
+func hardwareSize(server *Server) (cores, memory, storage int64) {
+	hw := server.Spec.Hardware
+	if hw == nil {
+		return 0, 0, 0
+	}
+
+	if hw.Compute != nil {
+		cores = int64(hw.Compute.TotalCoreCount)
+	}
+
+	// sizes which can't be parsed are treated as zero
+	if hw.Memory != nil {
+		memory, _ = ParseGigabytes(hw.Memory.TotalSize) //nolint:errcheck
+	}
+
+	if hw.Storage != nil {
+		storage, _ = ParseGigabytes(hw.Storage.TotalSize) //nolint:errcheck
+	}
+
+	return cores, memory, storage
+}
+
+func orderSmallestFit(_ *AllocationStrategy, candidates []AllocationCandidate, _ []Server) {
+	for i := range candidates {
+		cores, memory, storage := hardwareSize(&candidates[i].Server)
+
+		candidates[i].Reason = fmt.Sprintf("smallest hardware: %d cores, %d GB memory, %d GB storage", cores, memory, storage)
+	}
+
+	slices.SortStableFunc(candidates, func(a, b AllocationCandidate) int {
+		coresA, memoryA, storageA := hardwareSize(&a.Server)
+		coresB, memoryB, storageB := hardwareSize(&b.Server)
+
+		return cmp.Or(
+			cmp.Compare(coresA, coresB),
+			cmp.Compare(memoryA, memoryB),
+			cmp.Compare(storageA, storageB),
+		)
+	})
+}
+
+func orderLeastRecentlyUsed(_ *AllocationStrategy, candidates []AllocationCandidate, _ []Server) {
+	for i := range candidates {
+		if lastAllocated := candidates[i].Server.Status.LastAllocated; lastAllocated != nil {
+			candidates[i].Reason = fmt.Sprintf("least recently used, last allocated at %s", lastAllocated.UTC().Format("2006-01-02T15:04:05Z"))
+		} else {
+			candidates[i].Reason = "least recently used, never allocated"
+		}
+	}
+
+	// servers never allocated go first
+	slices.SortStableFunc(candidates, func(a, b AllocationCandidate) int {
+		lastA, lastB := a.Server.Status.LastAllocated, b.Server.Status.LastAllocated
+
+		switch {
+		case lastA == nil && lastB == nil:
+			return 0
+		case lastA == nil:
+			return -1
+		case lastB == nil:
+			return 1
+		default:
+			return lastA.Time.Compare(lastB.Time)
+		}
+	})
+}
+
+func orderRandom(_ *AllocationStrategy, candidates []AllocationCandidate, _ []Server) {
+	rand.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+
+	for i := range candidates {
+		candidates[i].Reason = "picked randomly"
+	}
+}
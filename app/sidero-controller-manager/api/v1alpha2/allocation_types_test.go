@@ -0,0 +1,118 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestAllocationStrategyOrderServers(t *testing.T) {
+	server := func(name, rack string, cores uint32, memory string, lastAllocated *metav1.Time) metal.Server {
+		return metal.Server{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"rack": rack},
+			},
+			Spec: metal.ServerSpec{
+				Hardware: &metal.HardwareInformation{
+					Compute: &metal.ComputeInformation{TotalCoreCount: cores},
+					Memory:  &metal.MemoryInformation{TotalSize: memory},
+				},
+			},
+			Status: metal.ServerStatus{
+				LastAllocated: lastAllocated,
+			},
+		}
+	}
+
+	now := time.Now()
+
+	servers := []metal.Server{
+		server("d", "rack-2", 32, "128 GB", &metav1.Time{Time: now}),
+		server("c", "rack-1", 16, "128 GB", nil),
+		server("b", "rack-1", 16, "64 GB", &metav1.Time{Time: now.Add(-time.Hour)}),
+		server("a", "rack-3", 64, "512 GB", &metav1.Time{Time: now.Add(-2 * time.Hour)}),
+	}
+
+	allocated := []metal.Server{
+		server("x", "rack-1", 16, "64 GB", nil),
+		server("y", "rack-1", 16, "64 GB", nil),
+		server("z", "rack-3", 16, "64 GB", nil),
+	}
+
+	names := func(candidates []metal.AllocationCandidate) []string {
+		result := make([]string, 0, len(candidates))
+
+		for _, candidate := range candidates {
+			result = append(result, candidate.Server.Name)
+		}
+
+		return result
+	}
+
+	for _, tt := range []struct {
+		name     string
+		strategy *metal.AllocationStrategy
+		expected []string
+		reason   string
+	}{
+		{
+			name:     "default",
+			expected: []string{"a", "b", "c", "d"},
+			reason:   "first available server",
+		},
+		{
+			name:     "spread",
+			strategy: &metal.AllocationStrategy{Type: metal.AllocationStrategySpread, TopologyKey: "rack"},
+			expected: []string{"d", "a", "b", "c"},
+			reason:   `rack="rack-2" has 0 server(s) allocated to the cluster`,
+		},
+		{
+			name:     "pack",
+			strategy: &metal.AllocationStrategy{Type: metal.AllocationStrategyPack, TopologyKey: "rack"},
+			expected: []string{"b", "c", "a", "d"},
+			reason:   `rack="rack-1" has 2 server(s) allocated to the cluster`,
+		},
+		{
+			name:     "smallest-fit",
+			strategy: &metal.AllocationStrategy{Type: metal.AllocationStrategySmallestFit},
+			expected: []string{"b", "c", "d", "a"},
+			reason:   "smallest hardware: 16 cores, 64 GB memory, 0 GB storage",
+		},
+		{
+			name:     "least-recently-used",
+			strategy: &metal.AllocationStrategy{Type: metal.AllocationStrategyLeastRecentlyUsed},
+			expected: []string{"c", "a", "b", "d"},
+			reason:   "least recently used, never allocated",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := tt.strategy.OrderServers(servers, allocated)
+
+			assert.Equal(t, tt.expected, names(candidates))
+			assert.Equal(t, tt.reason, candidates[0].Reason)
+		})
+	}
+
+	candidates := (&metal.AllocationStrategy{Type: metal.AllocationStrategyRandom}).OrderServers(servers, nil)
+	assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, names(candidates))
+}
+
+func TestAllocationStrategyValidate(t *testing.T) {
+	path := field.NewPath("spec", "allocationStrategy")
+
+	assert.Empty(t, (*metal.AllocationStrategy)(nil).Validate(path))
+	assert.Empty(t, (&metal.AllocationStrategy{Type: metal.AllocationStrategySpread, TopologyKey: "rack"}).Validate(path))
+	assert.Len(t, (&metal.AllocationStrategy{Type: metal.AllocationStrategyPack}).Validate(path), 1)
+	assert.Len(t, (&metal.AllocationStrategy{Type: "best"}).Validate(path), 1)
+}
@@ -0,0 +1,326 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// DefaultDHCPLeaseTime is used if the lease time is not set in the DHCP pool.
+const DefaultDHCPLeaseTime = time.Hour
+
+// ErrDHCPPoolExhausted is returned when there are no free addresses left in the DHCP pool.
+var ErrDHCPPoolExhausted = errors.New("no free addresses left in the pool")
+
+// DHCPPoolSpec defines the desired state of DHCPPool.
+type DHCPPoolSpec struct {
+	// Subnet is the network the addresses are leased from in CIDR notation, e.g. `172.16.0.0/24`.
+	Subnet string `json:"subnet"`
+	// RangeStart is the first address of the subnet which is leased to the clients.
+	RangeStart string `json:"rangeStart"`
+	// RangeEnd is the last address of the subnet which is leased to the clients.
+	RangeEnd string `json:"rangeEnd"`
+	// Gateway is the default gateway address sent to the clients.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+	// DNSServers is the list of DNS servers sent to the clients.
+	// +optional
+	DNSServers []string `json:"dnsServers,omitempty"`
+	// LeaseTime is the duration of the dynamic leases, defaults to 1h.
+	// +optional
+	LeaseTime *metav1.Duration `json:"leaseTime,omitempty"`
+}
+
+// DHCPLease is an address leased from the pool.
+type DHCPLease struct {
+	// MAC is the hardware address of the client.
+	MAC string `json:"mac"`
+	// IP is the leased address.
+	IP string `json:"ip"`
+	// Hostname is the hostname reported by the client.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// Server is the name of the Server the MAC address belongs to.
+	//
+	// Leases of the Servers are static: they don't expire and the address is kept until the Server is deleted.
+	//
+	// +optional
+	Server string `json:"server,omitempty"`
+	// ExpiresAt is the time when the lease expires.
+	ExpiresAt metav1.Time `json:"expiresAt"`
+}
+
+// Static returns true if the lease is a static reservation for the server.
+func (lease *DHCPLease) Static() bool {
+	return lease.Server != ""
+}
+
+// Active returns true if the lease address is not available to other clients.
+func (lease *DHCPLease) Active(now time.Time) bool {
+	return lease.Static() || now.Before(lease.ExpiresAt.Time)
+}
+
+// DHCPPoolStatus defines the observed state of DHCPPool.
+type DHCPPoolStatus struct {
+	// Leases is the list of the addresses leased from the pool.
+	// +optional
+	Leases []DHCPLease `json:"leases,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Subnet",type="string",JSONPath=".spec.subnet",description="leased subnet"
+// +kubebuilder:printcolumn:name="Range Start",type="string",JSONPath=".spec.rangeStart",description="first leased address"
+// +kubebuilder:printcolumn:name="Range End",type="string",JSONPath=".spec.rangeEnd",description="last leased address"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of this resource"
+// +kubebuilder:storageversion
+
+// DHCPPool is the Schema for the dhcppools API.
+//
+// DHCPPool defines the addresses leased by the authoritative DHCP server.
+type DHCPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DHCPPoolSpec   `json:"spec,omitempty"`
+	Status DHCPPoolStatus `json:"status,omitempty"`
+}
+
+// Validate the pool spec.
+func (spec *DHCPPoolSpec) Validate(fldPath *field.Path) (allErrs field.ErrorList) {
+	subnet, err := netip.ParsePrefix(spec.Subnet)
+	if err != nil || !subnet.Addr().Is4() {
+		return append(allErrs, field.Invalid(fldPath.Child("subnet"), spec.Subnet, "should be an IPv4 subnet in CIDR notation"))
+	}
+
+	inSubnet := func(path *field.Path, value string, required bool) (netip.Addr, bool) {
+		if value == "" && !required {
+			return netip.Addr{}, true
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil || !subnet.Contains(addr) {
+			allErrs = append(allErrs, field.Invalid(path, value, fmt.Sprintf("should be an address in the subnet %s", subnet)))
+
+			return addr, false
+		}
+
+		return addr, true
+	}
+
+	rangeStart, startOk := inSubnet(fldPath.Child("rangeStart"), spec.RangeStart, true)
+	rangeEnd, endOk := inSubnet(fldPath.Child("rangeEnd"), spec.RangeEnd, true)
+
+	if startOk && endOk && rangeEnd.Less(rangeStart) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("rangeEnd"), spec.RangeEnd, "should not be lower than rangeStart"))
+	}
+
+	inSubnet(fldPath.Child("gateway"), spec.Gateway, false)
+
+	for i, dns := range spec.DNSServers {
+		if _, err := netip.ParseAddr(dns); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("dnsServers").Index(i), dns, "should be an IP address"))
+		}
+	}
+
+	if spec.LeaseTime != nil && spec.LeaseTime.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("leaseTime"), spec.LeaseTime.Duration.String(), "should be positive"))
+	}
+
+	return allErrs
+}
+
+// Network returns the parsed subnet of the pool.
+func (pool *DHCPPool) Network() (*net.IPNet, error) {
+	_, network, err := net.ParseCIDR(pool.Spec.Subnet)
+
+	return network, err
+}
+
+// Contains returns true if the address belongs to the pool subnet.
+func (pool *DHCPPool) Contains(ip net.IP) bool {
+	network, err := pool.Network()
+	if err != nil {
+		return false
+	}
+
+	return network.Contains(ip)
+}
+
+// LeaseTime returns the duration of the dynamic leases.
+func (pool *DHCPPool) LeaseTime() time.Duration {
+	if pool.Spec.LeaseTime == nil {
+		return DefaultDHCPLeaseTime
+	}
+
+	return pool.Spec.LeaseTime.Duration
+}
+
+// Lease returns the lease of the MAC address, if any.
+func (pool *DHCPPool) Lease(mac string) *DHCPLease {
+	for i := range pool.Status.Leases {
+		if strings.EqualFold(pool.Status.Leases[i].MAC, mac) {
+			return &pool.Status.Leases[i]
+		}
+	}
+
+	return nil
+}
+
+// Allocate picks the address for the MAC address.
+//
+// The address leased to the MAC is preferred, then the requested address, then the first free address in the range.
+func (pool *DHCPPool) Allocate(mac string, requested net.IP, now time.Time) (net.IP, error) {
+	rangeStart, err := netip.ParseAddr(pool.Spec.RangeStart)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range start: %w", err)
+	}
+
+	rangeEnd, err := netip.ParseAddr(pool.Spec.RangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("invalid range end: %w", err)
+	}
+
+	if lease := pool.Lease(mac); lease != nil {
+		if addr, err := netip.ParseAddr(lease.IP); err == nil && pool.inRange(addr, rangeStart, rangeEnd) {
+			return net.IP(addr.AsSlice()), nil
+		}
+	}
+
+	taken := map[netip.Addr]struct{}{}
+
+	for _, lease := range pool.Status.Leases {
+		if strings.EqualFold(lease.MAC, mac) || !lease.Active(now) {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(lease.IP); err == nil {
+			taken[addr] = struct{}{}
+		}
+	}
+
+	free := func(addr netip.Addr) bool {
+		_, ok := taken[addr]
+
+		return !ok && pool.inRange(addr, rangeStart, rangeEnd)
+	}
+
+	if addr, ok := netip.AddrFromSlice(requested.To4()); ok && free(addr) {
+		return net.IP(addr.AsSlice()), nil
+	}
+
+	for addr := rangeStart; addr.IsValid() && !rangeEnd.Less(addr); addr = addr.Next() {
+		if free(addr) {
+			return net.IP(addr.AsSlice()), nil
+		}
+	}
+
+	return nil, ErrDHCPPoolExhausted
+}
+
+func (pool *DHCPPool) inRange(addr, rangeStart, rangeEnd netip.Addr) bool {
+	return !addr.Less(rangeStart) && !rangeEnd.Less(addr) && pool.Contains(net.IP(addr.AsSlice()))
+}
+
+// SetLease adds or replaces the lease of the MAC address.
+func (pool *DHCPPool) SetLease(lease DHCPLease) {
+	if existing := pool.Lease(lease.MAC); existing != nil {
+		*existing = lease
+
+		return
+	}
+
+	pool.Status.Leases = append(pool.Status.Leases, lease)
+
+	slices.SortFunc(pool.Status.Leases, func(a, b DHCPLease) int {
+		return strings.Compare(a.MAC, b.MAC)
+	})
+}
+
+// Release removes the dynamic lease of the MAC address, static leases are kept.
+func (pool *DHCPPool) Release(mac string) bool {
+	lease := pool.Lease(mac)
+	if lease == nil || lease.Static() {
+		return false
+	}
+
+	pool.Status.Leases = slices.DeleteFunc(pool.Status.Leases, func(l DHCPLease) bool {
+		return strings.EqualFold(l.MAC, mac)
+	})
+
+	return true
+}
+
+// ReconcileLeases removes the expired dynamic leases and updates the static leases.
+//
+// Argument servers maps lowercase MAC addresses to the names of the Servers.
+// The leases of the known MAC addresses become static, and the static leases of the deleted Servers are removed.
+// Returns true if the leases were changed.
+func (pool *DHCPPool) ReconcileLeases(servers map[string]string, now time.Time) bool {
+	changed := false
+
+	leases := pool.Status.Leases[:0]
+
+	for _, lease := range pool.Status.Leases {
+		server := servers[strings.ToLower(lease.MAC)]
+
+		if lease.Server != server {
+			lease.Server = server
+			changed = true
+		}
+
+		if !lease.Active(now) {
+			changed = true
+
+			continue
+		}
+
+		leases = append(leases, lease)
+	}
+
+	pool.Status.Leases = leases
+
+	return changed
+}
+
+// NextExpiration returns the time the first dynamic lease expires at, zero if there are no dynamic leases.
+func (pool *DHCPPool) NextExpiration() time.Time {
+	var next time.Time
+
+	for _, lease := range pool.Status.Leases {
+		if lease.Static() {
+			continue
+		}
+
+		if next.IsZero() || lease.ExpiresAt.Before(&metav1.Time{Time: next}) {
+			next = lease.ExpiresAt.Time
+		}
+	}
+
+	return next
+}
+
+// +kubebuilder:object:root=true
+
+// DHCPPoolList contains a list of DHCPPool.
+type DHCPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DHCPPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DHCPPool{}, &DHCPPoolList{})
+}
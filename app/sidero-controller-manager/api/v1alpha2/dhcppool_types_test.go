@@ -0,0 +1,148 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestDHCPPoolAllocate(t *testing.T) {
+	now := time.Now()
+
+	pool := metal.DHCPPool{
+		Spec: metal.DHCPPoolSpec{
+			Subnet:     "172.16.0.0/24",
+			RangeStart: "172.16.0.10",
+			RangeEnd:   "172.16.0.12",
+		},
+		Status: metal.DHCPPoolStatus{
+			Leases: []metal.DHCPLease{
+				{MAC: "00:00:00:00:00:01", IP: "172.16.0.10", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+				{MAC: "00:00:00:00:00:02", IP: "172.16.0.11", ExpiresAt: metav1.NewTime(now.Add(-time.Hour))},
+				{MAC: "00:00:00:00:00:03", IP: "172.16.0.12", Server: "server-3", ExpiresAt: metav1.NewTime(now.Add(-time.Hour))},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name      string
+		mac       string
+		requested string
+		expected  string
+	}{
+		{
+			name:     "existing lease",
+			mac:      "00:00:00:00:00:01",
+			expected: "172.16.0.10",
+		},
+		{
+			name:      "existing static lease",
+			mac:       "00:00:00:00:00:03",
+			requested: "172.16.0.11",
+			expected:  "172.16.0.12",
+		},
+		{
+			name:     "expired lease is reused",
+			mac:      "00:00:00:00:00:04",
+			expected: "172.16.0.11",
+		},
+		{
+			name:      "requested address is taken",
+			mac:       "00:00:00:00:00:04",
+			requested: "172.16.0.10",
+			expected:  "172.16.0.11",
+		},
+		{
+			name:      "requested address is out of range",
+			mac:       "00:00:00:00:00:04",
+			requested: "172.16.0.100",
+			expected:  "172.16.0.11",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, err := pool.Allocate(tt.mac, net.ParseIP(tt.requested), now)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, ip.String())
+		})
+	}
+
+	pool.SetLease(metal.DHCPLease{MAC: "00:00:00:00:00:04", IP: "172.16.0.11", ExpiresAt: metav1.NewTime(now.Add(time.Hour))})
+
+	_, err := pool.Allocate("00:00:00:00:00:05", nil, now)
+	assert.ErrorIs(t, err, metal.ErrDHCPPoolExhausted)
+
+	assert.True(t, pool.Release("00:00:00:00:00:04"))
+	assert.False(t, pool.Release("00:00:00:00:00:03"), "static leases are not released")
+
+	ip, err := pool.Allocate("00:00:00:00:00:05", nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, "172.16.0.11", ip.String())
+}
+
+func TestDHCPPoolReconcileLeases(t *testing.T) {
+	now := time.Now()
+
+	pool := metal.DHCPPool{
+		Status: metal.DHCPPoolStatus{
+			Leases: []metal.DHCPLease{
+				{MAC: "00:00:00:00:00:01", IP: "172.16.0.10", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+				{MAC: "00:00:00:00:00:02", IP: "172.16.0.11", ExpiresAt: metav1.NewTime(now.Add(-time.Hour))},
+				{MAC: "00:00:00:00:00:03", IP: "172.16.0.12", Server: "server-3", ExpiresAt: metav1.NewTime(now.Add(-time.Hour))},
+				{MAC: "00:00:00:00:00:04", IP: "172.16.0.13", ExpiresAt: metav1.NewTime(now.Add(-time.Hour))},
+			},
+		},
+	}
+
+	assert.True(t, pool.ReconcileLeases(map[string]string{"00:00:00:00:00:04": "server-4"}, now))
+
+	assert.Equal(t, []metal.DHCPLease{
+		{MAC: "00:00:00:00:00:01", IP: "172.16.0.10", ExpiresAt: metav1.NewTime(now.Add(time.Hour))},
+		{MAC: "00:00:00:00:00:04", IP: "172.16.0.13", Server: "server-4", ExpiresAt: metav1.NewTime(now.Add(-time.Hour))},
+	}, pool.Status.Leases)
+
+	assert.Equal(t, now.Add(time.Hour), pool.NextExpiration())
+
+	assert.False(t, pool.ReconcileLeases(map[string]string{"00:00:00:00:00:04": "server-4"}, now))
+}
+
+func TestDHCPPoolValidate(t *testing.T) {
+	path := field.NewPath("spec")
+
+	assert.Empty(t, (&metal.DHCPPoolSpec{
+		Subnet:     "172.16.0.0/24",
+		RangeStart: "172.16.0.10",
+		RangeEnd:   "172.16.0.20",
+		Gateway:    "172.16.0.1",
+		DNSServers: []string{"1.1.1.1"},
+	}).Validate(path))
+
+	assert.Len(t, (&metal.DHCPPoolSpec{Subnet: "fd00::/64"}).Validate(path), 1)
+
+	assert.Len(t, (&metal.DHCPPoolSpec{
+		Subnet:     "172.16.0.0/24",
+		RangeStart: "172.16.0.20",
+		RangeEnd:   "172.16.1.10",
+		Gateway:    "10.0.0.1",
+		DNSServers: []string{"dns"},
+		LeaseTime:  &metav1.Duration{},
+	}).Validate(path), 4)
+
+	assert.Len(t, (&metal.DHCPPoolSpec{
+		Subnet:     "172.16.0.0/24",
+		RangeStart: "172.16.0.20",
+		RangeEnd:   "172.16.0.10",
+	}).Validate(path), 1)
+}
@@ -6,21 +6,54 @@ package v1alpha2
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/siderolabs/talos/pkg/machinery/imager/quirks"
 	"github.com/siderolabs/talos/pkg/machinery/kernel"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // EnvironmentDefault is an automatically created Environment.
 const EnvironmentDefault = "default"
 
+// Asset is the file downloaded and served by Sidero.
 type Asset struct {
-	URL    string `json:"url,omitempty"`
+	// URL of the asset, the following schemes are supported:
+	//
+	//  - http://, https://
+	//  - file:///<path> reads the file under the asset file root of the controller manager
+	//  - oci://<registry>/<repository>:<tag> (or @<digest>) fetches the single layer of the OCI artifact,
+	//    oci://<registry>/<repository>:<tag>#<path> extracts the file from the image layers
+	//  - secret://<namespace>/<name>/<key>, configmap://<namespace>/<name>/<key> reads the Secret or ConfigMap key,
+	//    the namespace should be the controller manager one or allowed with the asset namespaces
+	URL string `json:"url,omitempty"`
+	// SHA512 is the hex-encoded SHA512 checksum of the asset.
+	//
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([0-9a-fA-F]{128})?$`
 	SHA512 string `json:"sha512,omitempty"`
 }
 
+// Validate checks the asset checksum.
+func (asset *Asset) Validate(path *field.Path) field.ErrorList {
+	if asset == nil || asset.SHA512 == "" {
+		return nil
+	}
+
+	if !sha512Regexp.MatchString(asset.SHA512) {
+		return field.ErrorList{field.Invalid(path.Child("sha512"), asset.SHA512, "should be a hex-encoded SHA512 checksum")}
+	}
+
+	return nil
+}
+
+var sha512Regexp = regexp.MustCompile(`^[0-9a-fA-F]{128}$`)
+
 type Kernel struct {
 	Asset `json:",inline"`
 
@@ -31,21 +64,234 @@ type Initrd struct {
 	Asset `json:",inline"`
 }
 
+// UKI is the unified kernel image booted by the UEFI HTTP boot clients without iPXE.
+//
+// If the trust bundle is configured, the UKI and the shim are marked Ready only if their signatures are valid.
+type UKI struct {
+	Asset `json:",inline"`
+
+	// Signature is the detached PKCS#7 signature (DER or PEM) of the UKI.
+	//
+	// If set, it is verified instead of the Authenticode signature embedded into the UKI.
+	//
+	// +optional
+	Signature *Asset `json:"signature,omitempty"`
+
+	// Certificate is the PEM-encoded certificate chain of the UKI signer.
+	//
+	// The certificates are used in addition to the ones included into the signature to build the chain to the trust bundle.
+	//
+	// +optional
+	Certificate *Asset `json:"certificate,omitempty"`
+
+	// Shim is the signed shim first-stage bootloader.
+	//
+	// If set, the shim is booted first and it loads the UKI as the second stage,
+	// so the UKI can be signed with the key enrolled into the shim instead of the UEFI db.
+	//
+	// +optional
+	Shim *Asset `json:"shim,omitempty"`
+}
+
+// AssetSources configures the access to the private asset sources.
+type AssetSources struct {
+	// RegistryCredentials references the Secret key with the OCI registry credentials
+	// in the Docker config format (e.g. the .dockerconfigjson key of the kubernetes.io/dockerconfigjson Secret).
+	//
+	// +optional
+	RegistryCredentials *SecretKeyRef `json:"registryCredentials,omitempty"`
+
+	// CABundle references the Secret key with the PEM-encoded CA certificates
+	// trusted by the HTTPS and OCI registry sources in addition to the system ones.
+	//
+	// +optional
+	CABundle *SecretKeyRef `json:"caBundle,omitempty"`
+}
+
+// ImageFactoryDefaultURL is the URL of the public Talos Image Factory.
+const ImageFactoryDefaultURL = "https://factory.talos.dev"
+
+// ImageFactorySource generates the Environment assets from the Talos Image Factory schematic.
+type ImageFactorySource struct {
+	// URL of the Image Factory, defaults to https://factory.talos.dev.
+	//
+	// +optional
+	URL string `json:"url,omitempty"`
+	// SchematicID is the ID of the schematic (the customization of the Talos image, e.g. the system extensions).
+	SchematicID string `json:"schematicID"`
+	// TalosVersion is the Talos version of the image (e.g. v1.7.0).
+	TalosVersion string `json:"talosVersion"`
+	// Arch is the architecture of the image, defaults to amd64.
+	//
+	// +optional
+	Arch string `json:"arch,omitempty"`
+	// ExtraKernelArgs are appended to the default kernel arguments for the Talos version.
+	//
+	// +optional
+	ExtraKernelArgs []string `json:"extraKernelArgs,omitempty"`
+}
+
+func (source *ImageFactorySource) assetURL(asset string) string {
+	factoryURL := source.URL
+	if factoryURL == "" {
+		factoryURL = ImageFactoryDefaultURL
+	}
+
+	arch := source.Arch
+	if arch == "" {
+		arch = "amd64"
+	}
+
+	return fmt.Sprintf("%s/image/%s/%s/%s-%s", strings.TrimRight(factoryURL, "/"), source.SchematicID, source.TalosVersion, asset, arch)
+}
+
+// KernelURL returns the URL of the kernel of the schematic.
+func (source *ImageFactorySource) KernelURL() string {
+	return source.assetURL("kernel")
+}
+
+// InitrdURL returns the URL of the initramfs of the schematic.
+func (source *ImageFactorySource) InitrdURL() string {
+	return source.assetURL("initramfs") + ".xz"
+}
+
+// Validate checks the Image Factory source.
+func (source *ImageFactorySource) Validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if source == nil {
+		return allErrs
+	}
+
+	if source.URL != "" {
+		if u, err := url.Parse(source.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			allErrs = append(allErrs, field.Invalid(path.Child("url"), source.URL, "should be an HTTP(S) URL"))
+		}
+	}
+
+	if !schematicIDRegexp.MatchString(source.SchematicID) {
+		allErrs = append(allErrs, field.Invalid(path.Child("schematicID"), source.SchematicID, "should be a hex-encoded SHA256 digest"))
+	}
+
+	if !strings.HasPrefix(source.TalosVersion, "v") || strings.ContainsAny(source.TalosVersion, "/ ") {
+		allErrs = append(allErrs, field.Invalid(path.Child("talosVersion"), source.TalosVersion, "should be a Talos version, e.g. v1.7.0"))
+	}
+
+	switch source.Arch {
+	case "", "amd64", "arm64":
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("arch"), source.Arch, []string{"amd64", "arm64"}))
+	}
+
+	return allErrs
+}
+
+var schematicIDRegexp = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
 // EnvironmentSpec defines the desired state of Environment.
 type EnvironmentSpec struct {
 	Kernel Kernel `json:"kernel,omitempty"`
 	Initrd Initrd `json:"initrd,omitempty"`
+	// UKI is served to the UEFI HTTP boot clients when native HTTP boot is enabled,
+	// and it is chainloaded by iPXE if the Kernel is not set.
+	//
+	// The kernel command line is embedded into the UKI, so Kernel.Args are not used to boot the UKI.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	UKI *UKI `json:"uki,omitempty"`
+	// Sources configures the access to the private asset sources.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	Sources *AssetSources `json:"sources,omitempty"`
+	// ImageFactory generates the Kernel and Initrd from the Talos Image Factory schematic.
+	//
+	// If set, the Kernel and Initrd URLs and the Kernel args are managed by the controller.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	ImageFactory *ImageFactorySource `json:"imageFactory,omitempty"`
 }
 
+// ApplyImageFactory sets the Kernel and Initrd URLs of the Image Factory schematic, and the default Kernel args for the Talos version.
+//
+// It returns true if the spec was changed.
+func (spec *EnvironmentSpec) ApplyImageFactory() bool {
+	source := spec.ImageFactory
+	if source == nil {
+		return false
+	}
+
+	args := DefaultKernelArgs(source.TalosVersion)
+	args = append(args, source.ExtraKernelArgs...)
+
+	changed := spec.Kernel.URL != source.KernelURL() || spec.Initrd.URL != source.InitrdURL() || !slices.Equal(spec.Kernel.Args, args)
+
+	spec.Kernel.Asset = Asset{URL: source.KernelURL()}
+	spec.Kernel.Args = args
+	spec.Initrd.Asset = Asset{URL: source.InitrdURL()}
+
+	return changed
+}
+
+// Asset condition types.
+const (
+	// AssetReady is True if the asset is downloaded (and verified), so it can be served.
+	AssetReady = "Ready"
+	// AssetVerified reports the result of the asset SHA512 checksum (and signature) verification.
+	AssetVerified = "Verified"
+	// AssetDownloadFailed is True if the last download of the asset failed.
+	AssetDownloadFailed = "DownloadFailed"
+)
+
+// Asset condition reasons.
+const (
+	AssetReasonDownloaded       = "Downloaded"
+	AssetReasonDownloadError    = "DownloadError"
+	AssetReasonChecksumMatched  = "ChecksumMatched"
+	AssetReasonChecksumMismatch = "ChecksumMismatch"
+	AssetReasonNoChecksum       = "NoChecksum"
+	AssetReasonSignatureInvalid = "SignatureInvalid"
+)
+
 type AssetCondition struct {
 	Asset  `json:",inline"`
 	Status string `json:"status"`
 	Type   string `json:"type"`
+	// Reason is a brief machine-readable reason of the condition status.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable description of the condition status.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	Message string `json:"message,omitempty"`
+}
+
+// ReplicaStatus is the state of the Environment assets on a controller manager replica.
+type ReplicaStatus struct {
+	// Name of the replica (the controller manager pod name).
+	Name string `json:"name"`
+	// Ready is true if all the assets are synced to the replica, so the replica can serve the Environment.
+	Ready bool `json:"ready"`
+	// ObservedGeneration is the Environment generation the assets were synced for.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // EnvironmentStatus defines the observed state of Environment.
 type EnvironmentStatus struct {
 	Conditions []AssetCondition `json:"conditions,omitempty"`
+	// Replicas is the state of the assets on each controller manager replica,
+	// as every replica serves the assets from its local copy.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -75,20 +321,25 @@ type EnvironmentList struct {
 	Items           []Environment `json:"items"`
 }
 
-// EnvironmentDefaultSpec returns EnvironmentDefault's spec.
-func EnvironmentDefaultSpec(talosRelease, apiEndpoint string, apiPort uint16) *EnvironmentSpec {
-	args := make([]string, 0, len(kernel.DefaultArgs(quirks.New(talosRelease)))+6)
+// DefaultKernelArgs returns the default kernel args to boot the Talos release.
+func DefaultKernelArgs(talosRelease string) []string {
+	args := make([]string, 0, len(kernel.DefaultArgs(quirks.New(talosRelease)))+5)
 	args = append(args, kernel.DefaultArgs(quirks.New(talosRelease))...)
 	args = append(args, "console=tty0", "console=ttyS0", "earlyprintk=ttyS0")
 	args = append(args, "initrd=initramfs.xz", "talos.platform=metal")
 	sort.Strings(args)
 
+	return args
+}
+
+// EnvironmentDefaultSpec returns EnvironmentDefault's spec.
+func EnvironmentDefaultSpec(talosRelease, apiEndpoint string, apiPort uint16) *EnvironmentSpec {
 	return &EnvironmentSpec{
 		Kernel: Kernel{
 			Asset: Asset{
 				URL: fmt.Sprintf("https://github.com/siderolabs/talos/releases/download/%s/vmlinuz-amd64", talosRelease),
 			},
-			Args: args,
+			Args: DefaultKernelArgs(talosRelease),
 		},
 		Initrd: Initrd{
 			Asset: Asset{
@@ -110,8 +361,20 @@ func (env *Environment) IsReady() bool {
 		assetURLs[env.Spec.Initrd.URL] = struct{}{}
 	}
 
+	if env.Spec.UKI != nil {
+		if env.Spec.UKI.URL != "" {
+			assetURLs[env.Spec.UKI.URL] = struct{}{}
+		}
+
+		for _, asset := range []*Asset{env.Spec.UKI.Signature, env.Spec.UKI.Certificate, env.Spec.UKI.Shim} {
+			if asset != nil && asset.URL != "" {
+				assetURLs[asset.URL] = struct{}{}
+			}
+		}
+	}
+
 	for _, cond := range env.Status.Conditions {
-		if cond.Status == "True" && cond.Type == "Ready" {
+		if cond.Status == "True" && cond.Type == AssetReady {
 			delete(assetURLs, cond.URL)
 		}
 	}
@@ -119,6 +382,27 @@ func (env *Environment) IsReady() bool {
 	return len(assetURLs) == 0
 }
 
+// IsReadyOn returns true if the Environment is ready, and its assets are synced to the replica.
+//
+// The replica check is skipped if the replica name is not set, or the Environment has no assets.
+func (env *Environment) IsReadyOn(replica string) bool {
+	if !env.IsReady() {
+		return false
+	}
+
+	if replica == "" || (env.Spec.Kernel.URL == "" && env.Spec.Initrd.URL == "" && (env.Spec.UKI == nil || env.Spec.UKI.URL == "")) {
+		return true
+	}
+
+	for _, status := range env.Status.Replicas {
+		if status.Name == replica {
+			return status.Ready && status.ObservedGeneration == env.Generation
+		}
+	}
+
+	return false
+}
+
 func init() {
 	SchemeBuilder.Register(&Environment{}, &EnvironmentList{})
 }
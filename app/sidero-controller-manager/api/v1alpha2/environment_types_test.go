@@ -0,0 +1,210 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestEnvironmentIsReadyOn(t *testing.T) {
+	kernel := metal.Asset{URL: "http://example.com/vmlinuz"}
+
+	newEnvironment := func(replicas ...metal.ReplicaStatus) *metal.Environment {
+		return &metal.Environment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: metal.EnvironmentSpec{
+				Kernel: metal.Kernel{Asset: kernel},
+			},
+			Status: metal.EnvironmentStatus{
+				Conditions: []metal.AssetCondition{{Asset: kernel, Type: metal.AssetReady, Status: "True"}},
+				Replicas:   replicas,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		env      *metal.Environment
+		replica  string
+		expected bool
+	}{
+		{
+			name:     "no replica",
+			env:      newEnvironment(),
+			expected: true,
+		},
+		{
+			name:     "replica missing",
+			env:      newEnvironment(metal.ReplicaStatus{Name: "other", Ready: true, ObservedGeneration: 2}),
+			replica:  "sidero-0",
+			expected: false,
+		},
+		{
+			name:     "replica ready",
+			env:      newEnvironment(metal.ReplicaStatus{Name: "sidero-0", Ready: true, ObservedGeneration: 2}),
+			replica:  "sidero-0",
+			expected: true,
+		},
+		{
+			name:     "replica not ready",
+			env:      newEnvironment(metal.ReplicaStatus{Name: "sidero-0", ObservedGeneration: 2}),
+			replica:  "sidero-0",
+			expected: false,
+		},
+		{
+			name:     "replica outdated",
+			env:      newEnvironment(metal.ReplicaStatus{Name: "sidero-0", Ready: true, ObservedGeneration: 1}),
+			replica:  "sidero-0",
+			expected: false,
+		},
+		{
+			name:     "no assets",
+			env:      &metal.Environment{},
+			replica:  "sidero-0",
+			expected: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.env.IsReadyOn(tt.replica))
+		})
+	}
+}
+
+func TestEnvironmentApplyImageFactory(t *testing.T) {
+	factory := newImageFactoryServer()
+
+	srv := httptest.NewServer(factory)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Post(srv.URL+"/schematics", "application/yaml", strings.NewReader("customization:\n  systemExtensions: {}\n"))
+	require.NoError(t, err)
+
+	var schematic struct {
+		ID string `json:"id"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&schematic))
+	require.NoError(t, resp.Body.Close())
+
+	spec := metal.EnvironmentSpec{
+		ImageFactory: &metal.ImageFactorySource{
+			URL:             srv.URL,
+			SchematicID:     schematic.ID,
+			TalosVersion:    "v1.7.0",
+			ExtraKernelArgs: []string{"console=ttyS1"},
+		},
+	}
+
+	assert.True(t, spec.ApplyImageFactory())
+	assert.False(t, spec.ApplyImageFactory())
+
+	assert.Equal(t, srv.URL+"/image/"+schematic.ID+"/v1.7.0/kernel-amd64", spec.Kernel.URL)
+	assert.Equal(t, srv.URL+"/image/"+schematic.ID+"/v1.7.0/initramfs-amd64.xz", spec.Initrd.URL)
+	assert.Equal(t, append(metal.DefaultKernelArgs("v1.7.0"), "console=ttyS1"), spec.Kernel.Args)
+
+	for _, tt := range []struct {
+		url      string
+		expected []byte
+	}{
+		{url: spec.Kernel.URL, expected: imageFactoryAsset("kernel", schematic.ID, "v1.7.0", "amd64")},
+		{url: spec.Initrd.URL, expected: imageFactoryAsset("initramfs", schematic.ID, "v1.7.0", "amd64")},
+	} {
+		resp, err := http.Get(tt.url)
+		require.NoError(t, err)
+
+		contents, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, tt.expected, contents)
+	}
+
+	// the generated assets follow the schematic changes
+	spec.ImageFactory.Arch = "arm64"
+	spec.ImageFactory.URL = ""
+
+	assert.True(t, spec.ApplyImageFactory())
+	assert.Equal(t, metal.ImageFactoryDefaultURL+"/image/"+schematic.ID+"/v1.7.0/kernel-arm64", spec.Kernel.URL)
+
+	resp, err = http.Get(srv.URL + "/image/" + strings.Repeat("0", 64) + "/v1.7.0/kernel-amd64")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestImageFactorySourceValidate(t *testing.T) {
+	schematicID := strings.Repeat("a", 64)
+
+	for _, tt := range []struct {
+		name   string
+		source *metal.ImageFactorySource
+		errors int
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:   "valid",
+			source: &metal.ImageFactorySource{URL: "https://factory.example.com", SchematicID: schematicID, TalosVersion: "v1.7.0", Arch: "arm64"},
+		},
+		{
+			name:   "invalid",
+			source: &metal.ImageFactorySource{URL: "ftp://factory.example.com", SchematicID: "abcd", TalosVersion: "1.7.0", Arch: "riscv64"},
+			errors: 4,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.source.Validate(field.NewPath("spec")), tt.errors)
+		})
+	}
+}
+
+func TestAssetValidate(t *testing.T) {
+	sum := sha512.Sum512([]byte("kernel"))
+
+	for _, tt := range []struct {
+		name   string
+		asset  *metal.Asset
+		errors int
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:  "no checksum",
+			asset: &metal.Asset{URL: "https://example.com/vmlinuz"},
+		},
+		{
+			name:  "valid",
+			asset: &metal.Asset{URL: "https://example.com/vmlinuz", SHA512: strings.ToUpper(hex.EncodeToString(sum[:]))},
+		},
+		{
+			name:   "path",
+			asset:  &metal.Asset{URL: "https://example.com/vmlinuz", SHA512: "../../../etc/shadow"},
+			errors: 1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.asset.Validate(field.NewPath("spec")), tt.errors)
+		})
+	}
+}
@@ -5,12 +5,21 @@
 package v1alpha2
 
 import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func (r *Environment) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(r).
 		Complete()
 }
 
@@ -19,3 +28,52 @@ func (r *EnvironmentList) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		For(r).
 		Complete()
 }
+
+//+kubebuilder:webhook:verbs=create;update,path=/validate-metal-sidero-dev-v1alpha2-environment,mutating=false,failurePolicy=fail,groups=metal.sidero.dev,resources=environments,versions=v1alpha2,name=venvironments.metal.sidero.dev,sideEffects=None,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &Environment{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *Environment) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r = obj.(*Environment)
+
+	return nil, r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *Environment) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	r = newObj.(*Environment)
+
+	return nil, r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *Environment) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *Environment) validate() error {
+	specPath := field.NewPath("spec")
+
+	allErrs := r.Spec.ImageFactory.Validate(specPath.Child("imageFactory"))
+
+	allErrs = append(allErrs, r.Spec.Kernel.Asset.Validate(specPath.Child("kernel"))...)
+	allErrs = append(allErrs, r.Spec.Initrd.Asset.Validate(specPath.Child("initrd"))...)
+
+	if uki := r.Spec.UKI; uki != nil {
+		ukiPath := specPath.Child("uki")
+
+		allErrs = append(allErrs, uki.Asset.Validate(ukiPath)...)
+		allErrs = append(allErrs, uki.Signature.Validate(ukiPath.Child("signature"))...)
+		allErrs = append(allErrs, uki.Certificate.Validate(ukiPath.Child("certificate"))...)
+		allErrs = append(allErrs, uki.Shim.Validate(ukiPath.Child("shim"))...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "Environment"},
+		r.Name, allErrs)
+}
@@ -0,0 +1,98 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// imageFactoryServer is the local stand-in for the Talos Image Factory,
+// it serves the kernel and initramfs of the registered schematics.
+//
+// The assets contents are generated from the schematic ID, the Talos version and the architecture, see imageFactoryAsset.
+type imageFactoryServer struct {
+	mu         sync.Mutex
+	schematics map[string]struct{}
+}
+
+func newImageFactoryServer() *imageFactoryServer {
+	return &imageFactoryServer{
+		schematics: map[string]struct{}{},
+	}
+}
+
+// AddSchematic registers the schematic and returns its ID.
+func (s *imageFactoryServer) AddSchematic(schematic []byte) string {
+	sum := sha256.Sum256(schematic)
+	id := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	s.schematics[id] = struct{}{}
+	s.mu.Unlock()
+
+	return id
+}
+
+// imageFactoryAsset returns the contents of the asset served by the stand-in.
+func imageFactoryAsset(name, schematicID, talosVersion, arch string) []byte {
+	return []byte(fmt.Sprintf("%s-%s %s %s", name, arch, schematicID, talosVersion))
+}
+
+// ServeHTTP implements http.Handler.
+//
+// It handles the schematic uploads (POST /schematics) and the kernel and initramfs downloads
+// (GET /image/<schematic>/<version>/kernel-<arch>, GET /image/<schematic>/<version>/initramfs-<arch>.xz).
+func (s *imageFactoryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost && r.URL.Path == "/schematics" {
+		schematic, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+
+		json.NewEncoder(w).Encode(map[string]string{"id": s.AddSchematic(schematic)}) //nolint:errcheck
+
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if r.Method != http.MethodGet || len(parts) != 4 || parts[0] != "image" {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	schematicID, talosVersion, file := parts[1], parts[2], parts[3]
+
+	s.mu.Lock()
+	_, ok := s.schematics[schematicID]
+	s.mu.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	name, arch, ok := strings.Cut(strings.TrimSuffix(file, ".xz"), "-")
+	if !ok || (arch != "amd64" && arch != "arm64") || (name != "kernel" && name != "initramfs") || (name == "initramfs") != strings.HasSuffix(file, ".xz") {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	w.Write(imageFactoryAsset(name, schematicID, talosVersion, arch)) //nolint:errcheck
+}
@@ -0,0 +1,134 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// InstallDiskSelector selects the Talos install disk from the storage devices in the server hardware information.
+//
+// All set fields should match for the disk to be selected.
+// If several disks match, the smallest one is selected.
+type InstallDiskSelector struct {
+	// MinSize is the minimum size of the disk, e.g. 100Gi.
+	// +optional
+	MinSize *resource.Quantity `json:"minSize,omitempty"`
+	// MaxSize is the maximum size of the disk, e.g. 2Ti.
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+	// Type of the disk, as reported in the hardware information.
+	// Valid values: Unknown, SSD, HDD, NVMe, SD.
+	// +kubebuilder:validation:Enum=Unknown;SSD;HDD;NVMe;SD
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Model of the disk, supports shell glob patterns.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// WWID of the disk, supports shell glob patterns.
+	// +optional
+	WWID string `json:"wwid,omitempty"`
+	// NonRemovable skips removable, USB and SD card devices.
+	// +optional
+	NonRemovable bool `json:"nonRemovable,omitempty"`
+}
+
+// Validate the install disk selector.
+func (selector *InstallDiskSelector) Validate(fldPath *field.Path) (allErrs field.ErrorList) {
+	if selector == nil {
+		return nil
+	}
+
+	if selector.Type != "" && !slices.Contains(StorageTypes, selector.Type) {
+		allErrs = append(allErrs,
+			field.NotSupported(fldPath.Child("type"), selector.Type, StorageTypes),
+		)
+	}
+
+	if selector.MinSize != nil && selector.MaxSize != nil && selector.MinSize.Cmp(*selector.MaxSize) > 0 {
+		allErrs = append(allErrs,
+			field.Invalid(fldPath.Child("maxSize"), selector.MaxSize.String(), "maxSize should not be less than minSize"),
+		)
+	}
+
+	for _, pattern := range []struct {
+		name  string
+		value string
+	}{
+		{"model", selector.Model},
+		{"wwid", selector.WWID},
+	} {
+		if _, err := path.Match(pattern.value, ""); err != nil {
+			allErrs = append(allErrs,
+				field.Invalid(fldPath.Child(pattern.name), pattern.value, err.Error()),
+			)
+		}
+	}
+
+	return allErrs
+}
+
+// Match checks whether the storage device matches the selector.
+func (selector *InstallDiskSelector) Match(device *StorageDevice) bool {
+	if selector.MinSize != nil && selector.MinSize.CmpInt64(int64(device.Size)) > 0 {
+		return false
+	}
+
+	if selector.MaxSize != nil && selector.MaxSize.CmpInt64(int64(device.Size)) < 0 {
+		return false
+	}
+
+	if selector.Type != "" && selector.Type != device.Type {
+		return false
+	}
+
+	if selector.NonRemovable && device.IsRemovable() {
+		return false
+	}
+
+	return matchPatterns(
+		[2]string{selector.Model, device.Model},
+		[2]string{selector.WWID, device.WWID},
+	)
+}
+
+// Select returns the smallest storage device matching the selector.
+func (selector *InstallDiskSelector) Select(hw *HardwareInformation) (*StorageDevice, error) {
+	if hw == nil || hw.Storage == nil || len(hw.Storage.Devices) == 0 {
+		return nil, errors.New("no storage devices in the hardware information")
+	}
+
+	var candidates []*StorageDevice
+
+	for _, device := range hw.Storage.Devices {
+		if device == nil || device.DeviceName == "" {
+			continue
+		}
+
+		if selector.Match(device) {
+			candidates = append(candidates, device)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("none of %d storage devices matches the install disk selector", len(hw.Storage.Devices))
+	}
+
+	return slices.MinFunc(candidates, func(a, b *StorageDevice) int {
+		return cmp.Or(cmp.Compare(a.Size, b.Size), cmp.Compare(a.DeviceName, b.DeviceName))
+	}), nil
+}
+
+// IsRemovable checks whether the storage device looks like a removable media (USB stick, SD card).
+func (device *StorageDevice) IsRemovable() bool {
+	return device.Type == "SD" || device.Transport == "usb"
+}
@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"testing"
+
+	"github.com/siderolabs/go-pointer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestInstallDiskSelectorValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		selector *metal.InstallDiskSelector
+		expected int
+	}{
+		{
+			name: "not set",
+		},
+		{
+			name: "valid",
+			selector: &metal.InstallDiskSelector{
+				MinSize: pointer.To(resource.MustParse("100Gi")),
+				MaxSize: pointer.To(resource.MustParse("1Ti")),
+				Type:    "NVMe",
+				Model:   "Samsung*",
+			},
+		},
+		{
+			name: "invalid",
+			selector: &metal.InstallDiskSelector{
+				MinSize: pointer.To(resource.MustParse("1Ti")),
+				MaxSize: pointer.To(resource.MustParse("100Gi")),
+				Type:    "Tape",
+				WWID:    "[",
+			},
+			expected: 3,
+		},
+		{
+			name:     "type case",
+			selector: &metal.InstallDiskSelector{Type: "nvme"},
+			expected: 1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.selector.Validate(field.NewPath("spec").Child("installDisk")), tt.expected)
+		})
+	}
+}
+
+func TestInstallDiskSelectorSelect(t *testing.T) {
+	hw := &metal.HardwareInformation{
+		Storage: &metal.StorageInformation{
+			Devices: []*metal.StorageDevice{
+				{Type: "HDD", Size: 4_000_000_000_000, DeviceName: "/dev/sda", Model: "ST4000NM"},
+				{Type: "SSD", Size: 32_000_000_000, DeviceName: "/dev/sdb", Transport: "usb"},
+				{Type: "NVMe", Size: 512_000_000_000, DeviceName: "/dev/nvme0n1", WWID: "eui.0001"},
+				{Type: "NVMe", Size: 256_000_000_000, DeviceName: "/dev/nvme1n1", WWID: "eui.0002"},
+				{Type: "SD", Size: 16_000_000_000, DeviceName: "/dev/mmcblk0"},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		selector metal.InstallDiskSelector
+		expected string
+	}{
+		{
+			name:     "smallest",
+			expected: "/dev/mmcblk0",
+		},
+		{
+			name:     "smallest non-removable",
+			selector: metal.InstallDiskSelector{NonRemovable: true},
+			expected: "/dev/nvme1n1",
+		},
+		{
+			name:     "type and size",
+			selector: metal.InstallDiskSelector{Type: "NVMe", MinSize: pointer.To(resource.MustParse("300Gi"))},
+			expected: "/dev/nvme0n1",
+		},
+		{
+			name:     "max size",
+			selector: metal.InstallDiskSelector{MinSize: pointer.To(resource.MustParse("1T")), MaxSize: pointer.To(resource.MustParse("4T"))},
+			expected: "/dev/sda",
+		},
+		{
+			name:     "model",
+			selector: metal.InstallDiskSelector{Model: "ST*"},
+			expected: "/dev/sda",
+		},
+		{
+			name:     "wwid",
+			selector: metal.InstallDiskSelector{WWID: "eui.0001"},
+			expected: "/dev/nvme0n1",
+		},
+		{
+			name:     "not matching",
+			selector: metal.InstallDiskSelector{Type: "HDD", MaxSize: pointer.To(resource.MustParse("1T"))},
+		},
+		{
+			name:     "type case",
+			selector: metal.InstallDiskSelector{Type: "nvme"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			device, err := tt.selector.Select(hw)
+
+			if tt.expected == "" {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, device.DeviceName)
+		})
+	}
+
+	_, err := (&metal.InstallDiskSelector{}).Select(&metal.HardwareInformation{})
+	assert.EqualError(t, err, "no storage devices in the hardware information")
+}
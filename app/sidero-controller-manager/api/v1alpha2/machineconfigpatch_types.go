@@ -0,0 +1,115 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// MachineConfigPatchSpec defines the desired state of MachineConfigPatch.
+type MachineConfigPatchSpec struct {
+	// Priority defines the order the patches are applied in: the patches with the lower priority are applied first,
+	// so the patches with the higher priority override them.
+	//
+	// Patches with the same priority are applied in the order of their names.
+	//
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// Selector selects the Servers the patch is applied to by labels.
+	//
+	// If not set, the patch is applied only to the Servers which reference it directly,
+	// or via the ServerClass or the MetalMachine.
+	//
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// ConfigPatches are RFC 6902 JSON patches to apply to the machine configuration.
+	//
+	// +optional
+	ConfigPatches []ConfigPatches `json:"configPatches,omitempty"`
+	// StrategicPatches are Talos machine configuration strategic merge patches.
+	//
+	// +optional
+	StrategicPatches []string `json:"strategicPatches,omitempty"`
+}
+
+// Validate the patch spec.
+func (spec *MachineConfigPatchSpec) Validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.Selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("selector"), spec.Selector, err.Error()))
+		}
+	}
+
+	for i, patch := range spec.ConfigPatches {
+		if _, ok := operations[patch.Op]; !ok {
+			allErrs = append(allErrs, field.NotSupported(path.Child("configPatches").Index(i).Child("op"), patch.Op, operationKinds))
+		}
+	}
+
+	if len(spec.ConfigPatches) == 0 && len(spec.StrategicPatches) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("configPatches"), "either configPatches or strategicPatches should be set"))
+	}
+
+	return allErrs
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority",description="order the patch is applied in"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of this resource"
+// +kubebuilder:storageversion
+
+// MachineConfigPatch is the reusable set of the machine configuration patches.
+//
+// The patch is applied to the Servers selected by the label selector, and to the Servers which reference it
+// directly, or via the ServerClass or the MetalMachine.
+type MachineConfigPatch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MachineConfigPatchSpec `json:"spec,omitempty"`
+}
+
+// Selects checks whether the patch selector matches the server.
+func (patch *MachineConfigPatch) Selects(server *Server) (bool, error) {
+	if patch.Spec.Selector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(patch.Spec.Selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid selector of machine config patch %q: %w", patch.Name, err)
+	}
+
+	return selector.Matches(labels.Set(server.Labels)), nil
+}
+
+// SortMachineConfigPatches sorts the patches in the order they are applied: by priority, then by name.
+func SortMachineConfigPatches(patches []MachineConfigPatch) {
+	slices.SortFunc(patches, func(a, b MachineConfigPatch) int {
+		return cmp.Or(cmp.Compare(a.Spec.Priority, b.Spec.Priority), cmp.Compare(a.Name, b.Name))
+	})
+}
+
+// +kubebuilder:object:root=true
+
+// MachineConfigPatchList contains a list of MachineConfigPatch.
+type MachineConfigPatchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachineConfigPatch `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MachineConfigPatch{}, &MachineConfigPatchList{})
+}
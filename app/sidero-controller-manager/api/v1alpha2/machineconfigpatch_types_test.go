@@ -0,0 +1,99 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestMachineConfigPatchValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		spec     metal.MachineConfigPatchSpec
+		expected int
+	}{
+		{
+			name: "valid",
+			spec: metal.MachineConfigPatchSpec{
+				Selector:         &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "1"}},
+				ConfigPatches:    []metal.ConfigPatches{{Op: "remove", Path: "/machine/install/extraKernelArgs"}},
+				StrategicPatches: []string{"machine: {}"},
+			},
+		},
+		{
+			name:     "empty",
+			spec:     metal.MachineConfigPatchSpec{},
+			expected: 1,
+		},
+		{
+			name: "invalid",
+			spec: metal.MachineConfigPatchSpec{
+				Selector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "rack", Operator: "Near"}},
+				},
+				ConfigPatches: []metal.ConfigPatches{{Op: "merge", Path: "/machine"}},
+			},
+			expected: 2,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.spec.Validate(field.NewPath("spec")), tt.expected)
+		})
+	}
+}
+
+func TestMachineConfigPatchSelects(t *testing.T) {
+	server := &metal.Server{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"rack": "1"},
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		selector *metav1.LabelSelector
+		expected bool
+	}{
+		{name: "not set"},
+		{name: "empty", selector: &metav1.LabelSelector{}, expected: true},
+		{name: "matching", selector: &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "1"}}, expected: true},
+		{name: "not matching", selector: &metav1.LabelSelector{MatchLabels: map[string]string{"rack": "2"}}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := metal.MachineConfigPatch{Spec: metal.MachineConfigPatchSpec{Selector: tt.selector}}
+
+			selected, err := patch.Selects(server)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, selected)
+		})
+	}
+}
+
+func TestSortMachineConfigPatches(t *testing.T) {
+	patches := []metal.MachineConfigPatch{
+		{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: metal.MachineConfigPatchSpec{Priority: 10}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "c"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: metal.MachineConfigPatchSpec{Priority: 10}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "d"}, Spec: metal.MachineConfigPatchSpec{Priority: -5}},
+	}
+
+	metal.SortMachineConfigPatches(patches)
+
+	var names []string
+
+	for _, patch := range patches {
+		names = append(names, patch.Name)
+	}
+
+	assert.Equal(t, []string{"d", "c", "a", "b"}, names)
+}
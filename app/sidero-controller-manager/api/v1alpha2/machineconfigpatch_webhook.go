@@ -0,0 +1,59 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func (r *MachineConfigPatch) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:verbs=create;update,path=/validate-metal-sidero-dev-v1alpha2-machineconfigpatch,mutating=false,failurePolicy=fail,groups=metal.sidero.dev,resources=machineconfigpatches,versions=v1alpha2,name=vmachineconfigpatches.metal.sidero.dev,sideEffects=None,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &MachineConfigPatch{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *MachineConfigPatch) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r = obj.(*MachineConfigPatch)
+
+	return nil, r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *MachineConfigPatch) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	r = newObj.(*MachineConfigPatch)
+
+	return nil, r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *MachineConfigPatch) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *MachineConfigPatch) validate() error {
+	allErrs := r.Spec.Validate(field.NewPath("spec"))
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "MachineConfigPatch"},
+		r.Name, allErrs)
+}
@@ -0,0 +1,113 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// ServerReservation reserves the server for the specific MetalMachines.
+//
+// All specified conditions should match the MetalMachine for the server to be allocated to it.
+type ServerReservation struct {
+	// ClusterName reserves the server for the machines of the cluster.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+	// Namespace reserves the server for the machines in the namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Selector reserves the server for the machines matching the label selector,
+	// e.g. `cluster.x-k8s.io/deployment-name` label to reserve for a MachineDeployment.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// ExpiresAt is the time when the reservation expires, the reservation doesn't expire if not set.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// Validate the server reservation.
+func (reservation *ServerReservation) Validate(fldPath *field.Path) (allErrs field.ErrorList) {
+	if reservation == nil {
+		return nil
+	}
+
+	if reservation.ClusterName == "" && reservation.Namespace == "" && reservation.Selector == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of clusterName, namespace or selector should be set"))
+	}
+
+	if reservation.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(reservation.Selector); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("selector"), reservation.Selector, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// Active returns true if the reservation is set and not expired.
+func (reservation *ServerReservation) Active(now time.Time) bool {
+	return reservation != nil && (reservation.ExpiresAt == nil || now.Before(reservation.ExpiresAt.Time))
+}
+
+// Matches returns true if the machine with the namespace and labels holds the reservation.
+func (reservation *ServerReservation) Matches(namespace string, machineLabels map[string]string) (bool, error) {
+	if reservation.ClusterName != "" && machineLabels[clusterv1.ClusterNameLabel] != reservation.ClusterName {
+		return false, nil
+	}
+
+	if reservation.Namespace != "" && namespace != reservation.Namespace {
+		return false, nil
+	}
+
+	if reservation.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(reservation.Selector)
+		if err != nil {
+			return false, fmt.Errorf("failed to get selector from labelselector: %w", err)
+		}
+
+		if !selector.Matches(labels.Set(machineLabels)) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// String describes the reservation holder.
+func (reservation *ServerReservation) String() string {
+	var holder []string
+
+	if reservation.ClusterName != "" {
+		holder = append(holder, fmt.Sprintf("cluster %q", reservation.ClusterName))
+	}
+
+	if reservation.Namespace != "" {
+		holder = append(holder, fmt.Sprintf("namespace %q", reservation.Namespace))
+	}
+
+	if reservation.Selector != nil {
+		holder = append(holder, fmt.Sprintf("machines %q", metav1.FormatLabelSelector(reservation.Selector)))
+	}
+
+	return strings.Join(holder, ", ")
+}
+
+// ServerReservationStatus describes the active reservation of the server in the server class.
+type ServerReservationStatus struct {
+	// Server is the name of the reserved server.
+	Server string `json:"server"`
+	// ReservedFor describes the reservation holder.
+	ReservedFor string `json:"reservedFor"`
+	// ExpiresAt is the time when the reservation expires.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
@@ -0,0 +1,93 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestServerReservationActive(t *testing.T) {
+	now := time.Now()
+
+	assert.False(t, (*metal.ServerReservation)(nil).Active(now))
+	assert.True(t, (&metal.ServerReservation{ClusterName: "prod"}).Active(now))
+	assert.True(t, (&metal.ServerReservation{ClusterName: "prod", ExpiresAt: &metav1.Time{Time: now.Add(time.Hour)}}).Active(now))
+	assert.False(t, (&metal.ServerReservation{ClusterName: "prod", ExpiresAt: &metav1.Time{Time: now.Add(-time.Hour)}}).Active(now))
+}
+
+func TestServerReservationMatches(t *testing.T) {
+	reservation := metal.ServerReservation{
+		ClusterName: "prod",
+		Namespace:   "prod-ns",
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"cluster.x-k8s.io/deployment-name": "workers"},
+		},
+	}
+
+	machineLabels := map[string]string{
+		"cluster.x-k8s.io/cluster-name":    "prod",
+		"cluster.x-k8s.io/deployment-name": "workers",
+	}
+
+	for _, tt := range []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		expected  bool
+	}{
+		{
+			name:      "match",
+			namespace: "prod-ns",
+			labels:    machineLabels,
+			expected:  true,
+		},
+		{
+			name:      "other namespace",
+			namespace: "dev-ns",
+			labels:    machineLabels,
+		},
+		{
+			name:      "other cluster",
+			namespace: "prod-ns",
+			labels: map[string]string{
+				"cluster.x-k8s.io/cluster-name":    "dev",
+				"cluster.x-k8s.io/deployment-name": "workers",
+			},
+		},
+		{
+			name:      "other deployment",
+			namespace: "prod-ns",
+			labels: map[string]string{
+				"cluster.x-k8s.io/cluster-name": "prod",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := reservation.Matches(tt.namespace, tt.labels)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, match)
+		})
+	}
+
+	assert.Equal(t, `cluster "prod", namespace "prod-ns", machines "cluster.x-k8s.io/deployment-name=workers"`, reservation.String())
+}
+
+func TestServerReservationValidate(t *testing.T) {
+	path := field.NewPath("spec", "reservedFor")
+
+	assert.Empty(t, (*metal.ServerReservation)(nil).Validate(path))
+	assert.Empty(t, (&metal.ServerReservation{Namespace: "prod"}).Validate(path))
+	assert.Len(t, (&metal.ServerReservation{ExpiresAt: &metav1.Time{Time: time.Now()}}).Validate(path), 1)
+}
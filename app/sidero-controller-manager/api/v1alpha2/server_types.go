@@ -88,6 +88,32 @@ func (source *CredentialSource) Resolve(ctx context.Context, reader client.Clien
 	return string(rawValue), nil
 }
 
+// Redfish defines data about how to talk to the node via Redfish API.
+type Redfish struct {
+	// Redfish endpoint, either host[:port] or a full URL.
+	// If the scheme is not specified, https is used.
+	Endpoint string `json:"endpoint"`
+	// Redfish user value.
+	// +optional
+	User string `json:"user,omitempty"`
+	// Source for the user value. Cannot be used if User is not empty.
+	// +optional
+	UserFrom *CredentialSource `json:"userFrom,omitempty"`
+	// Redfish password value.
+	// +optional
+	Pass string `json:"pass,omitempty"`
+	// Source for the password value. Cannot be used if Pass is not empty.
+	// +optional
+	PassFrom *CredentialSource `json:"passFrom,omitempty"`
+	// SystemID is the ID of the computer system in the Redfish Systems collection.
+	// Defaults to the first system reported by the endpoint.
+	// +optional
+	SystemID string `json:"systemID,omitempty"`
+	// InsecureSkipVerify disables TLS certificate verification of the Redfish endpoint.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
 // ManagementAPI defines data about how to talk to the node via simple HTTP API.
 type ManagementAPI struct {
 	Endpoint string `json:"endpoint"`
@@ -147,6 +173,12 @@ type StorageDevice struct {
 	DeviceName string `json:"deviceName,omitempty"`
 	UUID       string `json:"uuid,omitempty"`
 	WWID       string `json:"wwid,omitempty"`
+	// Rotational is true for the spinning disks.
+	Rotational bool `json:"rotational,omitempty"`
+	// Transport is the bus the disk is attached to: nvme, sata, sas, scsi, usb, mmc, virtio.
+	Transport string `json:"transport,omitempty"`
+	// SMARTHealth is the SMART overall health status: PASSED or FAILED, empty if not supported.
+	SMARTHealth string `json:"smartHealth,omitempty"`
 }
 
 type StorageInformation struct {
@@ -162,6 +194,12 @@ type NetworkInterface struct {
 	MTU       uint32   `json:"mtu,omitempty"`
 	MAC       string   `json:"mac,omitempty"`
 	Addresses []string `json:"addresses,omitempty"`
+	// Driver is the kernel driver of the network interface.
+	Driver string `json:"driver,omitempty"`
+	// FirmwareVersion is the firmware version of the network interface.
+	FirmwareVersion string `json:"firmwareVersion,omitempty"`
+	// LinkSpeed is the link speed in megabits per second (Mbps), zero if the link is down.
+	LinkSpeed uint32 `json:"linkSpeed,omitempty"`
 }
 
 type NetworkInformation struct {
@@ -169,12 +207,51 @@ type NetworkInformation struct {
 	Interfaces     []*NetworkInterface `json:"interfaces,omitempty"`
 }
 
+type PCIDevice struct {
+	// Address is the PCI address of the device, e.g. 0000:3b:00.0.
+	Address string `json:"address,omitempty"`
+	// VendorID is the PCI vendor ID, e.g. 0x15b3.
+	VendorID string `json:"vendorID,omitempty"`
+	// DeviceID is the PCI device ID, e.g. 0x101b.
+	DeviceID string `json:"deviceID,omitempty"`
+	// SubsystemVendorID is the PCI subsystem vendor ID.
+	SubsystemVendorID string `json:"subsystemVendorID,omitempty"`
+	// SubsystemDeviceID is the PCI subsystem device ID.
+	SubsystemDeviceID string `json:"subsystemDeviceID,omitempty"`
+	// Class is the PCI device class, e.g. 0x020000 for Ethernet controllers.
+	Class string `json:"class,omitempty"`
+	// Driver is the kernel driver bound to the device.
+	Driver string `json:"driver,omitempty"`
+}
+
+type PCIInformation struct {
+	DeviceCount uint32 `json:"deviceCount,omitempty"`
+	// GPUCount is the number of display controllers (PCI class 0x03).
+	GPUCount uint32       `json:"gpuCount,omitempty"`
+	Devices  []*PCIDevice `json:"devices,omitempty"`
+}
+
+type BIOSInformation struct {
+	Vendor      string `json:"vendor,omitempty"`
+	Version     string `json:"version,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+}
+
+type TPMInformation struct {
+	Present bool `json:"present,omitempty"`
+	// Version is the TPM specification version: 1.2 or 2.0.
+	Version string `json:"version,omitempty"`
+}
+
 type HardwareInformation struct {
 	System  *SystemInformation  `json:"system,omitempty"`
 	Compute *ComputeInformation `json:"compute,omitempty"`
 	Memory  *MemoryInformation  `json:"memory,omitempty"`
 	Storage *StorageInformation `json:"storage,omitempty"`
 	Network *NetworkInformation `json:"network,omitempty"`
+	PCI     *PCIInformation     `json:"pci,omitempty"`
+	BIOS    *BIOSInformation    `json:"bios,omitempty"`
+	TPM     *TPMInformation     `json:"tpm,omitempty"`
 }
 
 func (a *HardwareInformation) PartialEqual(b *HardwareInformation) bool {
@@ -240,15 +317,26 @@ type ServerSpec struct {
 	Hardware       *HardwareInformation    `json:"hardware,omitempty"`
 	Hostname       string                  `json:"hostname,omitempty"`
 	BMC            *BMC                    `json:"bmc,omitempty"`
+	Redfish        *Redfish                `json:"redfish,omitempty"`
 	ManagementAPI  *ManagementAPI          `json:"managementApi,omitempty"`
 	ConfigPatches  []ConfigPatches         `json:"configPatches,omitempty"`
 	// StrategicPatches are Talos machine configuration strategic merge patches.
 	//
 	// +optional
 	StrategicPatches []string `json:"strategicPatches,omitempty"`
-	Accepted         bool     `json:"accepted"`
-	Cordoned         bool     `json:"cordoned,omitempty"`
-	PXEBootAlways    bool     `json:"pxeBootAlways,omitempty"`
+	// ConfigPatchRefs references the MachineConfigPatches to apply to this server.
+	//
+	// +optional
+	ConfigPatchRefs []corev1.LocalObjectReference `json:"configPatchRefs,omitempty"`
+	// PatchTemplating enables rendering the config patches of this server as Go templates.
+	//
+	// If not set, the patches are applied verbatim.
+	//
+	// +optional
+	PatchTemplating *PatchTemplating `json:"patchTemplating,omitempty"`
+	Accepted        bool             `json:"accepted"`
+	Cordoned        bool             `json:"cordoned,omitempty"`
+	PXEBootAlways   bool             `json:"pxeBootAlways,omitempty"`
 	// BootFromDiskMethod specifies the method to exit iPXE to force boot from disk.
 	//
 	// If not set, controller default is used.
@@ -263,6 +351,30 @@ type ServerSpec struct {
 	//
 	// +optional
 	PXEMode siderotypes.PXEMode `json:"pxeMode,omitempty"`
+	// WipePolicy specifies how the server disks are wiped.
+	//
+	// If not set, the policy of the matching ServerClass is used with the fallback to the controller default.
+	//
+	// +optional
+	WipePolicy *WipePolicy `json:"wipePolicy,omitempty"`
+	// InstallDisk selects the Talos install disk of the server from its hardware information.
+	//
+	// If not set, the install disk selector of the ServerClass is used with the fallback to the install disk of the machine configuration.
+	//
+	// +optional
+	InstallDisk *InstallDiskSelector `json:"installDisk,omitempty"`
+	// Validation specifies the hardware checks performed before the server is wiped.
+	//
+	// If not set, the validation of the matching ServerClass is used.
+	//
+	// +optional
+	Validation *HardwareValidation `json:"validation,omitempty"`
+	// ReservedFor reserves the server for the specific clusters or machines.
+	//
+	// While the reservation is active, the server is allocated only to the matching MetalMachines.
+	//
+	// +optional
+	ReservedFor *ServerReservation `json:"reservedFor,omitempty"`
 }
 
 const (
@@ -270,8 +382,56 @@ const (
 	ConditionPowerCycle clusterv1.ConditionType = "PowerCycle"
 	// ConditionPXEBooted is used to record the fact that server got PXE booted.
 	ConditionPXEBooted clusterv1.ConditionType = "PXEBooted"
+	// ConditionHardwareChanged is set when the hardware reported by the agent differs from the stored one.
+	ConditionHardwareChanged clusterv1.ConditionType = "HardwareChanged"
+)
+
+const (
+	// HardwareChangedReason is the reason of the HardwareChanged condition when the change is not acknowledged yet.
+	HardwareChangedReason = "HardwareChanged"
+	// HardwareChangedAutoCordonedReason is the reason of the HardwareChanged condition when the server was cordoned on the change.
+	HardwareChangedAutoCordonedReason = "AutoCordoned"
+	// HardwareChangeAcknowledgedReason is the reason of the HardwareChanged condition when the change was acknowledged.
+	HardwareChangeAcknowledgedReason = "Acknowledged"
+
+	// HardwareChangeAcknowledgedAnnotation acknowledges the hardware change when set on the Server.
+	//
+	// The annotation is removed once the change is acknowledged.
+	HardwareChangeAcknowledgedAnnotation = "metal.sidero.dev/hardware-change-acknowledged"
 )
 
+// DiskWipeProgress describes the wipe progress of a single disk.
+type DiskWipeProgress struct {
+	// DeviceName is the name of the device being wiped, e.g. /dev/sda.
+	DeviceName string `json:"deviceName"`
+	// BytesWiped is the number of bytes already wiped.
+	BytesWiped uint64 `json:"bytesWiped"`
+	// TotalBytes is the size of the device.
+	TotalBytes uint64 `json:"totalBytes"`
+	// Method is the method used to wipe the device.
+	// +optional
+	Method string `json:"method,omitempty"`
+	// Complete is true when the device is fully wiped.
+	// +optional
+	Complete bool `json:"complete,omitempty"`
+}
+
+// WipeProgress describes the progress of the server disks wipe as reported by the agent.
+type WipeProgress struct {
+	// Percentage is the overall wipe progress across all disks.
+	Percentage int32 `json:"percentage"`
+	// BytesWiped is the number of bytes already wiped across all disks.
+	BytesWiped uint64 `json:"bytesWiped"`
+	// TotalBytes is the total size of all disks being wiped.
+	TotalBytes uint64 `json:"totalBytes"`
+	// LastProgressTime is the last time the number of wiped bytes increased.
+	// +optional
+	LastProgressTime metav1.Time `json:"lastProgressTime,omitempty"`
+	// Disks lists per-disk wipe progress.
+	// +optional
+	Disks []DiskWipeProgress `json:"disks,omitempty"`
+}
+
 // ServerStatus defines the observed state of Server.
 type ServerStatus struct {
 	// Ready is true when server is accepted and in use.
@@ -294,6 +454,18 @@ type ServerStatus struct {
 
 	// Power is the current power state of the server: "on", "off" or "unknown".
 	Power string `json:"power,omitempty"`
+
+	// WipeProgress is the progress of the last disk wipe reported by the agent.
+	// +optional
+	WipeProgress *WipeProgress `json:"wipeProgress,omitempty"`
+
+	// HardwareValidation lists the results of the last hardware validation reported by the agent.
+	// +optional
+	HardwareValidation []HardwareValidationResult `json:"hardwareValidation,omitempty"`
+
+	// LastAllocated is the time when the server was last allocated to a MetalMachine.
+	// +optional
+	LastAllocated *metav1.Time `json:"lastAllocated,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -303,9 +475,11 @@ type ServerStatus struct {
 // +kubebuilder:printcolumn:name="BMC IP",type="string",priority=1,JSONPath=".spec.bmc.endpoint",description="BMC IP"
 // +kubebuilder:printcolumn:name="Accepted",type="boolean",JSONPath=".spec.accepted",description="indicates if the server is accepted"
 // +kubebuilder:printcolumn:name="Cordoned",type="boolean",JSONPath=".spec.cordoned",description="indicates if the server is cordoned"
+// +kubebuilder:printcolumn:name="Reserved Until",type="date",priority=1,JSONPath=".spec.reservedFor.expiresAt",description="the time when the server reservation expires"
 // +kubebuilder:printcolumn:name="Allocated",type="boolean",JSONPath=".status.inUse",description="indicates that the server has been allocated"
 // +kubebuilder:printcolumn:name="Clean",type="boolean",JSONPath=".status.isClean",description="indicates if the server is clean or not"
 // +kubebuilder:printcolumn:name="Power",type="string",JSONPath=".status.power",description="display the current power status"
+// +kubebuilder:printcolumn:name="Wipe",type="integer",priority=1,JSONPath=".status.wipeProgress.percentage",description="disk wipe progress in percent"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of this resource"
 // +kubebuilder:storageversion
 
@@ -73,6 +73,11 @@ func (r *Server) validate() error {
 	allErrs = append(allErrs, r.validateBootFromDisk()...)
 	allErrs = append(allErrs, r.validatePXEMode()...)
 	allErrs = append(allErrs, r.validateConfigPatches()...)
+	allErrs = append(allErrs, r.Spec.WipePolicy.Validate(field.NewPath("spec").Child("wipePolicy"))...)
+	allErrs = append(allErrs, r.Spec.PatchTemplating.Validate(field.NewPath("spec").Child("patchTemplating"))...)
+	allErrs = append(allErrs, r.Spec.InstallDisk.Validate(field.NewPath("spec").Child("installDisk"))...)
+	allErrs = append(allErrs, r.Spec.Validation.Validate(field.NewPath("spec").Child("validation"))...)
+	allErrs = append(allErrs, r.Spec.ReservedFor.Validate(field.NewPath("spec").Child("reservedFor"))...)
 
 	if len(allErrs) == 0 {
 		return nil
@@ -0,0 +1,127 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// hardwareExpressionCostLimit limits the runtime cost of a single hardware expression evaluation.
+const hardwareExpressionCostLimit = 1_000_000
+
+// hardwareExpressionEnv is the CEL environment for the hardware expressions.
+//
+// Variables:
+//   - hardware: Server.Spec.Hardware with the fields named as in the Server resource
+//   - labels: Server labels
+//
+// Functions:
+//   - gigabytes(string) int: parses the total size as reported in the hardware information, e.g. "256 GB"
+var hardwareExpressionEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("hardware", cel.DynType),
+		cel.Variable("labels", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Function("gigabytes",
+			cel.Overload("gigabytes_string", []*cel.Type{cel.StringType}, cel.IntType,
+				cel.UnaryBinding(func(value ref.Val) ref.Val {
+					size, err := ParseGigabytes(string(value.(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+
+					return types.Int(size)
+				}),
+			),
+		),
+		ext.Strings(),
+	)
+})
+
+// ParseGigabytes parses the total size as reported in the hardware information, e.g. "256 GB".
+func ParseGigabytes(size string) (int64, error) {
+	var gigabytes int64
+
+	if _, err := fmt.Sscanf(size, "%d GB", &gigabytes); err != nil {
+		return 0, fmt.Errorf("failed to parse size %q: %w", size, err)
+	}
+
+	return gigabytes, nil
+}
+
+// CompileHardwareExpression compiles the CEL expression which should evaluate to a boolean.
+func CompileHardwareExpression(expression string) (cel.Program, error) {
+	env, err := hardwareExpressionEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression should evaluate to bool, got %s", ast.OutputType())
+	}
+
+	return env.Program(ast, cel.CostLimit(hardwareExpressionCostLimit))
+}
+
+// MatchHardwareExpression evaluates the compiled expression against the server.
+//
+// Evaluation errors (e.g. accessing the field which is not reported for the server) are returned as errors.
+func MatchHardwareExpression(program cel.Program, server *Server) (bool, error) {
+	hardware := map[string]any{}
+
+	if server.Spec.Hardware != nil {
+		var err error
+
+		if hardware, err = runtime.DefaultUnstructuredConverter.ToUnstructured(server.Spec.Hardware); err != nil {
+			return false, err
+		}
+	}
+
+	labels := server.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	out, _, err := program.Eval(map[string]any{
+		"hardware": hardware,
+		"labels":   labels,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	match, ok := out.Value().(bool)
+	if !ok {
+		return false, errors.New("expression didn't evaluate to bool")
+	}
+
+	return match, nil
+}
+
+// Validate the qualifiers.
+func (q *Qualifiers) Validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, expression := range q.Expressions {
+		if _, err := CompileHardwareExpression(expression); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("expressions").Index(i), expression, err.Error()))
+		}
+	}
+
+	return allErrs
+}
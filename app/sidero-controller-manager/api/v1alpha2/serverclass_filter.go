@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // AcceptedServerFilter matches Servers that have Spec.Accepted set to true.
@@ -38,7 +41,26 @@ func (sc *ServerClass) SelectorFilter() func(Server) (bool, error) {
 // QualifiersFilter returns a ServerFilter that matches servers against the
 // serverclass's qualifiers field.
 func (sc *ServerClass) QualifiersFilter() func(Server) (bool, error) {
+	expressions := make([]cel.Program, 0, len(sc.Spec.Qualifiers.Expressions))
+
+	var compileErr error
+
+	for _, expression := range sc.Spec.Qualifiers.Expressions {
+		compiled, err := CompileHardwareExpression(expression)
+		if err != nil {
+			compileErr = fmt.Errorf("failed to compile qualifier expression %q: %w", expression, err)
+
+			break
+		}
+
+		expressions = append(expressions, compiled)
+	}
+
 	return func(server Server) (bool, error) {
+		if compileErr != nil {
+			return false, compileErr
+		}
+
 		q := sc.Spec.Qualifiers
 
 		// check hardware qualifiers if they are present
@@ -74,10 +96,44 @@ func (sc *ServerClass) QualifiersFilter() func(Server) (bool, error) {
 			}
 		}
 
+		for _, expression := range expressions {
+			// evaluation errors are caused by the server hardware information, so they don't match
+			if match, err := MatchHardwareExpression(expression, &server); err != nil || !match {
+				return false, nil //nolint:nilerr
+			}
+		}
+
 		return true, nil
 	}
 }
 
+// HardwareValidatedFilter returns a ServerFilter that matches servers against the
+// serverclass's validation field.
+//
+// Servers which failed the hardware validation never match, if the serverclass
+// requires validation, servers should pass it.
+func (sc *ServerClass) HardwareValidatedFilter() func(Server) (bool, error) {
+	return func(server Server) (bool, error) {
+		var validated *clusterv1.Condition
+
+		for i := range server.Status.Conditions {
+			if server.Status.Conditions[i].Type == ConditionHardwareValidated {
+				validated = &server.Status.Conditions[i]
+			}
+		}
+
+		if validated != nil && validated.Status == corev1.ConditionFalse && validated.Reason == HardwareValidationFailedReason {
+			return false, nil
+		}
+
+		if sc.Spec.Validation == nil {
+			return true, nil
+		}
+
+		return validated != nil && validated.Status == corev1.ConditionTrue, nil
+	}
+}
+
 // FilterServers returns the subset of servers that pass all provided filters.
 // In case of error the returned slice will be nil.
 func FilterServers(servers []Server, filters ...func(Server) (bool, error)) ([]Server, error) {
@@ -8,7 +8,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
 )
@@ -72,6 +75,9 @@ func TestFilterAcceptedServers(t *testing.T) {
 						},
 					},
 				},
+				Memory: &metal.MemoryInformation{
+					TotalSize: "128 GB",
+				},
 			},
 		},
 	}
@@ -351,6 +357,47 @@ func TestFilterAcceptedServers(t *testing.T) {
 			},
 			expected: []metal.Server{},
 		},
+		"expression - core count range": {
+			q: metal.Qualifiers{
+				Expressions: []string{
+					"hardware.compute.totalCoreCount >= 8 && hardware.compute.totalCoreCount <= 16",
+				},
+			},
+			expected: []metal.Server{dualXeon, ryzen},
+		},
+		"expression - any processor": {
+			q: metal.Qualifiers{
+				Expressions: []string{
+					`hardware.compute.processors.exists(p, p.manufacturer.startsWith("Advanced Micro Devices"))`,
+				},
+			},
+			expected: []metal.Server{ryzen},
+		},
+		"expression - missing field": {
+			q: metal.Qualifiers{
+				Expressions: []string{
+					`hardware.system.manufacturer == "QEMU"`,
+				},
+			},
+			expected: []metal.Server{ryzen},
+		},
+		"expression - memory size": {
+			q: metal.Qualifiers{
+				Expressions: []string{
+					`gigabytes(hardware.memory.totalSize) >= 64`,
+				},
+			},
+			expected: []metal.Server{dualXeon},
+		},
+		"expression - all should match": {
+			q: metal.Qualifiers{
+				Expressions: []string{
+					`labels["zone"] in ["central", "east"]`,
+					"hardware.compute.processorCount == 1 && hardware.compute.processors[0].speed < 3000",
+				},
+			},
+			expected: []metal.Server{atom},
+		},
 		metal.ServerClassAny: {
 			expected: []metal.Server{atom, dualXeon, ryzen},
 		},
@@ -377,3 +424,95 @@ func TestFilterAcceptedServers(t *testing.T) {
 		})
 	}
 }
+
+func TestQualifiersValidate(t *testing.T) {
+	t.Parallel()
+
+	q := metal.Qualifiers{
+		Expressions: []string{
+			"gigabytes(hardware.memory.totalSize) >= 256",
+			"hardware.memory.totalSize >=",
+			"hardware.memory.totalSize + 1",
+			"unknown.field == 1",
+		},
+	}
+
+	errs := q.Validate(field.NewPath("spec", "qualifiers"))
+	assert.Len(t, errs, 3)
+
+	for _, err := range errs {
+		assert.Contains(t, err.Field, "spec.qualifiers.expressions[")
+	}
+
+	sc := &metal.ServerClass{
+		Spec: metal.ServerClassSpec{
+			Qualifiers: q,
+		},
+	}
+
+	_, err := metal.FilterServers([]metal.Server{{}}, sc.QualifiersFilter())
+	assert.Error(t, err)
+}
+
+func TestHardwareValidatedFilter(t *testing.T) {
+	t.Parallel()
+
+	withCondition := func(name string, status corev1.ConditionStatus, reason string) metal.Server {
+		server := metal.Server{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}
+
+		if status != "" {
+			server.Status.Conditions = clusterv1.Conditions{
+				{
+					Type:   metal.ConditionHardwareValidated,
+					Status: status,
+					Reason: reason,
+				},
+			}
+		}
+
+		return server
+	}
+
+	notValidated := withCondition("not-validated", "", "")
+	validated := withCondition("validated", corev1.ConditionTrue, "")
+	inProgress := withCondition("in-progress", corev1.ConditionFalse, metal.HardwareValidationInProgressReason)
+	failed := withCondition("failed", corev1.ConditionFalse, metal.HardwareValidationFailedReason)
+
+	servers := []metal.Server{notValidated, validated, inProgress, failed}
+
+	for _, td := range []struct {
+		name       string
+		validation *metal.HardwareValidation
+		expected   []metal.Server
+	}{
+		{
+			name:     "no validation",
+			expected: []metal.Server{notValidated, validated, inProgress},
+		},
+		{
+			name: "validation",
+			validation: &metal.HardwareValidation{
+				DiskHealth: true,
+			},
+			expected: []metal.Server{validated},
+		},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			t.Parallel()
+
+			sc := &metal.ServerClass{
+				Spec: metal.ServerClassSpec{
+					Validation: td.validation,
+				},
+			}
+
+			actual, err := metal.FilterServers(servers, sc.HardwareValidatedFilter())
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, td.expected, actual)
+		})
+	}
+}
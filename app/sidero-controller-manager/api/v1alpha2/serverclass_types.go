@@ -17,6 +17,16 @@ const ServerClassAny = "any"
 type Qualifiers struct {
 	Hardware       []HardwareInformation `json:"hardware,omitempty"`
 	LabelSelectors []map[string]string   `json:"labelSelectors,omitempty"`
+	// Expressions are CEL expressions evaluated against the server hardware information and labels.
+	//
+	// Variables available in the expressions are `hardware` (Server .spec.hardware) and `labels` (Server labels),
+	// `gigabytes()` function parses the total sizes (e.g. "256 GB") as integers.
+	// Server should match all expressions, expressions which fail to evaluate (e.g. missing fields) don't match.
+	//
+	// Example: `gigabytes(hardware.memory.totalSize) >= 256 && hardware.storage.devices.exists(d, d.type == "NVMe")`.
+	//
+	// +optional
+	Expressions []string `json:"expressions,omitempty"`
 }
 
 // ServerClassSpec defines the desired state of ServerClass.
@@ -42,6 +52,18 @@ type ServerClassSpec struct {
 	// +optional
 	// +k8s:conversion-gen=false
 	StrategicPatches []string `json:"strategicPatches,omitempty"`
+	// ConfigPatchRefs references the MachineConfigPatches to apply to the servers provisioned via this server class.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	ConfigPatchRefs []corev1.LocalObjectReference `json:"configPatchRefs,omitempty"`
+	// PatchTemplating enables rendering the config patches of this server class as Go templates.
+	//
+	// If not set, the patches are applied verbatim.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	PatchTemplating *PatchTemplating `json:"patchTemplating,omitempty"`
 	// BootFromDiskMethod specifies the method to exit iPXE to force boot from disk.
 	//
 	// If not set, controller default is used.
@@ -49,12 +71,44 @@ type ServerClassSpec struct {
 	//
 	// +optional
 	BootFromDiskMethod siderotypes.BootFromDisk `json:"bootFromDiskMethod,omitempty"`
+	// WipePolicy specifies how the disks of the servers matching this server class are wiped.
+	//
+	// If the server matches several server classes with the wipe policy, the first one (sorted by name) is used.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	WipePolicy *WipePolicy `json:"wipePolicy,omitempty"`
+	// InstallDisk selects the Talos install disk of the servers provisioned via this server class from their hardware information.
+	//
+	// If not set, the install disk of the machine configuration is used.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	InstallDisk *InstallDiskSelector `json:"installDisk,omitempty"`
+	// Validation specifies the hardware checks performed before the servers matching this server class are wiped.
+	//
+	// Only servers which passed the validation are available in the server class.
+	// If the server matches several server classes with the validation, the first one (sorted by name) is used.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	Validation *HardwareValidation `json:"validation,omitempty"`
+	// AllocationStrategy specifies how the servers are picked from the server class for the metal machines.
+	//
+	// If not set, the first available server (sorted by name) is picked.
+	//
+	// +optional
+	// +k8s:conversion-gen=false
+	AllocationStrategy *AllocationStrategy `json:"allocationStrategy,omitempty"`
 }
 
 // ServerClassStatus defines the observed state of ServerClass.
 type ServerClassStatus struct {
 	ServersAvailable []string `json:"serversAvailable"`
 	ServersInUse     []string `json:"serversInUse"`
+	// ServersReserved lists the servers which are not in use, but reserved, they are not included in ServersAvailable.
+	// +optional
+	ServersReserved []ServerReservationStatus `json:"serversReserved,omitempty"`
 }
 
 // +kubebuilder:object:root=true
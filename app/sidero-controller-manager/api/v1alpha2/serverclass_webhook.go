@@ -5,12 +5,21 @@
 package v1alpha2
 
 import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 func (r *ServerClass) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(r).
 		Complete()
 }
 
@@ -19,3 +28,45 @@ func (r *ServerClassList) SetupWebhookWithManager(mgr ctrl.Manager) error {
 		For(r).
 		Complete()
 }
+
+//+kubebuilder:webhook:verbs=create;update;delete,path=/validate-metal-sidero-dev-v1alpha2-serverclass,mutating=false,failurePolicy=fail,groups=metal.sidero.dev,resources=serverclasses,versions=v1alpha2,name=vserverclasses.metal.sidero.dev,sideEffects=None,admissionReviewVersions=v1
+
+var _ webhook.CustomValidator = &ServerClass{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *ServerClass) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r = obj.(*ServerClass)
+
+	return nil, r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *ServerClass) ValidateUpdate(ctx context.Context, oldObj runtime.Object, newObj runtime.Object) (admission.Warnings, error) {
+	r = newObj.(*ServerClass)
+
+	return nil, r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *ServerClass) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *ServerClass) validate() error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.Spec.Qualifiers.Validate(field.NewPath("spec").Child("qualifiers"))...)
+	allErrs = append(allErrs, r.Spec.WipePolicy.Validate(field.NewPath("spec").Child("wipePolicy"))...)
+	allErrs = append(allErrs, r.Spec.PatchTemplating.Validate(field.NewPath("spec").Child("patchTemplating"))...)
+	allErrs = append(allErrs, r.Spec.InstallDisk.Validate(field.NewPath("spec").Child("installDisk"))...)
+	allErrs = append(allErrs, r.Spec.Validation.Validate(field.NewPath("spec").Child("validation"))...)
+	allErrs = append(allErrs, r.Spec.AllocationStrategy.Validate(field.NewPath("spec").Child("allocationStrategy"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: GroupVersion.Group, Kind: "ServerClass"},
+		r.Name, allErrs)
+}
@@ -4,7 +4,11 @@
 
 package v1alpha2
 
-import apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+import (
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
 
 // nb: we use apiextensions.JSON for the value below b/c we can't use interface{} with controller-gen.
 // found this workaround here: https://github.com/kubernetes-sigs/controller-tools/pull/126#issuecomment-630769075
@@ -14,3 +18,31 @@ type ConfigPatches struct {
 	Path  string             `json:"path"`
 	Value apiextensions.JSON `json:"value,omitempty"`
 }
+
+// PatchTemplating enables rendering the config patches as Go templates.
+//
+// The templates have access to the Server, its ServerBinding addresses, the Cluster and Machine names,
+// and the variables from the referenced ConfigMap.
+type PatchTemplating struct {
+	// VariablesRef references the ConfigMap with the variables available in the templates as .Vars.
+	// The ConfigMap should be in the namespace of the Machine the server is allocated to,
+	// the empty namespace means the namespace of the Machine.
+	//
+	// +optional
+	VariablesRef *corev1.ObjectReference `json:"variablesRef,omitempty"`
+}
+
+// Validate the patch templating settings.
+func (t *PatchTemplating) Validate(path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if t == nil || t.VariablesRef == nil {
+		return allErrs
+	}
+
+	if t.VariablesRef.Name == "" {
+		allErrs = append(allErrs, field.Required(path.Child("variablesRef", "name"), "ConfigMap name is required"))
+	}
+
+	return allErrs
+}
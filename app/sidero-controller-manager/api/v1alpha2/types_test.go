@@ -0,0 +1,49 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestPatchTemplatingValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		templating *metal.PatchTemplating
+		errors     int
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:       "no variables",
+			templating: &metal.PatchTemplating{},
+		},
+		{
+			name:       "namespace",
+			templating: &metal.PatchTemplating{VariablesRef: &corev1.ObjectReference{Namespace: "default", Name: "variables"}},
+		},
+		{
+			name:       "machine namespace",
+			templating: &metal.PatchTemplating{VariablesRef: &corev1.ObjectReference{Name: "variables"}},
+		},
+		{
+			name:       "no name",
+			templating: &metal.PatchTemplating{VariablesRef: &corev1.ObjectReference{Namespace: "default"}},
+			errors:     1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, tt.templating.Validate(field.NewPath("spec", "patchTemplating")), tt.errors)
+		})
+	}
+}
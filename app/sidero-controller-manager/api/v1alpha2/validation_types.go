@@ -0,0 +1,73 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// HardwareValidation defines the hardware checks performed by the agent before the server is wiped.
+//
+// Checks which are not configured are skipped.
+type HardwareValidation struct {
+	// MemoryTestPasses is the number of pattern test passes over the available memory.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MemoryTestPasses int32 `json:"memoryTestPasses,omitempty"`
+	// DiskHealth checks the SMART health status of the disks.
+	// +optional
+	DiskHealth bool `json:"diskHealth,omitempty"`
+	// MinLinkSpeed is the minimum link speed (in Mbps) of the connected network interfaces.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinLinkSpeed int32 `json:"minLinkSpeed,omitempty"`
+	// CPUStressDuration is the duration of the CPU stress test running on all CPU cores.
+	// +optional
+	CPUStressDuration *metav1.Duration `json:"cpuStressDuration,omitempty"`
+}
+
+// Validate the hardware validation settings.
+func (validation *HardwareValidation) Validate(fldPath *field.Path) (allErrs field.ErrorList) {
+	if validation == nil {
+		return nil
+	}
+
+	if validation.MemoryTestPasses < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryTestPasses"), validation.MemoryTestPasses, "should be non-negative"))
+	}
+
+	if validation.MinLinkSpeed < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minLinkSpeed"), validation.MinLinkSpeed, "should be non-negative"))
+	}
+
+	if validation.CPUStressDuration != nil && validation.CPUStressDuration.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cpuStressDuration"), validation.CPUStressDuration.Duration.String(), "should be non-negative"))
+	}
+
+	return allErrs
+}
+
+// HardwareValidationResult is the result of a single hardware validation check.
+type HardwareValidationResult struct {
+	// Name of the check, e.g. memory or disk-health:/dev/sda.
+	Name string `json:"name"`
+	// Passed is true if the check passed.
+	Passed bool `json:"passed"`
+	// Message describes the check result.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// ConditionHardwareValidated is set when the hardware validation is performed.
+	ConditionHardwareValidated clusterv1.ConditionType = "HardwareValidated"
+
+	// HardwareValidationInProgressReason is the reason of the HardwareValidated condition while the validation is running.
+	HardwareValidationInProgressReason = "InProgress"
+	// HardwareValidationFailedReason is the reason of the HardwareValidated condition when some checks failed.
+	HardwareValidationFailedReason = "ValidationFailed"
+)
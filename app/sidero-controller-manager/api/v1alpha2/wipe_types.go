@@ -0,0 +1,170 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha2
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// WipeMethod defines a method to wipe a disk.
+type WipeMethod string
+
+const (
+	// WipeMethodFast wipes only the head and the tail of the disk.
+	WipeMethodFast WipeMethod = "fast"
+	// WipeMethodZero zeroes out the whole disk using the best available method (secure discard, zeroout, write zeroes).
+	WipeMethodZero WipeMethod = "zero"
+	// WipeMethodDiscard discards (TRIMs) the whole disk.
+	WipeMethodDiscard WipeMethod = "discard"
+	// WipeMethodATASecureErase uses ATA Security Erase Unit command.
+	WipeMethodATASecureErase WipeMethod = "ata-secure-erase"
+	// WipeMethodNVMeFormat uses NVMe Format NVM command with user data erase.
+	WipeMethodNVMeFormat WipeMethod = "nvme-format"
+	// WipeMethodCryptoErase uses NVMe Format NVM command with cryptographic erase.
+	WipeMethodCryptoErase WipeMethod = "crypto-erase"
+)
+
+// WipeMethods lists all supported wipe methods.
+var WipeMethods = []WipeMethod{
+	WipeMethodFast,
+	WipeMethodZero,
+	WipeMethodDiscard,
+	WipeMethodATASecureErase,
+	WipeMethodNVMeFormat,
+	WipeMethodCryptoErase,
+}
+
+// IsValid checks whether the wipe method is supported.
+func (method WipeMethod) IsValid() bool {
+	for _, m := range WipeMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StorageTypes lists storage device types as reported in the hardware information.
+var StorageTypes = []string{"Unknown", "SSD", "HDD", "NVMe", "SD"}
+
+// DiskSelector matches disks by their properties.
+//
+// All set fields should match for the disk to be selected, values support shell glob patterns.
+type DiskSelector struct {
+	// WWID of the disk.
+	// +optional
+	WWID string `json:"wwid,omitempty"`
+	// Serial number of the disk.
+	// +optional
+	Serial string `json:"serial,omitempty"`
+	// Model of the disk.
+	// +optional
+	Model string `json:"model,omitempty"`
+}
+
+// Match checks whether the disk with given properties matches the selector.
+func (selector *DiskSelector) Match(wwid, serial, model string) bool {
+	return matchPatterns(
+		[2]string{selector.WWID, wwid},
+		[2]string{selector.Serial, serial},
+		[2]string{selector.Model, model},
+	)
+}
+
+// matchPatterns checks whether the values match the shell glob patterns, empty patterns match any value.
+func matchPatterns(pairs ...[2]string) bool {
+	for _, pair := range pairs {
+		if pair[0] == "" {
+			continue
+		}
+
+		if matched, _ := path.Match(pair[0], pair[1]); !matched { //nolint:errcheck
+			return false
+		}
+	}
+
+	return true
+}
+
+// WipePolicy defines how the disks of the server are wiped.
+type WipePolicy struct {
+	// Method is the default wipe method.
+	// If not set, controller default is used (see --insecure-wipe).
+	// Valid values: fast, zero, discard, ata-secure-erase, nvme-format, crypto-erase.
+	// +optional
+	Method WipeMethod `json:"method,omitempty"`
+	// StorageTypeMethods overrides the wipe method per storage type.
+	// Valid keys: Unknown, SSD, HDD, NVMe, SD.
+	// +optional
+	StorageTypeMethods map[string]WipeMethod `json:"storageTypeMethods,omitempty"`
+	// Include limits the wipe to the disks matching any of the selectors.
+	// If empty, all disks are wiped.
+	// +optional
+	Include []DiskSelector `json:"include,omitempty"`
+	// Exclude skips the disks matching any of the selectors.
+	// +optional
+	Exclude []DiskSelector `json:"exclude,omitempty"`
+	// SkipBootMedia skips removable, USB and SD card devices which are commonly used as boot media.
+	// +optional
+	SkipBootMedia bool `json:"skipBootMedia,omitempty"`
+}
+
+// Validate the wipe policy.
+func (policy *WipePolicy) Validate(fldPath *field.Path) (allErrs field.ErrorList) {
+	if policy == nil {
+		return nil
+	}
+
+	if policy.Method != "" && !policy.Method.IsValid() {
+		allErrs = append(allErrs,
+			field.NotSupported(fldPath.Child("method"), policy.Method, WipeMethods),
+		)
+	}
+
+	for storageType, method := range policy.StorageTypeMethods {
+		valid := false
+
+		for _, t := range StorageTypes {
+			if storageType == t {
+				valid = true
+
+				break
+			}
+		}
+
+		if !valid {
+			allErrs = append(allErrs,
+				field.Invalid(fldPath.Child("storageTypeMethods"), storageType, fmt.Sprintf("valid storage types are: %q", StorageTypes)),
+			)
+		}
+
+		if !method.IsValid() {
+			allErrs = append(allErrs,
+				field.NotSupported(fldPath.Child("storageTypeMethods").Key(storageType), method, WipeMethods),
+			)
+		}
+	}
+
+	validateSelectors := func(selectorsPath *field.Path, selectors []DiskSelector) {
+		for i, selector := range selectors {
+			for _, pattern := range []string{selector.WWID, selector.Serial, selector.Model} {
+				if _, err := path.Match(pattern, ""); err != nil {
+					allErrs = append(allErrs,
+						field.Invalid(selectorsPath.Index(i), pattern, err.Error()),
+					)
+				}
+			}
+		}
+	}
+
+	validateSelectors(fldPath.Child("include"), policy.Include)
+	validateSelectors(fldPath.Child("exclude"), policy.Exclude)
+
+	return allErrs
+}
@@ -0,0 +1,76 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package v1alpha2_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	metal "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestDiskSelectorMatch(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		selector metal.DiskSelector
+		expected bool
+	}{
+		{
+			name:     "empty",
+			selector: metal.DiskSelector{},
+			expected: true,
+		},
+		{
+			name:     "exact",
+			selector: metal.DiskSelector{Serial: "S3EVNX0K123456"},
+			expected: true,
+		},
+		{
+			name:     "glob",
+			selector: metal.DiskSelector{Model: "Samsung SSD 8*", WWID: "eui.*"},
+			expected: true,
+		},
+		{
+			name:     "partial mismatch",
+			selector: metal.DiskSelector{Model: "Samsung SSD 8*", Serial: "other"},
+			expected: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.selector.Match("eui.0025388b9150c1a2", "S3EVNX0K123456", "Samsung SSD 860 EVO"))
+		})
+	}
+}
+
+func TestWipePolicyValidate(t *testing.T) {
+	fldPath := field.NewPath("spec").Child("wipePolicy")
+
+	assert.Empty(t, (*metal.WipePolicy)(nil).Validate(fldPath))
+
+	assert.Empty(t, (&metal.WipePolicy{
+		Method: metal.WipeMethodZero,
+		StorageTypeMethods: map[string]metal.WipeMethod{
+			"NVMe": metal.WipeMethodCryptoErase,
+			"SSD":  metal.WipeMethodATASecureErase,
+		},
+		Include: []metal.DiskSelector{{Model: "Samsung*"}},
+	}).Validate(fldPath))
+
+	errs := (&metal.WipePolicy{
+		Method: "shred",
+		StorageTypeMethods: map[string]metal.WipeMethod{
+			"Tape": metal.WipeMethodZero,
+		},
+		Exclude: []metal.DiskSelector{{Serial: "[abc"}},
+	}).Validate(fldPath)
+
+	assert.Len(t, errs, 3)
+	assert.Equal(t, "spec.wipePolicy.method", errs[0].Field)
+	assert.Equal(t, "spec.wipePolicy.storageTypeMethods", errs[1].Field)
+	assert.Equal(t, "spec.wipePolicy.exclude[0]", errs[2].Field)
+}
@@ -9,11 +9,27 @@
 package v1alpha2
 
 import (
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllocationStrategy) DeepCopyInto(out *AllocationStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllocationStrategy.
+func (in *AllocationStrategy) DeepCopy() *AllocationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AllocationStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Asset) DeepCopyInto(out *Asset) {
 	*out = *in
@@ -45,6 +61,46 @@ func (in *AssetCondition) DeepCopy() *AssetCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssetSources) DeepCopyInto(out *AssetSources) {
+	*out = *in
+	if in.RegistryCredentials != nil {
+		in, out := &in.RegistryCredentials, &out.RegistryCredentials
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetSources.
+func (in *AssetSources) DeepCopy() *AssetSources {
+	if in == nil {
+		return nil
+	}
+	out := new(AssetSources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BIOSInformation) DeepCopyInto(out *BIOSInformation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BIOSInformation.
+func (in *BIOSInformation) DeepCopy() *BIOSInformation {
+	if in == nil {
+		return nil
+	}
+	out := new(BIOSInformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BMC) DeepCopyInto(out *BMC) {
 	*out = *in
@@ -132,6 +188,158 @@ func (in *CredentialSource) DeepCopy() *CredentialSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPLease) DeepCopyInto(out *DHCPLease) {
+	*out = *in
+	in.ExpiresAt.DeepCopyInto(&out.ExpiresAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPLease.
+func (in *DHCPLease) DeepCopy() *DHCPLease {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPLease)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPPool) DeepCopyInto(out *DHCPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPPool.
+func (in *DHCPPool) DeepCopy() *DHCPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DHCPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPPoolList) DeepCopyInto(out *DHCPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DHCPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPPoolList.
+func (in *DHCPPoolList) DeepCopy() *DHCPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DHCPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPPoolSpec) DeepCopyInto(out *DHCPPoolSpec) {
+	*out = *in
+	if in.DNSServers != nil {
+		in, out := &in.DNSServers, &out.DNSServers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LeaseTime != nil {
+		in, out := &in.LeaseTime, &out.LeaseTime
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPPoolSpec.
+func (in *DHCPPoolSpec) DeepCopy() *DHCPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DHCPPoolStatus) DeepCopyInto(out *DHCPPoolStatus) {
+	*out = *in
+	if in.Leases != nil {
+		in, out := &in.Leases, &out.Leases
+		*out = make([]DHCPLease, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DHCPPoolStatus.
+func (in *DHCPPoolStatus) DeepCopy() *DHCPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DHCPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSelector) DeepCopyInto(out *DiskSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSelector.
+func (in *DiskSelector) DeepCopy() *DiskSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskWipeProgress) DeepCopyInto(out *DiskWipeProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskWipeProgress.
+func (in *DiskWipeProgress) DeepCopy() *DiskWipeProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskWipeProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Environment) DeepCopyInto(out *Environment) {
 	*out = *in
@@ -196,6 +404,21 @@ func (in *EnvironmentSpec) DeepCopyInto(out *EnvironmentSpec) {
 	*out = *in
 	in.Kernel.DeepCopyInto(&out.Kernel)
 	out.Initrd = in.Initrd
+	if in.UKI != nil {
+		in, out := &in.UKI, &out.UKI
+		*out = new(UKI)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = new(AssetSources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageFactory != nil {
+		in, out := &in.ImageFactory, &out.ImageFactory
+		*out = new(ImageFactorySource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentSpec.
@@ -216,6 +439,11 @@ func (in *EnvironmentStatus) DeepCopyInto(out *EnvironmentStatus) {
 		*out = make([]AssetCondition, len(*in))
 		copy(*out, *in)
 	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]ReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentStatus.
@@ -256,6 +484,21 @@ func (in *HardwareInformation) DeepCopyInto(out *HardwareInformation) {
 		*out = new(NetworkInformation)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PCI != nil {
+		in, out := &in.PCI, &out.PCI
+		*out = new(PCIInformation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BIOS != nil {
+		in, out := &in.BIOS, &out.BIOS
+		*out = new(BIOSInformation)
+		**out = **in
+	}
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(TPMInformation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareInformation.
@@ -268,6 +511,61 @@ func (in *HardwareInformation) DeepCopy() *HardwareInformation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareValidation) DeepCopyInto(out *HardwareValidation) {
+	*out = *in
+	if in.CPUStressDuration != nil {
+		in, out := &in.CPUStressDuration, &out.CPUStressDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareValidation.
+func (in *HardwareValidation) DeepCopy() *HardwareValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HardwareValidationResult) DeepCopyInto(out *HardwareValidationResult) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HardwareValidationResult.
+func (in *HardwareValidationResult) DeepCopy() *HardwareValidationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(HardwareValidationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageFactorySource) DeepCopyInto(out *ImageFactorySource) {
+	*out = *in
+	if in.ExtraKernelArgs != nil {
+		in, out := &in.ExtraKernelArgs, &out.ExtraKernelArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFactorySource.
+func (in *ImageFactorySource) DeepCopy() *ImageFactorySource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageFactorySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Initrd) DeepCopyInto(out *Initrd) {
 	*out = *in
@@ -284,6 +582,31 @@ func (in *Initrd) DeepCopy() *Initrd {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallDiskSelector) DeepCopyInto(out *InstallDiskSelector) {
+	*out = *in
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallDiskSelector.
+func (in *InstallDiskSelector) DeepCopy() *InstallDiskSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallDiskSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kernel) DeepCopyInto(out *Kernel) {
 	*out = *in
@@ -305,6 +628,96 @@ func (in *Kernel) DeepCopy() *Kernel {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineConfigPatch) DeepCopyInto(out *MachineConfigPatch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineConfigPatch.
+func (in *MachineConfigPatch) DeepCopy() *MachineConfigPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineConfigPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineConfigPatch) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineConfigPatchList) DeepCopyInto(out *MachineConfigPatchList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MachineConfigPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineConfigPatchList.
+func (in *MachineConfigPatchList) DeepCopy() *MachineConfigPatchList {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineConfigPatchList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MachineConfigPatchList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineConfigPatchSpec) DeepCopyInto(out *MachineConfigPatchSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigPatches != nil {
+		in, out := &in.ConfigPatches, &out.ConfigPatches
+		*out = make([]ConfigPatches, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StrategicPatches != nil {
+		in, out := &in.StrategicPatches, &out.StrategicPatches
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineConfigPatchSpec.
+func (in *MachineConfigPatchSpec) DeepCopy() *MachineConfigPatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineConfigPatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementAPI) DeepCopyInto(out *ManagementAPI) {
 	*out = *in
@@ -407,6 +820,67 @@ func (in *NetworkInterface) DeepCopy() *NetworkInterface {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PCIDevice) DeepCopyInto(out *PCIDevice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PCIDevice.
+func (in *PCIDevice) DeepCopy() *PCIDevice {
+	if in == nil {
+		return nil
+	}
+	out := new(PCIDevice)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PCIInformation) DeepCopyInto(out *PCIInformation) {
+	*out = *in
+	if in.Devices != nil {
+		in, out := &in.Devices, &out.Devices
+		*out = make([]*PCIDevice, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(PCIDevice)
+				**out = **in
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PCIInformation.
+func (in *PCIInformation) DeepCopy() *PCIInformation {
+	if in == nil {
+		return nil
+	}
+	out := new(PCIInformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PatchTemplating) DeepCopyInto(out *PatchTemplating) {
+	*out = *in
+	if in.VariablesRef != nil {
+		in, out := &in.VariablesRef, &out.VariablesRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchTemplating.
+func (in *PatchTemplating) DeepCopy() *PatchTemplating {
+	if in == nil {
+		return nil
+	}
+	out := new(PatchTemplating)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Processor) DeepCopyInto(out *Processor) {
 	*out = *in
@@ -445,6 +919,11 @@ func (in *Qualifiers) DeepCopyInto(out *Qualifiers) {
 			}
 		}
 	}
+	if in.Expressions != nil {
+		in, out := &in.Expressions, &out.Expressions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Qualifiers.
@@ -457,6 +936,46 @@ func (in *Qualifiers) DeepCopy() *Qualifiers {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redfish) DeepCopyInto(out *Redfish) {
+	*out = *in
+	if in.UserFrom != nil {
+		in, out := &in.UserFrom, &out.UserFrom
+		*out = new(CredentialSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PassFrom != nil {
+		in, out := &in.PassFrom, &out.PassFrom
+		*out = new(CredentialSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redfish.
+func (in *Redfish) DeepCopy() *Redfish {
+	if in == nil {
+		return nil
+	}
+	out := new(Redfish)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaStatus) DeepCopyInto(out *ReplicaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaStatus.
+func (in *ReplicaStatus) DeepCopy() *ReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
 	*out = *in
@@ -563,7 +1082,7 @@ func (in *ServerClassSpec) DeepCopyInto(out *ServerClassSpec) {
 	*out = *in
 	if in.EnvironmentRef != nil {
 		in, out := &in.EnvironmentRef, &out.EnvironmentRef
-		*out = new(v1.ObjectReference)
+		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
 	in.Qualifiers.DeepCopyInto(&out.Qualifiers)
@@ -580,6 +1099,36 @@ func (in *ServerClassSpec) DeepCopyInto(out *ServerClassSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConfigPatchRefs != nil {
+		in, out := &in.ConfigPatchRefs, &out.ConfigPatchRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PatchTemplating != nil {
+		in, out := &in.PatchTemplating, &out.PatchTemplating
+		*out = new(PatchTemplating)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WipePolicy != nil {
+		in, out := &in.WipePolicy, &out.WipePolicy
+		*out = new(WipePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallDisk != nil {
+		in, out := &in.InstallDisk, &out.InstallDisk
+		*out = new(InstallDiskSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(HardwareValidation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllocationStrategy != nil {
+		in, out := &in.AllocationStrategy, &out.AllocationStrategy
+		*out = new(AllocationStrategy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerClassSpec.
@@ -605,6 +1154,13 @@ func (in *ServerClassStatus) DeepCopyInto(out *ServerClassStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServersReserved != nil {
+		in, out := &in.ServersReserved, &out.ServersReserved
+		*out = make([]ServerReservationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerClassStatus.
@@ -649,12 +1205,55 @@ func (in *ServerList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerReservation) DeepCopyInto(out *ServerReservation) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerReservation.
+func (in *ServerReservation) DeepCopy() *ServerReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerReservationStatus) DeepCopyInto(out *ServerReservationStatus) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerReservationStatus.
+func (in *ServerReservationStatus) DeepCopy() *ServerReservationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerReservationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
 	*out = *in
 	if in.EnvironmentRef != nil {
 		in, out := &in.EnvironmentRef, &out.EnvironmentRef
-		*out = new(v1.ObjectReference)
+		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
 	if in.Hardware != nil {
@@ -667,6 +1266,11 @@ func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
 		*out = new(BMC)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Redfish != nil {
+		in, out := &in.Redfish, &out.Redfish
+		*out = new(Redfish)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ManagementAPI != nil {
 		in, out := &in.ManagementAPI, &out.ManagementAPI
 		*out = new(ManagementAPI)
@@ -684,6 +1288,36 @@ func (in *ServerSpec) DeepCopyInto(out *ServerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConfigPatchRefs != nil {
+		in, out := &in.ConfigPatchRefs, &out.ConfigPatchRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.PatchTemplating != nil {
+		in, out := &in.PatchTemplating, &out.PatchTemplating
+		*out = new(PatchTemplating)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WipePolicy != nil {
+		in, out := &in.WipePolicy, &out.WipePolicy
+		*out = new(WipePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallDisk != nil {
+		in, out := &in.InstallDisk, &out.InstallDisk
+		*out = new(InstallDiskSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Validation != nil {
+		in, out := &in.Validation, &out.Validation
+		*out = new(HardwareValidation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReservedFor != nil {
+		in, out := &in.ReservedFor, &out.ReservedFor
+		*out = new(ServerReservation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerSpec.
@@ -708,9 +1342,23 @@ func (in *ServerStatus) DeepCopyInto(out *ServerStatus) {
 	}
 	if in.Addresses != nil {
 		in, out := &in.Addresses, &out.Addresses
-		*out = make([]v1.NodeAddress, len(*in))
+		*out = make([]corev1.NodeAddress, len(*in))
+		copy(*out, *in)
+	}
+	if in.WipeProgress != nil {
+		in, out := &in.WipeProgress, &out.WipeProgress
+		*out = new(WipeProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HardwareValidation != nil {
+		in, out := &in.HardwareValidation, &out.HardwareValidation
+		*out = make([]HardwareValidationResult, len(*in))
 		copy(*out, *in)
 	}
+	if in.LastAllocated != nil {
+		in, out := &in.LastAllocated, &out.LastAllocated
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerStatus.
@@ -778,3 +1426,102 @@ func (in *SystemInformation) DeepCopy() *SystemInformation {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TPMInformation) DeepCopyInto(out *TPMInformation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPMInformation.
+func (in *TPMInformation) DeepCopy() *TPMInformation {
+	if in == nil {
+		return nil
+	}
+	out := new(TPMInformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UKI) DeepCopyInto(out *UKI) {
+	*out = *in
+	out.Asset = in.Asset
+	if in.Signature != nil {
+		in, out := &in.Signature, &out.Signature
+		*out = new(Asset)
+		**out = **in
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(Asset)
+		**out = **in
+	}
+	if in.Shim != nil {
+		in, out := &in.Shim, &out.Shim
+		*out = new(Asset)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UKI.
+func (in *UKI) DeepCopy() *UKI {
+	if in == nil {
+		return nil
+	}
+	out := new(UKI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WipePolicy) DeepCopyInto(out *WipePolicy) {
+	*out = *in
+	if in.StorageTypeMethods != nil {
+		in, out := &in.StorageTypeMethods, &out.StorageTypeMethods
+		*out = make(map[string]WipeMethod, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]DiskSelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]DiskSelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WipePolicy.
+func (in *WipePolicy) DeepCopy() *WipePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(WipePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WipeProgress) DeepCopyInto(out *WipeProgress) {
+	*out = *in
+	in.LastProgressTime.DeepCopyInto(&out.LastProgressTime)
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]DiskWipeProgress, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WipeProgress.
+func (in *WipeProgress) DeepCopy() *WipeProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(WipeProgress)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -74,6 +74,24 @@ func wipe(ctx context.Context, client api.AgentClient, s *smbios.SMBIOS) error {
 	})
 }
 
+func reportWipeProgress(ctx context.Context, client api.AgentClient, s *smbios.SMBIOS, tracker *wipeTracker) error {
+	_, err := client.ReportWipeProgress(ctx, &api.ReportWipeProgressRequest{
+		Uuid:  s.SystemInformation.UUID,
+		Disks: tracker.snapshot(),
+	})
+
+	return err
+}
+
+func reportHardwareValidation(ctx context.Context, client api.AgentClient, s *smbios.SMBIOS, results []*api.HardwareValidationResult) error {
+	_, err := client.ReportHardwareValidation(ctx, &api.ReportHardwareValidationRequest{
+		Uuid:    s.SystemInformation.UUID,
+		Results: results,
+	})
+
+	return err
+}
+
 func connect(endpoint string) (*grpc.ClientConn, error) {
 	return grpc.NewClient(
 		endpoint,
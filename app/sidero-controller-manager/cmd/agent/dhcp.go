@@ -25,15 +25,20 @@ import (
 )
 
 func setupNetworking() error {
-	var bootMACAddress string
+	found := procfs.ProcCmdline().Get(constants.AgentMACArg).First()
+	if found == nil {
+		// the kernel command line of the agent booted as UKI is fixed, so the boot link is not known
+		log.Printf("no MAC found, looking for the link with carrier...")
 
-	if found := procfs.ProcCmdline().Get(constants.AgentMACArg).First(); found != nil {
-		bootMACAddress = *found
-	} else {
-		return fmt.Errorf("no MAC found")
+		link, err := waitForCarrier()
+		if err != nil {
+			return err
+		}
+
+		return runDHCP(link)
 	}
 
-	link, err := waitForLink(bootMACAddress)
+	link, err := waitForLink(*found)
 	if err != nil {
 		return err
 	}
@@ -45,6 +50,42 @@ func setupNetworking() error {
 	return runDHCP(link)
 }
 
+// waitForCarrier brings up all Ethernet links and returns the first one with carrier.
+func waitForCarrier() (net.Interface, error) {
+	var foundLink net.Interface
+
+	err := retry.Constant(time.Minute, retry.WithUnits(time.Second)).Retry(func() error {
+		links, err := net.Interfaces()
+		if err != nil {
+			return err
+		}
+
+		for _, link := range links {
+			if link.Flags&net.FlagLoopback != 0 || len(link.HardwareAddr) != 6 {
+				continue
+			}
+
+			if link.Flags&net.FlagUp == 0 {
+				if err = brinkLinkUp(link.Index); err != nil {
+					log.Printf("failed to bring link %q up: %s", link.Name, err)
+				}
+
+				continue
+			}
+
+			if link.Flags&net.FlagRunning != 0 {
+				foundLink = link
+
+				return nil
+			}
+		}
+
+		return retry.ExpectedErrorf("no link with carrier found")
+	})
+
+	return foundLink, err
+}
+
 func waitForLink(hwaddr string) (net.Interface, error) {
 	log.Printf("waiting for network link with MAC %q...", hwaddr)
 
@@ -100,13 +141,22 @@ func runDHCP(link net.Interface) error {
 
 	if err := retry.Constant(5*time.Minute,
 		retry.WithUnits(10*time.Second),
-		retry.WithAttemptTimeout(30*time.Second),
+		retry.WithAttemptTimeout(time.Minute),
 	).RetryWithContext(context.Background(), func(ctx context.Context) error {
 		var err error
 
-		lease, err = acquireLease(ctx, link.Name)
-		if err != nil {
-			return retry.ExpectedError(err)
+		ctx4, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		lease, err = acquireLease(ctx4, link.Name)
+		if err == nil {
+			return nil
+		}
+
+		log.Printf("failed to acquire DHCPv4 lease: %s, trying IPv6", err)
+
+		if err6 := setupIPv6(ctx, link); err6 != nil {
+			return retry.ExpectedError(fmt.Errorf("DHCPv4: %w, IPv6: %w", err, err6))
 		}
 
 		return nil
@@ -114,6 +164,11 @@ func runDHCP(link net.Interface) error {
 		return err
 	}
 
+	if lease == nil {
+		// configured via IPv6
+		return nil
+	}
+
 	log.Printf("got DHCP lease: %s", lease.ACK.Summary())
 
 	return configureNetworking(link.Index, lease)
@@ -250,17 +305,23 @@ func configureNetworking(linkIndex int, lease *nclient4.Lease) error {
 		unix.Sethostname([]byte(lease.ACK.HostName())) //nolint:errcheck
 	}
 
-	if len(lease.ACK.DNS()) > 0 {
-		log.Printf("setting DNS servers to %s", lease.ACK.DNS())
+	return configureDNS(lease.ACK.DNS())
+}
 
-		contents := strings.Join(xslices.Map(lease.ACK.DNS(),
-			func(ns net.IP) string {
-				return fmt.Sprintf("nameserver %s\n", ns)
-			}), "")
+func configureDNS(servers []net.IP) error {
+	if len(servers) == 0 {
+		return nil
+	}
 
-		if err := os.WriteFile("/etc/resolv.conf", []byte(contents), 0o777); err != nil {
-			return fmt.Errorf("error writing /etc/resolv.conf: %w", err)
-		}
+	log.Printf("setting DNS servers to %s", servers)
+
+	contents := strings.Join(xslices.Map(servers,
+		func(ns net.IP) string {
+			return fmt.Sprintf("nameserver %s\n", ns)
+		}), "")
+
+	if err := os.WriteFile("/etc/resolv.conf", []byte(contents), 0o777); err != nil {
+		return fmt.Errorf("error writing /etc/resolv.conf: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,128 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/nclient6"
+	"github.com/jsimonetti/rtnetlink"
+	"golang.org/x/sys/unix"
+)
+
+// slaacTimeout is the time to wait for the kernel to assign the address from the router advertisements.
+const slaacTimeout = 10 * time.Second
+
+// setupIPv6 configures IPv6 networking on the link.
+//
+// The address assigned by the kernel via SLAAC is used if the router advertisements are available,
+// otherwise the address is requested via DHCPv6.
+func setupIPv6(ctx context.Context, link net.Interface) error {
+	addr, err := waitForSLAAC(ctx, link)
+	if err == nil {
+		log.Printf("got SLAAC address %s", addr)
+
+		return nil
+	}
+
+	log.Printf("no SLAAC address: %s, running DHCPv6 on %q...", err, link.Name)
+
+	reply, err := acquireLease6(ctx, link.Name)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("got DHCPv6 lease: %s", reply.Summary())
+
+	return configureNetworking6(link.Index, reply)
+}
+
+func waitForSLAAC(ctx context.Context, link net.Interface) (net.IP, error) {
+	ctx, cancel := context.WithTimeout(ctx, slaacTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		addrs, err := link.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() == nil && ipNet.IP.IsGlobalUnicast() {
+				return ipNet.IP, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.New("timed out waiting for router advertisements")
+		case <-ticker.C:
+		}
+	}
+}
+
+func acquireLease6(ctx context.Context, linkName string) (*dhcpv6.Message, error) {
+	cli, err := nclient6.New(linkName)
+	if err != nil {
+		return nil, fmt.Errorf("error creating DHCPv6 client: %w", err)
+	}
+
+	//nolint:errcheck
+	defer cli.Close()
+
+	advertise, err := cli.Solicit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error soliciting DHCPv6 lease: %w", err)
+	}
+
+	reply, err := cli.Request(ctx, advertise)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting DHCPv6 lease: %w", err)
+	}
+
+	return reply, nil
+}
+
+func configureNetworking6(linkIndex int, reply *dhcpv6.Message) error {
+	iana := reply.Options.OneIANA()
+	if iana == nil || iana.Options.OneAddress() == nil {
+		return errors.New("no address in DHCPv6 reply")
+	}
+
+	address := iana.Options.OneAddress().IPv6Addr
+
+	conn, err := rtnetlink.Dial(nil)
+	if err != nil {
+		return fmt.Errorf("error dialing rtnetlink socket: %w", err)
+	}
+
+	defer conn.Close() //nolint:errcheck
+
+	// DHCPv6 doesn't provide the prefix length, on-link prefixes are announced via router advertisements
+	log.Printf("assigning address %s/128", address)
+
+	if err := conn.Address.New(&rtnetlink.AddressMessage{
+		Family:       unix.AF_INET6,
+		PrefixLength: 128,
+		Scope:        unix.RT_SCOPE_UNIVERSE,
+		Index:        uint32(linkIndex),
+		Attributes: &rtnetlink.AddressAttributes{
+			Address: address,
+		},
+	}); err != nil {
+		return fmt.Errorf("error adding address: %w", err)
+	}
+
+	return configureDNS(reply.Options.DNS())
+}
@@ -0,0 +1,353 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+	"unsafe"
+
+	"github.com/siderolabs/go-blockdevice/blockdevice"
+	"golang.org/x/sys/unix"
+)
+
+// Wipe methods as defined in the WipePolicy.
+const (
+	wipeMethodFast           = "fast"
+	wipeMethodZero           = "zero"
+	wipeMethodDiscard        = "discard"
+	wipeMethodATASecureErase = "ata-secure-erase"
+	wipeMethodNVMeFormat     = "nvme-format"
+	wipeMethodCryptoErase    = "crypto-erase"
+)
+
+// eraseTimeout is the timeout for the hardware erase commands.
+const eraseTimeout = 12 * time.Hour
+
+// discard the whole block device and zero out the partition tables.
+func discard(bd *blockdevice.BlockDevice) error {
+	size, err := bd.Size()
+	if err != nil {
+		return err
+	}
+
+	r := [2]uint64{0, size}
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, bd.Device().Fd(), blockdevice.BLKDISCARD, uintptr(unsafe.Pointer(&r[0]))); errno != 0 {
+		return fmt.Errorf("discard is not supported: %w", errno)
+	}
+
+	// discarded blocks might still read back the old contents, so make sure partition tables are gone
+	if _, err = bd.WipeRange(0, min(blockdevice.FastWipeRange, size)); err != nil {
+		return err
+	}
+
+	if size >= blockdevice.FastWipeRange*2 {
+		if _, err = bd.WipeRange(size-blockdevice.FastWipeRange, blockdevice.FastWipeRange); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SCSI generic interface, see include/scsi/sg.h.
+const (
+	sgIO = 0x2285
+
+	sgDxferNone    = -1
+	sgDxferToDev   = -2
+	sgDxferFromDev = -3
+
+	sgInfoOKMask = 0x1
+)
+
+type sgIOHdr struct {
+	InterfaceID    int32
+	DxferDirection int32
+	CmdLen         uint8
+	MxSbLen        uint8
+	IovecCount     uint16
+	DxferLen       uint32
+	Dxferp         unsafe.Pointer
+	Cmdp           unsafe.Pointer
+	Sbp            unsafe.Pointer
+	Timeout        uint32
+	Flags          uint32
+	PackID         int32
+	UsrPtr         unsafe.Pointer
+	Status         uint8
+	MaskedStatus   uint8
+	MsgStatus      uint8
+	SbLenWr        uint8
+	HostStatus     uint16
+	DriverStatus   uint16
+	Resid          int32
+	Duration       uint32
+	Info           uint32
+}
+
+// ATA commands used for the secure erase, see ATA/ATAPI Command Set (ACS).
+const (
+	ataPassThrough16 = 0x85
+
+	ataProtocolNonData = 3
+	ataProtocolPIOIn   = 4
+	ataProtocolPIOOut  = 5
+
+	ataIdentifyDevice          = 0xec
+	ataSecuritySetPassword     = 0xf1
+	ataSecurityUnlock          = 0xf2
+	ataSecurityErasePrepare    = 0xf3
+	ataSecurityEraseUnit       = 0xf4
+	ataSecurityDisablePassword = 0xf6
+
+	ataSectorSize = 512
+
+	ataSenseDescriptorFormat  = 0x72
+	ataStatusReturnDescriptor = 0x09
+
+	// IDENTIFY DEVICE word 128: security status.
+	ataSecuritySupported = 1 << 0
+	ataSecurityEnabled   = 1 << 1
+	ataSecurityLocked    = 1 << 2
+	ataSecurityFrozen    = 1 << 3
+)
+
+// ataPassword is a temporary password set to enable the security erase, it is cleared by the erase (or explicitly, if the erase fails).
+var ataPassword = []byte("sidero")
+
+// ataTaskfile describes the ATA command registers.
+type ataTaskfile struct {
+	command  byte
+	protocol byte
+	features byte
+	lbaMid   byte
+	lbaHigh  byte
+	// checkCondition requests the device to return the registers after the command completes.
+	checkCondition bool
+}
+
+// ataCommand sends ATA command via SCSI ATA PASS-THROUGH (16).
+//
+// If the checkCondition is set, the returned taskfile contains the device registers.
+func ataCommand(bd *blockdevice.BlockDevice, tf ataTaskfile, data []byte, timeout time.Duration) (ataTaskfile, error) {
+	var (
+		cdb   [16]byte
+		sense [32]byte
+	)
+
+	cdb[0] = ataPassThrough16
+	cdb[1] = tf.protocol << 1
+
+	hdr := sgIOHdr{
+		InterfaceID:    'S',
+		DxferDirection: sgDxferNone,
+		CmdLen:         uint8(len(cdb)),
+		MxSbLen:        uint8(len(sense)),
+		Cmdp:           unsafe.Pointer(&cdb[0]),
+		Sbp:            unsafe.Pointer(&sense[0]),
+		Timeout:        uint32(timeout.Milliseconds()),
+	}
+
+	if tf.checkCondition {
+		cdb[2] |= 1 << 5
+	}
+
+	if len(data) > 0 {
+		// transfer length is in the sector count field, in blocks
+		cdb[2] |= 1<<2 | 2
+		cdb[6] = uint8(len(data) / ataSectorSize)
+
+		hdr.DxferLen = uint32(len(data))
+		hdr.Dxferp = unsafe.Pointer(&data[0])
+
+		switch tf.protocol {
+		case ataProtocolPIOIn:
+			cdb[2] |= 1 << 3
+			hdr.DxferDirection = sgDxferFromDev
+		case ataProtocolPIOOut:
+			hdr.DxferDirection = sgDxferToDev
+		}
+	}
+
+	cdb[4] = tf.features
+	cdb[10] = tf.lbaMid
+	cdb[12] = tf.lbaHigh
+	cdb[14] = tf.command
+
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, bd.Device().Fd(), sgIO, uintptr(unsafe.Pointer(&hdr)))
+
+	runtime.KeepAlive(data)
+	runtime.KeepAlive(&cdb)
+	runtime.KeepAlive(&sense)
+
+	if errno != 0 {
+		return ataTaskfile{}, fmt.Errorf("ATA command 0x%x failed: %w", tf.command, errno)
+	}
+
+	// descriptor format sense data with the ATA Status Return descriptor
+	if tf.checkCondition && sense[0] == ataSenseDescriptorFormat && sense[8] == ataStatusReturnDescriptor {
+		return ataTaskfile{
+			command: tf.command,
+			lbaMid:  sense[8+9],
+			lbaHigh: sense[8+11],
+		}, nil
+	}
+
+	if hdr.Info&sgInfoOKMask != 0 {
+		return ataTaskfile{}, fmt.Errorf("ATA command 0x%x failed: status 0x%x, host status 0x%x, driver status 0x%x", tf.command, hdr.Status, hdr.HostStatus, hdr.DriverStatus)
+	}
+
+	return ataTaskfile{command: tf.command}, nil
+}
+
+// ataSecureErase erases the disk with ATA SECURITY ERASE UNIT command.
+func ataSecureErase(bd *blockdevice.BlockDevice) error {
+	identify := make([]byte, ataSectorSize)
+
+	if _, err := ataCommand(bd, ataTaskfile{command: ataIdentifyDevice, protocol: ataProtocolPIOIn}, identify, time.Minute); err != nil {
+		return err
+	}
+
+	security := binary.LittleEndian.Uint16(identify[128*2:])
+
+	if security&ataSecuritySupported == 0 {
+		return errors.New("ATA security feature set is not supported")
+	}
+
+	if security&ataSecurityFrozen != 0 {
+		return errors.New("ATA security is frozen")
+	}
+
+	// word 0: identifier (0 - user password), words 1-16: password
+	password := make([]byte, ataSectorSize)
+	copy(password[2:34], ataPassword)
+
+	// the password is left set if the previous erase was interrupted (e.g. the server was power cycled),
+	// and the device is locked with it after the power cycle
+	if security&ataSecurityEnabled != 0 {
+		if err := ataSecurityDisable(bd, password, security&ataSecurityLocked != 0); err != nil {
+			return fmt.Errorf("ATA security is enabled, and the password can't be cleared: %w", err)
+		}
+	}
+
+	if _, err := ataCommand(bd, ataTaskfile{command: ataSecuritySetPassword, protocol: ataProtocolPIOOut}, password, time.Minute); err != nil {
+		return err
+	}
+
+	if _, err := ataCommand(bd, ataTaskfile{command: ataSecurityErasePrepare, protocol: ataProtocolNonData}, nil, time.Minute); err != nil {
+		return errors.Join(err, ataSecurityDisable(bd, password, false))
+	}
+
+	if _, err := ataCommand(bd, ataTaskfile{command: ataSecurityEraseUnit, protocol: ataProtocolPIOOut}, password, eraseTimeout); err != nil {
+		// the password is cleared only by the successful erase, so it should be removed not to leave the device locked
+		return errors.Join(err, ataSecurityDisable(bd, password, false))
+	}
+
+	return nil
+}
+
+// ataSecurityDisable clears the password set for the security erase, unlocking the device first if it is locked.
+func ataSecurityDisable(bd *blockdevice.BlockDevice, password []byte, locked bool) error {
+	if locked {
+		if _, err := ataCommand(bd, ataTaskfile{command: ataSecurityUnlock, protocol: ataProtocolPIOOut}, password, time.Minute); err != nil {
+			return fmt.Errorf("failed to unlock: %w", err)
+		}
+	}
+
+	if _, err := ataCommand(bd, ataTaskfile{command: ataSecurityDisablePassword, protocol: ataProtocolPIOOut}, password, time.Minute); err != nil {
+		return fmt.Errorf("failed to disable the password: %w", err)
+	}
+
+	return nil
+}
+
+// NVMe admin commands, see include/uapi/linux/nvme_ioctl.h and NVM Express Base Specification.
+const (
+	nvmeIoctlID       = 0x4e40
+	nvmeIoctlAdminCmd = 0xc0484e41
+
+	nvmeAdminIdentify  = 0x06
+	nvmeAdminFormatNVM = 0x80
+
+	nvmeIdentifySize = 4096
+
+	// Secure Erase Settings of the Format NVM command.
+	nvmeSESUserDataErase = 1
+	nvmeSESCryptoErase   = 2
+)
+
+type nvmeAdminCommand struct {
+	Opcode      uint8
+	Flags       uint8
+	Rsvd1       uint16
+	NSID        uint32
+	Cdw2        uint32
+	Cdw3        uint32
+	Metadata    uint64
+	Addr        uint64
+	MetadataLen uint32
+	DataLen     uint32
+	Cdw10       uint32
+	Cdw11       uint32
+	Cdw12       uint32
+	Cdw13       uint32
+	Cdw14       uint32
+	Cdw15       uint32
+	TimeoutMs   uint32
+	Result      uint32
+}
+
+func nvmeAdmin(bd *blockdevice.BlockDevice, cmd *nvmeAdminCommand, data []byte) error {
+	if len(data) > 0 {
+		cmd.Addr = uint64(uintptr(unsafe.Pointer(&data[0])))
+		cmd.DataLen = uint32(len(data))
+	}
+
+	status, _, errno := unix.Syscall(unix.SYS_IOCTL, bd.Device().Fd(), nvmeIoctlAdminCmd, uintptr(unsafe.Pointer(cmd)))
+
+	runtime.KeepAlive(data)
+
+	if errno != 0 {
+		return fmt.Errorf("NVMe admin command 0x%x failed: %w", cmd.Opcode, errno)
+	}
+
+	if status != 0 {
+		return fmt.Errorf("NVMe admin command 0x%x failed: status 0x%x", cmd.Opcode, status)
+	}
+
+	return nil
+}
+
+// nvmeFormat erases the namespace with NVMe Format NVM command keeping the current LBA format.
+func nvmeFormat(bd *blockdevice.BlockDevice, ses uint32) error {
+	nsid, _, errno := unix.Syscall(unix.SYS_IOCTL, bd.Device().Fd(), nvmeIoctlID, 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to get NVMe namespace ID: %w", errno)
+	}
+
+	identify := make([]byte, nvmeIdentifySize)
+
+	if err := nvmeAdmin(bd, &nvmeAdminCommand{
+		Opcode: nvmeAdminIdentify,
+		NSID:   uint32(nsid),
+	}, identify); err != nil {
+		return err
+	}
+
+	// FLBAS: bits 0-3 are the index of the LBA format in use
+	lbaf := uint32(identify[26] & 0xf)
+
+	return nvmeAdmin(bd, &nvmeAdminCommand{
+		Opcode:    nvmeAdminFormatNVM,
+		NSID:      uint32(nsid),
+		Cdw10:     ses<<9 | lbaf,
+		TimeoutMs: uint32(eraseTimeout.Milliseconds()),
+	}, nil)
+}
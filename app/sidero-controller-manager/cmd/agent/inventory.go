@@ -0,0 +1,179 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/siderolabs/go-blockdevice/blockdevice/util/disk"
+	"github.com/siderolabs/go-smbios/smbios"
+	"golang.org/x/sys/unix"
+
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/api"
+)
+
+const (
+	sysfsPCIDevices = "/sys/bus/pci/devices"
+	sysfsNet        = "/sys/class/net"
+	sysfsTPM        = "/sys/class/tpm"
+
+	// pciClassDisplay is the PCI base class of display controllers (GPUs).
+	pciClassDisplay = "0x03"
+)
+
+// readSysfs reads the sysfs attribute, empty string is returned if the attribute can't be read.
+func readSysfs(parts ...string) string {
+	contents, err := os.ReadFile(filepath.Join(parts...))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(contents))
+}
+
+// readSysfsLink returns the name of the sysfs symlink target, e.g. the driver name.
+func readSysfsLink(parts ...string) string {
+	target, err := os.Readlink(filepath.Join(parts...))
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Base(target)
+}
+
+func MapBIOSInformation(s *smbios.SMBIOS) *api.BIOSInformation {
+	return &api.BIOSInformation{
+		Vendor:      s.BIOSInformation.Vendor,
+		Version:     s.BIOSInformation.Version,
+		ReleaseDate: s.BIOSInformation.ReleaseDate,
+	}
+}
+
+// MapPCIInformation lists PCI devices from the sysfs.
+func MapPCIInformation(sysfsPCI string) *api.PCIInformation {
+	entries, err := os.ReadDir(sysfsPCI)
+	if err != nil {
+		log.Printf("encountered error listing PCI devices: %q", err)
+
+		return nil
+	}
+
+	var (
+		gpuCount uint32
+		devices  = make([]*api.PCIDevice, 0, len(entries))
+	)
+
+	for _, entry := range entries {
+		address := entry.Name()
+
+		device := &api.PCIDevice{
+			Address:           address,
+			VendorId:          readSysfs(sysfsPCI, address, "vendor"),
+			DeviceId:          readSysfs(sysfsPCI, address, "device"),
+			SubsystemVendorId: readSysfs(sysfsPCI, address, "subsystem_vendor"),
+			SubsystemDeviceId: readSysfs(sysfsPCI, address, "subsystem_device"),
+			Class:             readSysfs(sysfsPCI, address, "class"),
+			Driver:            readSysfsLink(sysfsPCI, address, "driver"),
+		}
+
+		if strings.HasPrefix(device.Class, pciClassDisplay) {
+			gpuCount++
+		}
+
+		devices = append(devices, device)
+	}
+
+	return &api.PCIInformation{
+		DeviceCount: uint32(len(devices)),
+		GpuCount:    gpuCount,
+		Devices:     devices,
+	}
+}
+
+// MapTPMInformation detects the TPM device from the sysfs.
+func MapTPMInformation(sysfsTPM string) *api.TPMInformation {
+	tpm := filepath.Join(sysfsTPM, "tpm0")
+
+	if _, err := os.Stat(tpm); err != nil {
+		return &api.TPMInformation{}
+	}
+
+	version := "1.2"
+
+	if readSysfs(tpm, "tpm_version_major") == "2" {
+		version = "2.0"
+	}
+
+	return &api.TPMInformation{
+		Present: true,
+		Version: version,
+	}
+}
+
+// MapStorageTransport detects the transport (bus) of the disk by the device name and the sysfs bus path.
+func MapStorageTransport(d *disk.Disk) string {
+	name := filepath.Base(d.DeviceName)
+
+	switch {
+	case strings.HasPrefix(name, "nvme"):
+		return "nvme"
+	case strings.HasPrefix(name, "mmcblk"):
+		return "mmc"
+	case strings.Contains(d.BusPath, "/usb"):
+		return "usb"
+	case strings.HasPrefix(name, "vd") || strings.Contains(d.BusPath, "virtio"):
+		return "virtio"
+	case strings.Contains(d.BusPath, "/ata"):
+		return "sata"
+	case strings.Contains(d.BusPath, "/end_device-") || strings.Contains(d.BusPath, "sas"):
+		return "sas"
+	default:
+		return "scsi"
+	}
+}
+
+// mapStorageHealth reads the SMART health of the disk, empty string is returned if it's not available.
+func mapStorageHealth(d *disk.Disk) string {
+	health, err := diskSMARTHealth(d)
+	if err != nil {
+		log.Printf("encountered error reading SMART health of %q: %q", d.DeviceName, err)
+
+		return ""
+	}
+
+	return health
+}
+
+// mapNetworkDriver returns the driver name and the firmware version of the network interface.
+//
+// If the ethtool request fails, the driver name is read from the sysfs.
+func mapNetworkDriver(sysfsNet, name string) (driver, firmwareVersion string) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM, 0)
+	if err == nil {
+		defer unix.Close(fd) //nolint:errcheck
+
+		var info *unix.EthtoolDrvinfo
+
+		if info, err = unix.IoctlGetEthtoolDrvinfo(fd, name); err == nil {
+			return unix.ByteSliceToString(info.Driver[:]), unix.ByteSliceToString(info.Fw_version[:])
+		}
+	}
+
+	return readSysfsLink(sysfsNet, name, "device", "driver"), ""
+}
+
+// mapLinkSpeed returns the link speed of the network interface in Mbps, zero is returned if the speed is unknown.
+func mapLinkSpeed(sysfsNet, name string) uint32 {
+	speed, err := strconv.Atoi(readSysfs(sysfsNet, name, "speed"))
+	if err != nil || speed < 0 {
+		return 0
+	}
+
+	return uint32(speed)
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/siderolabs/go-blockdevice/blockdevice"
@@ -24,6 +25,8 @@ import (
 
 const (
 	debugAddr = ":9991"
+
+	wipeProgressInterval = time.Minute
 )
 
 func mainFunc() error {
@@ -105,8 +108,24 @@ func mainFunc() error {
 
 		heartbeatInterval := (time.Duration(createResp.RebootTimeout) * time.Second) / 3
 
+		policy := mapWipePolicy(createResp)
+
+		// full wipe and wipe policies report progress instead of heartbeats, so report it more often
+		reportProgress := createResp.GetWipePolicy() != nil || !createResp.GetInsecureWipe()
+
+		if reportProgress {
+			heartbeatInterval = min(heartbeatInterval, wipeProgressInterval)
+		}
+
+		// wipe progress doesn't change while the hardware is being validated, so heartbeats are sent instead
+		var validating atomic.Bool
+
+		validating.Store(createResp.GetValidation() != nil)
+
 		ticker := time.NewTicker(heartbeatInterval)
 
+		tracker := &wipeTracker{}
+
 		wg.Add(1)
 
 		go func() {
@@ -115,8 +134,20 @@ func mainFunc() error {
 			for {
 				callCtx, cancel := context.WithTimeout(ctx, heartbeatInterval)
 
-				if _, err := client.Heartbeat(callCtx, &api.HeartbeatRequest{Uuid: s.SystemInformation.UUID}); err != nil {
-					log.Printf("Failed to send wipe heartbeat %s", err)
+				progressReported := reportProgress && !validating.Load()
+
+				if progressReported {
+					if err := reportWipeProgress(callCtx, client, s, tracker); err != nil {
+						log.Printf("Failed to report wipe progress %s", err)
+					}
+				}
+
+				// the controller considers the wipe stuck if the progress doesn't grow,
+				// so the heartbeats are sent while the hardware erase without the progress reporting runs
+				if !progressReported || tracker.erasingAtOnce() {
+					if _, err := client.Heartbeat(callCtx, &api.HeartbeatRequest{Uuid: s.SystemInformation.UUID}); err != nil {
+						log.Printf("Failed to send wipe heartbeat %s", err)
+					}
 				}
 
 				cancel()
@@ -135,6 +166,20 @@ func mainFunc() error {
 			wg.Wait()
 		}()
 
+		if createResp.GetValidation() != nil {
+			log.Println("Validating hardware")
+
+			results := validateHardware(ctx, createResp.GetValidation(), disks)
+
+			if err := reportHardwareValidation(ctx, client, s, results); err != nil {
+				return err
+			}
+
+			validating.Store(false)
+
+			log.Println("Hardware validation complete")
+		}
+
 		for _, d := range disks {
 			func(disk *disk.Disk) {
 				eg.Go(func() error {
@@ -146,6 +191,13 @@ func mainFunc() error {
 						return nil
 					}
 
+					method, skipReason := diskWipeMethod(policy, disk)
+					if method == "" {
+						log.Printf("Skipping %s: %s", path, skipReason)
+
+						return nil
+					}
+
 					log.Printf("Resetting %s", path)
 
 					bd, err := blockdevice.Open(path)
@@ -155,21 +207,17 @@ func mainFunc() error {
 						return nil
 					}
 
-					if createResp.GetInsecureWipe() {
-						if err = bd.FastWipe(); err != nil {
-							return fmt.Errorf("failed wiping %q: %w", path, err)
-						}
+					progress := tracker.add(path)
 
-						log.Printf("Fast wiped %s", path)
-					} else {
-						method, err := bd.Wipe()
-						if err != nil {
-							return fmt.Errorf("failed wiping %q: %w", path, err)
-						}
+					usedMethod, err := wipeDisk(bd, method, tracker, progress)
+					if err != nil {
+						bd.Close() //nolint:errcheck
 
-						log.Printf("Wiped %s with %s", path, method)
+						return fmt.Errorf("failed wiping %q with %s: %w", path, method, err)
 					}
 
+					log.Printf("Wiped %s with %s", path, usedMethod)
+
 					return bd.Close()
 				})
 			}(d)
@@ -179,6 +227,12 @@ func mainFunc() error {
 			return err
 		}
 
+		if reportProgress {
+			if err := reportWipeProgress(ctx, client, s, tracker); err != nil {
+				log.Printf("Failed to report final wipe progress %s", err)
+			}
+		}
+
 		if err := wipe(ctx, client, s); err != nil {
 			return err
 		}
@@ -6,10 +6,180 @@
 package main
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/siderolabs/go-blockdevice/blockdevice/util/disk"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/api"
 )
 
 //nolint:unparam
 func TestMapHardwareInformation_DoesNotPanic(t *testing.T) {
 	MapHardwareInformation(nil, nil, nil)
 }
+
+func TestDiskWipeMethod(t *testing.T) {
+	nvme := &disk.Disk{
+		DeviceName: "/dev/nvme0n1",
+		Model:      "Samsung SSD 980 PRO",
+		Serial:     "S5GXNF0R123456",
+		Type:       disk.TypeNVMe,
+	}
+
+	hdd := &disk.Disk{
+		DeviceName: "/dev/sda",
+		Model:      "ST4000NM0035",
+		Serial:     "ZC1ABCDE",
+		Type:       disk.TypeHDD,
+	}
+
+	usb := &disk.Disk{
+		DeviceName: "/dev/sdb",
+		Model:      "Ultra USB 3.0",
+		Type:       disk.TypeSSD,
+		BusPath:    "/pci0000:00/0000:00:14.0/usb2/2-1",
+	}
+
+	policy := mapWipePolicy(&api.CreateServerResponse{
+		InsecureWipe: true,
+		WipePolicy: &api.WipePolicy{
+			StorageTypeMethods: map[string]string{
+				"NVMe": wipeMethodCryptoErase,
+			},
+			Exclude: []*api.DiskSelector{
+				{Serial: "ZC1*"},
+			},
+			SkipBootMedia: true,
+		},
+	})
+
+	for _, tt := range []struct {
+		disk           *disk.Disk
+		expectedMethod string
+	}{
+		{nvme, wipeMethodCryptoErase},
+		{hdd, ""},
+		{usb, ""},
+	} {
+		method, skipReason := diskWipeMethod(policy, tt.disk)
+		assert.Equal(t, tt.expectedMethod, method, tt.disk.DeviceName)
+
+		if tt.expectedMethod == "" {
+			assert.NotEmpty(t, skipReason)
+		}
+	}
+
+	policy = mapWipePolicy(&api.CreateServerResponse{
+		WipePolicy: &api.WipePolicy{
+			Include: []*api.DiskSelector{
+				{Model: "ST4000*"},
+			},
+		},
+	})
+
+	method, _ := diskWipeMethod(policy, hdd)
+	assert.Equal(t, wipeMethodZero, method)
+
+	method, _ = diskWipeMethod(policy, nvme)
+	assert.Empty(t, method)
+
+	method, _ = diskWipeMethod(mapWipePolicy(&api.CreateServerResponse{InsecureWipe: true}), usb)
+	assert.Equal(t, wipeMethodFast, method)
+}
+
+func TestTestMemory(t *testing.T) {
+	assert.NoError(t, testMemory(make([]uint64, 1<<16), 2))
+}
+
+func TestCheckLinkSpeed(t *testing.T) {
+	sysfsNet := t.TempDir()
+
+	for iface, attrs := range map[string]map[string]string{
+		"eth0": {"device": "", "carrier": "1", "speed": "10000"},
+		"eth1": {"device": "", "carrier": "1", "speed": "1000"},
+		"eth2": {"device": "", "carrier": "0", "speed": "-1"},
+		"lo":   {"carrier": "1"},
+	} {
+		for name, contents := range attrs {
+			assert.NoError(t, os.MkdirAll(filepath.Join(sysfsNet, iface), 0o755))
+			assert.NoError(t, os.WriteFile(filepath.Join(sysfsNet, iface, name), []byte(contents+"\n"), 0o644))
+		}
+	}
+
+	message, err := checkLinkSpeed(sysfsNet, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, "eth0: 10000 Mbps, eth1: 1000 Mbps", message)
+
+	_, err = checkLinkSpeed(sysfsNet, 10000)
+	assert.EqualError(t, err, "link speed is below 10000 Mbps: eth1: 1000 Mbps")
+
+	_, err = checkLinkSpeed(t.TempDir(), 1000)
+	assert.Error(t, err)
+}
+
+func TestCheckCPU(t *testing.T) {
+	_, err := checkCPU(context.Background(), 100*time.Millisecond)
+	assert.NoError(t, err)
+}
+
+func TestMapPCIInformation(t *testing.T) {
+	sysfsPCI := t.TempDir()
+
+	for address, attrs := range map[string]map[string]string{
+		"0000:00:02.0": {"vendor": "0x8086", "device": "0x3e92", "class": "0x030000"},
+		"0000:01:00.0": {"vendor": "0x15b3", "device": "0x1017", "class": "0x020000", "subsystem_vendor": "0x15b3", "subsystem_device": "0x0020"},
+	} {
+		for name, contents := range attrs {
+			assert.NoError(t, os.MkdirAll(filepath.Join(sysfsPCI, address), 0o755))
+			assert.NoError(t, os.WriteFile(filepath.Join(sysfsPCI, address, name), []byte(contents+"\n"), 0o644))
+		}
+	}
+
+	assert.NoError(t, os.Symlink("../../../bus/pci/drivers/mlx5_core", filepath.Join(sysfsPCI, "0000:01:00.0", "driver")))
+
+	pci := MapPCIInformation(sysfsPCI)
+
+	assert.EqualValues(t, 2, pci.DeviceCount)
+	assert.EqualValues(t, 1, pci.GpuCount)
+	assert.Equal(t, "0000:01:00.0", pci.Devices[1].Address)
+	assert.Equal(t, "0x15b3", pci.Devices[1].VendorId)
+	assert.Equal(t, "0x0020", pci.Devices[1].SubsystemDeviceId)
+	assert.Equal(t, "mlx5_core", pci.Devices[1].Driver)
+	assert.Empty(t, pci.Devices[0].Driver)
+}
+
+func TestMapStorageTransport(t *testing.T) {
+	for _, tt := range []struct {
+		disk      disk.Disk
+		transport string
+	}{
+		{disk.Disk{DeviceName: "/dev/nvme0n1"}, "nvme"},
+		{disk.Disk{DeviceName: "/dev/mmcblk0"}, "mmc"},
+		{disk.Disk{DeviceName: "/dev/vda", BusPath: "/pci0000:00/0000:00:04.0/virtio1/"}, "virtio"},
+		{disk.Disk{DeviceName: "/dev/sda", BusPath: "/pci0000:00/0000:00:1f.2/ata1/host0/target0:0:0/0:0:0:0/"}, "sata"},
+		{disk.Disk{DeviceName: "/dev/sdb", BusPath: "/pci0000:00/0000:00:14.0/usb2/2-1/2-1:1.0/host6/target6:0:0/6:0:0:0/"}, "usb"},
+		{disk.Disk{DeviceName: "/dev/sdc", BusPath: "/pci0000:00/0000:03:00.0/host0/port-0:0/end_device-0:0/target0:0:0/0:0:0:0/"}, "sas"},
+		{disk.Disk{DeviceName: "/dev/sdd", BusPath: "/platform/host0/target0:0:0/0:0:0:0/"}, "scsi"},
+	} {
+		assert.Equal(t, tt.transport, MapStorageTransport(&tt.disk), tt.disk.DeviceName)
+	}
+}
+
+func TestMapTPMInformation(t *testing.T) {
+	sysfsTPM := t.TempDir()
+
+	assert.False(t, MapTPMInformation(sysfsTPM).Present)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(sysfsTPM, "tpm0"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(sysfsTPM, "tpm0", "tpm_version_major"), []byte("2\n"), 0o644))
+
+	tpm := MapTPMInformation(sysfsTPM)
+
+	assert.True(t, tpm.Present)
+	assert.Equal(t, "2.0", tpm.Version)
+}
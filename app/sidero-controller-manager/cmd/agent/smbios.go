@@ -22,6 +22,9 @@ func MapHardwareInformation(s *smbios.SMBIOS, disks []*disk.Disk, interfaces []n
 			Memory:  MapMemoryInformation(s),
 			Storage: MapStorageInformation(disks),
 			Network: MapNetworkInformation(interfaces),
+			Pci:     MapPCIInformation(sysfsPCIDevices),
+			Bios:    MapBIOSInformation(s),
+			Tpm:     MapTPMInformation(sysfsTPM),
 		}
 	}
 
@@ -119,30 +122,18 @@ func MapStorageInformation(s []*disk.Disk) *api.StorageInformation {
 	for _, v := range s {
 		totalSize += v.Size
 
-		var storageType api.StorageType
-
-		switch v.Type.String() {
-		case "ssd":
-			storageType = api.StorageType_SSD
-		case "hdd":
-			storageType = api.StorageType_HDD
-		case "nvme":
-			storageType = api.StorageType_NVMe
-		case "sd":
-			storageType = api.StorageType_SD
-		default:
-			storageType = api.StorageType_Unknown
-		}
-
 		storageDevice := &api.StorageDevice{
-			Model:      v.Model,
-			Serial:     v.Serial,
-			Type:       storageType,
-			Size:       v.Size,
-			Name:       v.Name,
-			DeviceName: v.DeviceName,
-			Uuid:       v.UUID,
-			Wwid:       v.WWID,
+			Model:       v.Model,
+			Serial:      v.Serial,
+			Type:        MapStorageType(v),
+			Size:        v.Size,
+			Name:        v.Name,
+			DeviceName:  v.DeviceName,
+			Uuid:        v.UUID,
+			Wwid:        v.WWID,
+			Rotational:  v.Type == disk.TypeHDD,
+			Transport:   MapStorageTransport(v),
+			SmartHealth: mapStorageHealth(v),
 		}
 
 		devices = append(devices, storageDevice)
@@ -155,6 +146,21 @@ func MapStorageInformation(s []*disk.Disk) *api.StorageInformation {
 	}
 }
 
+func MapStorageType(d *disk.Disk) api.StorageType {
+	switch d.Type.String() {
+	case "ssd":
+		return api.StorageType_SSD
+	case "hdd":
+		return api.StorageType_HDD
+	case "nvme":
+		return api.StorageType_NVMe
+	case "sd":
+		return api.StorageType_SD
+	default:
+		return api.StorageType_Unknown
+	}
+}
+
 func MapNetworkInformation(s []net.Interface) *api.NetworkInformation {
 	interfaces := make([]*api.NetworkInterface, 0, len(s))
 
@@ -176,13 +182,18 @@ func MapNetworkInformation(s []net.Interface) *api.NetworkInformation {
 			addresses = append(addresses, a.String())
 		}
 
+		driver, firmwareVersion := mapNetworkDriver(sysfsNet, v.Name)
+
 		networkInterface := &api.NetworkInterface{
-			Index:     uint32(v.Index),
-			Name:      v.Name,
-			Flags:     v.Flags.String(),
-			Mtu:       uint32(v.MTU),
-			Mac:       v.HardwareAddr.String(),
-			Addresses: addresses,
+			Index:           uint32(v.Index),
+			Name:            v.Name,
+			Flags:           v.Flags.String(),
+			Mtu:             uint32(v.MTU),
+			Mac:             v.HardwareAddr.String(),
+			Addresses:       addresses,
+			Driver:          driver,
+			FirmwareVersion: firmwareVersion,
+			LinkSpeed:       mapLinkSpeed(sysfsNet, v.Name),
 		}
 
 		interfaces = append(interfaces, networkInterface)
@@ -0,0 +1,336 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/siderolabs/go-blockdevice/blockdevice"
+	"github.com/siderolabs/go-blockdevice/blockdevice/util/disk"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sys/unix"
+
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/api"
+)
+
+const (
+	// memoryTestReserve is the amount of the available memory not tested to keep the agent running.
+	memoryTestReserve = 256 << 20
+
+	ataSMART             = 0xb0
+	ataSMARTReturnStatus = 0xda
+	ataSMARTLBAMid       = 0x4f
+	ataSMARTLBAHigh      = 0xc2
+	ataSMARTFailLBAMid   = 0xf4
+	ataSMARTFailLBAHigh  = 0x2c
+
+	nvmeAdminGetLogPage = 0x02
+	nvmeLogSMART        = 0x02
+	nvmeLogSMARTSize    = 512
+	nvmeNSIDAll         = 0xffffffff
+)
+
+// validateHardware runs the hardware validation checks enabled in the request.
+func validateHardware(ctx context.Context, validation *api.HardwareValidation, disks []*disk.Disk) []*api.HardwareValidationResult {
+	var results []*api.HardwareValidationResult
+
+	report := func(name string, err error, message string) {
+		result := &api.HardwareValidationResult{
+			Name:    name,
+			Passed:  err == nil,
+			Message: message,
+		}
+
+		if err != nil {
+			result.Message = err.Error()
+		}
+
+		log.Printf("Hardware validation %s: passed %v, %s", name, result.Passed, result.Message)
+
+		results = append(results, result)
+	}
+
+	if passes := validation.GetMemoryTestPasses(); passes > 0 {
+		message, err := checkMemory(int(passes))
+		report("memory", err, message)
+	}
+
+	if validation.GetDiskHealth() {
+		for _, d := range disks {
+			message, err := checkDiskHealth(d)
+			report("disk-health:"+d.DeviceName, err, message)
+		}
+	}
+
+	if minSpeed := validation.GetMinLinkSpeed(); minSpeed > 0 {
+		message, err := checkLinkSpeed(sysfsNet, int(minSpeed))
+		report("link-speed", err, message)
+	}
+
+	if duration := time.Duration(validation.GetCpuStressDuration() * float64(time.Second)); duration > 0 {
+		message, err := checkCPU(ctx, duration)
+		report("cpu-stress", err, message)
+	}
+
+	return results
+}
+
+// memAvailable returns the amount of available memory from /proc/meminfo.
+func memAvailable() (uint64, error) {
+	contents, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		return kb << 10, nil
+	}
+
+	return 0, errors.New("MemAvailable not found in /proc/meminfo")
+}
+
+// checkMemory writes and verifies test patterns over the available memory.
+func checkMemory(passes int) (string, error) {
+	available, err := memAvailable()
+	if err != nil {
+		return "", err
+	}
+
+	if available <= memoryTestReserve {
+		return "", fmt.Errorf("not enough available memory to test: %d MiB", available>>20)
+	}
+
+	size := int((available - memoryTestReserve) &^ uint64(unix.Getpagesize()-1))
+
+	mem, err := unix.Mmap(-1, 0, size, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE|unix.MAP_ANONYMOUS|unix.MAP_POPULATE)
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate %d MiB for memory test: %w", size>>20, err)
+	}
+
+	defer unix.Munmap(mem) //nolint:errcheck
+
+	words := unsafe.Slice((*uint64)(unsafe.Pointer(&mem[0])), len(mem)/8)
+
+	if err = testMemory(words, passes); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("tested %d MiB in %d passes", size>>20, passes), nil
+}
+
+// testMemory runs the pattern tests over the memory.
+//
+// Each pass writes and verifies the fixed bit patterns and the address of the word in the word itself.
+func testMemory(words []uint64, passes int) error {
+	patterns := []func(i int) uint64{
+		func(int) uint64 { return 0x5555555555555555 },
+		func(int) uint64 { return 0xaaaaaaaaaaaaaaaa },
+		func(i int) uint64 { return uint64(uintptr(unsafe.Pointer(&words[i]))) },
+	}
+
+	for range passes {
+		for _, pattern := range patterns {
+			for i := range words {
+				words[i] = pattern(i)
+			}
+
+			for i := range words {
+				if expected := pattern(i); words[i] != expected {
+					return fmt.Errorf("memory error at 0x%x: expected 0x%016x, got 0x%016x", uintptr(unsafe.Pointer(&words[i])), expected, words[i])
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// SMART overall health status.
+const (
+	smartHealthPassed = "PASSED"
+	smartHealthFailed = "FAILED"
+)
+
+// diskSMARTHealth reads the SMART overall health status of the disk.
+//
+// Empty status is returned if the disk doesn't support SMART.
+func diskSMARTHealth(d *disk.Disk) (string, error) {
+	bd, err := blockdevice.Open(d.DeviceName)
+	if err != nil {
+		return "", err
+	}
+
+	defer bd.Close() //nolint:errcheck
+
+	if d.Type == disk.TypeNVMe {
+		smartLog := make([]byte, nvmeLogSMARTSize)
+
+		if err = nvmeAdmin(bd, &nvmeAdminCommand{
+			Opcode: nvmeAdminGetLogPage,
+			NSID:   nvmeNSIDAll,
+			Cdw10:  (nvmeLogSMARTSize/4-1)<<16 | nvmeLogSMART,
+		}, smartLog); err != nil {
+			return "", nil //nolint:nilerr
+		}
+
+		// critical warning bits: spare, temperature, reliability, read-only, volatile backup
+		if smartLog[0] != 0 {
+			return smartHealthFailed, nil
+		}
+
+		return smartHealthPassed, nil
+	}
+
+	tf, err := ataCommand(bd, ataTaskfile{
+		command:        ataSMART,
+		protocol:       ataProtocolNonData,
+		features:       ataSMARTReturnStatus,
+		lbaMid:         ataSMARTLBAMid,
+		lbaHigh:        ataSMARTLBAHigh,
+		checkCondition: true,
+	}, nil, time.Minute)
+	if err != nil {
+		// virtual disks and some controllers don't support ATA pass-through
+		return "", nil //nolint:nilerr
+	}
+
+	if tf.lbaMid == ataSMARTFailLBAMid && tf.lbaHigh == ataSMARTFailLBAHigh {
+		return smartHealthFailed, nil
+	}
+
+	return smartHealthPassed, nil
+}
+
+// checkDiskHealth checks the SMART health status of the disk.
+func checkDiskHealth(d *disk.Disk) (string, error) {
+	health, err := diskSMARTHealth(d)
+	if err != nil {
+		return "", err
+	}
+
+	switch health {
+	case smartHealthPassed:
+		return "SMART health check passed", nil
+	case smartHealthFailed:
+		return "", errors.New("SMART health check failed, the disk is failing")
+	default:
+		return "SMART is not supported", nil
+	}
+}
+
+// checkLinkSpeed verifies that all connected physical network interfaces have the link speed of at least minSpeed Mbps.
+func checkLinkSpeed(sysfsNet string, minSpeed int) (string, error) {
+	entries, err := os.ReadDir(sysfsNet)
+	if err != nil {
+		return "", err
+	}
+
+	readAttr := func(iface, name string) string {
+		contents, err := os.ReadFile(filepath.Join(sysfsNet, iface, name))
+		if err != nil {
+			return ""
+		}
+
+		return strings.TrimSpace(string(contents))
+	}
+
+	var connected, slow []string
+
+	for _, entry := range entries {
+		iface := entry.Name()
+
+		// skip virtual interfaces
+		if _, err := os.Stat(filepath.Join(sysfsNet, iface, "device")); err != nil {
+			continue
+		}
+
+		if readAttr(iface, "carrier") != "1" {
+			continue
+		}
+
+		speed, err := strconv.Atoi(readAttr(iface, "speed"))
+		if err != nil {
+			continue
+		}
+
+		connected = append(connected, fmt.Sprintf("%s: %d Mbps", iface, speed))
+
+		if speed < minSpeed {
+			slow = append(slow, fmt.Sprintf("%s: %d Mbps", iface, speed))
+		}
+	}
+
+	if len(connected) == 0 {
+		return "", errors.New("no connected network interfaces with known link speed")
+	}
+
+	if len(slow) > 0 {
+		return "", fmt.Errorf("link speed is below %d Mbps: %s", minSpeed, strings.Join(slow, ", "))
+	}
+
+	return strings.Join(connected, ", "), nil
+}
+
+// checkCPU loads all CPU cores for the duration verifying the computation results.
+func checkCPU(ctx context.Context, duration time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	seed := make([]byte, 1<<16)
+
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	expected := sha256.Sum256(seed)
+
+	var eg errgroup.Group
+
+	cpus := runtime.NumCPU()
+
+	for range cpus {
+		eg.Go(func() error {
+			for ctx.Err() == nil {
+				if sum := sha256.Sum256(seed); sum != expected {
+					return fmt.Errorf("computation error: expected %x, got %x", expected, sum)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("stressed %d CPUs for %s", cpus, duration), nil
+}
@@ -0,0 +1,265 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/siderolabs/go-blockdevice/blockdevice"
+	"github.com/siderolabs/go-blockdevice/blockdevice/util/disk"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/api"
+)
+
+// wipeChunkSize is the size of the range wiped at once, progress is updated after each chunk.
+const wipeChunkSize = 1 << 30
+
+// wipeTracker keeps track of the disk wipe progress.
+type wipeTracker struct {
+	mu    sync.Mutex
+	disks []*api.DiskWipeProgress
+
+	// erasing is the number of the disks being wiped with a method which doesn't report intermediate progress.
+	erasing int
+}
+
+// add registers a disk to be wiped.
+func (t *wipeTracker) add(deviceName string) *api.DiskWipeProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	progress := &api.DiskWipeProgress{
+		DeviceName: deviceName,
+	}
+
+	t.disks = append(t.disks, progress)
+
+	return progress
+}
+
+// update the progress of the disk.
+func (t *wipeTracker) update(progress *api.DiskWipeProgress, f func(*api.DiskWipeProgress)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f(progress)
+}
+
+// erasingAtOnce checks whether any disk is being wiped with a method which doesn't report intermediate progress.
+func (t *wipeTracker) erasingAtOnce() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.erasing > 0
+}
+
+// snapshot returns a copy of the current progress.
+func (t *wipeTracker) snapshot() []*api.DiskWipeProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	disks := make([]*api.DiskWipeProgress, 0, len(t.disks))
+
+	for _, progress := range t.disks {
+		disks = append(disks, &api.DiskWipeProgress{
+			DeviceName: progress.GetDeviceName(),
+			BytesWiped: progress.GetBytesWiped(),
+			TotalBytes: progress.GetTotalBytes(),
+			Method:     progress.GetMethod(),
+			Complete:   progress.GetComplete(),
+		})
+	}
+
+	return disks
+}
+
+// wipeWithProgress wipes the whole block device chunk by chunk updating the progress.
+//
+// The method used to wipe the first chunk is reported as the wipe method.
+func wipeWithProgress(bd *blockdevice.BlockDevice, tracker *wipeTracker, progress *api.DiskWipeProgress) (string, error) {
+	size, err := bd.Size()
+	if err != nil {
+		return "", err
+	}
+
+	tracker.update(progress, func(p *api.DiskWipeProgress) {
+		p.TotalBytes = size
+	})
+
+	var method string
+
+	for offset := uint64(0); offset < size; offset += wipeChunkSize {
+		length := min(wipeChunkSize, size-offset)
+
+		chunkMethod, err := bd.WipeRange(offset, length)
+		if err != nil {
+			return "", err
+		}
+
+		if method == "" {
+			method = chunkMethod
+		}
+
+		tracker.update(progress, func(p *api.DiskWipeProgress) {
+			p.BytesWiped = offset + length
+			p.Method = method
+		})
+	}
+
+	tracker.update(progress, func(p *api.DiskWipeProgress) {
+		p.BytesWiped = size
+		p.Method = method
+		p.Complete = true
+	})
+
+	return method, nil
+}
+
+// wipeAtOnce wipes the block device with a method which doesn't report intermediate progress.
+func wipeAtOnce(bd *blockdevice.BlockDevice, method string, tracker *wipeTracker, progress *api.DiskWipeProgress, wipe func(*blockdevice.BlockDevice) error) (string, error) {
+	size, err := bd.Size()
+	if err != nil {
+		return "", err
+	}
+
+	tracker.update(progress, func(p *api.DiskWipeProgress) {
+		p.TotalBytes = size
+		p.Method = method
+	})
+
+	// the progress doesn't change for the duration of the erase (which might take hours), so heartbeats are sent meanwhile
+	tracker.mu.Lock()
+	tracker.erasing++
+	tracker.mu.Unlock()
+
+	err = wipe(bd)
+
+	tracker.mu.Lock()
+	tracker.erasing--
+	tracker.mu.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+
+	tracker.update(progress, func(p *api.DiskWipeProgress) {
+		p.BytesWiped = size
+		p.Complete = true
+	})
+
+	return method, nil
+}
+
+// wipeDisk wipes the block device using the requested wipe method.
+//
+// The actual method used is returned.
+func wipeDisk(bd *blockdevice.BlockDevice, method string, tracker *wipeTracker, progress *api.DiskWipeProgress) (string, error) {
+	switch method {
+	case wipeMethodFast:
+		return wipeAtOnce(bd, method, tracker, progress, (*blockdevice.BlockDevice).FastWipe)
+	case wipeMethodZero:
+		usedMethod, err := wipeWithProgress(bd, tracker, progress)
+		if err != nil {
+			return "", err
+		}
+
+		return method + ":" + usedMethod, nil
+	case wipeMethodDiscard:
+		return wipeAtOnce(bd, method, tracker, progress, discard)
+	case wipeMethodATASecureErase:
+		return wipeAtOnce(bd, method, tracker, progress, ataSecureErase)
+	case wipeMethodNVMeFormat:
+		return wipeAtOnce(bd, method, tracker, progress, func(bd *blockdevice.BlockDevice) error {
+			return nvmeFormat(bd, nvmeSESUserDataErase)
+		})
+	case wipeMethodCryptoErase:
+		return wipeAtOnce(bd, method, tracker, progress, func(bd *blockdevice.BlockDevice) error {
+			return nvmeFormat(bd, nvmeSESCryptoErase)
+		})
+	default:
+		return "", fmt.Errorf("unsupported wipe method %q", method)
+	}
+}
+
+// mapWipePolicy returns the wipe policy to apply falling back to the controller defaults.
+func mapWipePolicy(resp *api.CreateServerResponse) *api.WipePolicy {
+	policy := &api.WipePolicy{}
+
+	if resp.GetWipePolicy() != nil {
+		policy = &api.WipePolicy{
+			Method:             resp.GetWipePolicy().GetMethod(),
+			StorageTypeMethods: resp.GetWipePolicy().GetStorageTypeMethods(),
+			Include:            resp.GetWipePolicy().GetInclude(),
+			Exclude:            resp.GetWipePolicy().GetExclude(),
+			SkipBootMedia:      resp.GetWipePolicy().GetSkipBootMedia(),
+		}
+	}
+
+	if policy.Method == "" {
+		policy.Method = wipeMethodZero
+
+		if resp.GetInsecureWipe() {
+			policy.Method = wipeMethodFast
+		}
+	}
+
+	return policy
+}
+
+// matchDiskSelector checks whether all set fields of the selector match the disk.
+func matchDiskSelector(selector *api.DiskSelector, d *disk.Disk) bool {
+	return (&metalv1.DiskSelector{
+		WWID:   selector.GetWwid(),
+		Serial: selector.GetSerial(),
+		Model:  selector.GetModel(),
+	}).Match(d.WWID, d.Serial, d.Model)
+}
+
+// isBootMedia checks whether the disk looks like a removable boot media (USB stick, SD card, virtual media).
+func isBootMedia(d *disk.Disk) bool {
+	if d.Type == disk.TypeSD || strings.Contains(d.BusPath, "/usb") {
+		return true
+	}
+
+	removable, err := os.ReadFile(filepath.Join("/sys/block", filepath.Base(d.DeviceName), "removable"))
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(string(removable)) == "1"
+}
+
+// diskWipeMethod returns the wipe method for the disk according to the policy.
+//
+// If the disk should not be wiped, empty method and the reason are returned.
+func diskWipeMethod(policy *api.WipePolicy, d *disk.Disk) (method, skipReason string) {
+	if len(policy.GetInclude()) > 0 && !slices.ContainsFunc(policy.GetInclude(), func(selector *api.DiskSelector) bool {
+		return matchDiskSelector(selector, d)
+	}) {
+		return "", "not included by the wipe policy"
+	}
+
+	if slices.ContainsFunc(policy.GetExclude(), func(selector *api.DiskSelector) bool {
+		return matchDiskSelector(selector, d)
+	}) {
+		return "", "excluded by the wipe policy"
+	}
+
+	if policy.GetSkipBootMedia() && isBootMedia(d) {
+		return "", "boot media is skipped by the wipe policy"
+	}
+
+	if storageTypeMethod, ok := policy.GetStorageTypeMethods()[MapStorageType(d).String()]; ok {
+		return storageTypeMethod, ""
+	}
+
+	return policy.GetMethod(), ""
+}
@@ -0,0 +1,103 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/dhcp"
+)
+
+// DHCPPoolReconciler reconciles a DHCPPool object.
+//
+// Leases are created by the authoritative DHCP server, the reconciler expires them and keeps the static leases of the Servers.
+type DHCPPoolReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=metal.sidero.dev,resources=dhcppools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=metal.sidero.dev,resources=dhcppools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=metal.sidero.dev,resources=servers,verbs=get;list;watch
+
+func (r *DHCPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := r.Log.WithValues("dhcppool", req.Name)
+
+	var pool metalv1.DHCPPool
+
+	if err := r.Get(ctx, req.NamespacedName, &pool); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var servers metalv1.ServerList
+
+	if err := r.List(ctx, &servers); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to list servers: %w", err)
+	}
+
+	now := time.Now()
+
+	if pool.ReconcileLeases(dhcp.ServerMACs(servers.Items), now) {
+		l.Info("updating leases", "leases", len(pool.Status.Leases))
+
+		if err := r.Status().Update(ctx, &pool); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if next := pool.NextExpiration(); !next.IsZero() {
+		return ctrl.Result{RequeueAfter: next.Sub(now)}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *DHCPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	// Server hardware information changes the static leases, so reconcile all pools on server updates.
+	mapRequests := func(_ context.Context, a client.Object) []reconcile.Request {
+		reqList := []reconcile.Request{}
+
+		poolList := &metalv1.DHCPPoolList{}
+
+		if err := r.List(ctx, poolList); err != nil {
+			return reqList
+		}
+
+		for _, pool := range poolList.Items {
+			reqList = append(
+				reqList,
+				reconcile.Request{
+					NamespacedName: types.NamespacedName{
+						Name: pool.Name,
+					},
+				},
+			)
+		}
+
+		return reqList
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(options).
+		For(&metalv1.DHCPPool{}).
+		Watches(
+			&metalv1.Server{},
+			handler.EnqueueRequestsFromMapFunc(mapRequests),
+		).
+		Complete(r)
+}
@@ -6,25 +6,32 @@ package controllers
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/siderolabs/go-pointer"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 
 	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/assets"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/secureboot"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/constants"
 )
 
@@ -36,10 +43,36 @@ type EnvironmentReconciler struct {
 	TalosRelease string
 	APIEndpoint  string
 	APIPort      uint16
+	// TrustBundle is used to verify the signatures of the UKI and shim assets, verification is disabled if not set.
+	TrustBundle *x509.CertPool
+	// CheckSignatureExpiry rejects the UKI and shim assets signed with the expired certificates.
+	CheckSignatureExpiry bool
+	// Store keeps the assets shared by the Environments.
+	Store *assets.Store
+	// GCInterval is the interval of the asset garbage collection, it is disabled if zero.
+	GCInterval time.Duration
+	// AssetNamespaces are the namespaces the Secret and ConfigMap assets are allowed to be read from.
+	AssetNamespaces []string
+	// AssetFileRoot is the directory the file assets are confined to, the file assets are disabled if not set.
+	AssetFileRoot string
+	// Replica is the name of the controller manager pod, every replica syncs the assets it serves and reports its status.
+	Replica string
+	// ReplicaNamespace is the namespace of the controller manager pods, the status of the removed replicas is pruned if set.
+	ReplicaNamespace string
+	// APIReader is used to check whether the replicas exist without caching the pods.
+	APIReader client.Reader
+	// Elected is closed when the replica becomes the leader, only the leader updates the asset conditions.
+	// The replica is considered the leader if not set.
+	Elected <-chan struct{}
 }
 
+// environmentsDirectory keeps the assets of each Environment in a subdirectory named after the Environment.
+const environmentsDirectory = "/var/lib/sidero/env"
+
 // +kubebuilder:rbac:groups=metal.sidero.dev,resources=environments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=metal.sidero.dev,resources=environments/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets;configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get
 
 func (r *EnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	l := r.Log.WithValues("environment", req.Name)
@@ -59,11 +92,23 @@ func (r *EnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	var env metalv1.Environment
 
 	if err := r.Get(ctx, req.NamespacedName, &env); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.removeEnvironment(l, req.Name)
+		}
+
 		l.Error(err, "failed fetching resource")
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+
+		return ctrl.Result{}, err
+	}
+
+	// the update triggers the reconcile of the Environment with the generated assets
+	if env.Spec.ApplyImageFactory() {
+		l.Info("updating assets from Image Factory schematic", "schematic", env.Spec.ImageFactory.SchematicID, "version", env.Spec.ImageFactory.TalosVersion)
+
+		return ctrl.Result{}, r.Update(ctx, &env)
 	}
 
-	envs := filepath.Join("/var/lib/sidero/env", env.GetName())
+	envs := filepath.Join(environmentsDirectory, env.GetName())
 
 	if _, err := os.Stat(envs); os.IsNotExist(err) {
 		if err = os.MkdirAll(envs, 0o777); err != nil {
@@ -78,10 +123,7 @@ func (r *EnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		result     *multierror.Error
 	)
 
-	for _, assetTask := range []struct {
-		BaseName string
-		Asset    metalv1.Asset
-	}{
+	assetTasks := []assetTask{
 		{
 			BaseName: constants.KernelAsset,
 			Asset:    env.Spec.Kernel.Asset,
@@ -90,94 +132,394 @@ func (r *EnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			BaseName: constants.InitrdAsset,
 			Asset:    env.Spec.Initrd.Asset,
 		},
-	} {
-		file := filepath.Join(envs, assetTask.BaseName)
+	}
 
-		setReady := func(ready bool) {
-			status := "False"
-			if ready {
-				status = "True"
+	if env.Spec.UKI != nil {
+		assetTasks = append(assetTasks, assetTask{
+			BaseName: constants.UKIAsset,
+			Asset:    env.Spec.UKI.Asset,
+		})
+
+		for _, task := range []struct {
+			BaseName string
+			Asset    *metalv1.Asset
+		}{
+			{BaseName: constants.UKISignatureAsset, Asset: env.Spec.UKI.Signature},
+			{BaseName: constants.UKICertificateAsset, Asset: env.Spec.UKI.Certificate},
+			{BaseName: constants.ShimAsset, Asset: env.Spec.UKI.Shim},
+		} {
+			if task.Asset != nil {
+				assetTasks = append(assetTasks, assetTask{
+					BaseName: task.BaseName,
+					Asset:    *task.Asset,
+				})
 			}
+		}
+	}
 
-			condition := metalv1.AssetCondition{
-				Asset:  assetTask.Asset,
-				Status: status,
-				Type:   "Ready",
-			}
+	// the errors are reported per asset, so that they are visible in the conditions
+	src, srcErr := r.assetSources(ctx, &env)
 
-			mu.Lock()
+	for _, assetTask := range assetTasks {
+		if assetTask.Asset.URL == "" {
+			continue
+		}
+
+		file := filepath.Join(envs, assetTask.BaseName)
 
-			conditions = append(conditions, condition)
+		// the assets are local to the replica, so the shared status can't tell whether they are up to date
+		if r.Store.Installed(assetTask.Asset, file) {
+			l.Info("update not required", "file", file)
 
+			mu.Lock()
+			conditions = append(conditions, assetConditions(assetTask.Asset, nil)...)
 			mu.Unlock()
+
+			continue
 		}
 
-		saveAsset := func(file string) {
-			wg.Add(1)
+		// At this point the file doesn't exist, or the URL or the checksum for the file has changed,
+		// or the previous download failed.
+
+		l.Info("saving asset", "url", assetTask.Asset.URL)
+
+		wg.Add(1)
 
-			go func() {
-				defer wg.Done()
+		go func() {
+			defer wg.Done()
 
-				if err := save(ctx, assetTask.Asset, file); err != nil {
-					setReady(false)
+			err := srcErr
+			if err == nil {
+				err = r.Store.Install(ctx, src, assetTask.Asset, file)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			conditions = append(conditions, assetConditions(assetTask.Asset, err)...)
+
+			if err != nil {
+				result = multierror.Append(result, fmt.Errorf("error saving %q: %w", assetTask.Asset.URL, err))
+
+				return
+			}
+
+			l.Info("saved asset", "url", assetTask.Asset.URL)
+		}()
+	}
+
+	wg.Wait()
+
+	if err := removeStaleAssets(envs, assetTasks); err != nil {
+		result = multierror.Append(result, err)
+	}
+
+	if r.TrustBundle != nil && env.Spec.UKI != nil {
+		// signatures are verified on every reconcile, as assets which are already downloaded are not re-checked above
+		for url, err := range verifySecureBootAssets(env.Spec.UKI, envs, secureboot.VerifyOptions{Roots: r.TrustBundle, CheckExpiry: r.CheckSignatureExpiry}) {
+			l.Error(err, "signature verification failed", "url", url)
 
-					result = multierror.Append(result, fmt.Errorf("error saving %q: %w", assetTask.Asset.URL, err))
+			for i := range conditions {
+				if conditions[i].URL == url && (conditions[i].Type == metalv1.AssetReady || conditions[i].Type == metalv1.AssetVerified) {
+					conditions[i].Status = "False"
+					conditions[i].Reason = metalv1.AssetReasonSignatureInvalid
+					conditions[i].Message = err.Error()
 				}
+			}
+		}
+	}
 
-				setReady(true)
-				l.Info("saved asset", "url", assetTask.Asset.URL)
-			}()
+	// keep the order stable, as the assets are downloaded concurrently
+	sort.SliceStable(conditions, func(i, j int) bool {
+		if conditions[i].URL != conditions[j].URL {
+			return conditions[i].URL < conditions[j].URL
 		}
 
-		if _, err := os.Stat(file); os.IsNotExist(err) {
-			l.Info("saving asset", "url", assetTask.Asset.URL)
-			saveAsset(file)
+		return conditions[i].Type < conditions[j].Type
+	})
 
-			continue
+	ready := (&metalv1.Environment{
+		Spec:   env.Spec,
+		Status: metalv1.EnvironmentStatus{Conditions: conditions},
+	}).IsReady()
+
+	if err := r.updateStatus(ctx, &env, conditions, ready); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, result.ErrorOrNil()
+}
+
+// updateStatus updates the asset conditions and the status of the replica.
+//
+// Every replica updates its own status, so the update is retried on conflicts,
+// while the asset conditions are updated by the leader only, so that the replicas don't overwrite each other.
+func (r *EnvironmentReconciler) updateStatus(ctx context.Context, env *metalv1.Environment, conditions []metalv1.AssetCondition, ready bool) error {
+	replica := metalv1.ReplicaStatus{
+		Name:               r.Replica,
+		Ready:              ready,
+		ObservedGeneration: env.Generation,
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var latest metalv1.Environment
+
+		if err := r.Get(ctx, client.ObjectKeyFromObject(env), &latest); err != nil {
+			return err
+		}
+
+		status := latest.Status.DeepCopy()
+
+		if r.isLeader() {
+			latest.Status.Conditions = conditions
+		}
+
+		if r.Replica != "" {
+			replicas, err := r.replicaStatuses(ctx, latest.Status.Replicas, replica)
+			if err != nil {
+				return err
+			}
+
+			latest.Status.Replicas = replicas
+		}
+
+		if equality.Semantic.DeepEqual(status, &latest.Status) {
+			return nil
 		}
 
-		// If we reach here, the file derived from the URL exists, and now we need
-		// to update it if the URL has changed.
+		return r.Status().Update(ctx, &latest)
+	})
+}
+
+// isLeader checks whether the replica is the leader.
+func (r *EnvironmentReconciler) isLeader() bool {
+	if r.Elected == nil {
+		return true
+	}
+
+	select {
+	case <-r.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// replicaStatuses updates the status of the replica, and drops the status of the replicas which no longer exist.
+func (r *EnvironmentReconciler) replicaStatuses(ctx context.Context, statuses []metalv1.ReplicaStatus, replica metalv1.ReplicaStatus) ([]metalv1.ReplicaStatus, error) {
+	result := []metalv1.ReplicaStatus{replica}
+
+	for _, status := range statuses {
+		if status.Name == replica.Name {
+			continue
+		}
 
-		l.Info("checking if update required", "file", file)
+		if r.ReplicaNamespace != "" && r.APIReader != nil {
+			var pod corev1.Pod
 
-		ready := false
+			err := r.APIReader.Get(ctx, types.NamespacedName{Namespace: r.ReplicaNamespace, Name: status.Name}, &pod)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
 
-		for _, condition := range env.Status.Conditions {
-			if assetTask.Asset.URL == condition.URL {
-				ready = true
+			if err != nil {
+				return nil, err
 			}
 		}
 
-		if ready {
-			l.Info("update not required", "file", file)
-			setReady(true)
+		result = append(result, status)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, nil
+}
+
+// assetSources returns the asset sources with the Environment registry credentials and CA bundle.
+func (r *EnvironmentReconciler) assetSources(ctx context.Context, env *metalv1.Environment) (*assets.Sources, error) {
+	src := &assets.Sources{
+		Reader:     r.Client,
+		Namespaces: r.AssetNamespaces,
+		FileRoot:   r.AssetFileRoot,
+	}
+
+	if env.Spec.Sources == nil {
+		return src, nil
+	}
+
+	if ref := env.Spec.Sources.CABundle; ref != nil {
+		bundle, err := (&metalv1.CredentialSource{SecretKeyRef: ref}).Resolve(ctx, r.Client)
+		if err != nil {
+			return nil, fmt.Errorf("error getting CA bundle: %w", err)
+		}
+
+		if src.RootCAs, err = x509.SystemCertPool(); err != nil {
+			src.RootCAs = x509.NewCertPool()
+		}
+
+		if !src.RootCAs.AppendCertsFromPEM([]byte(bundle)) {
+			return nil, errors.New("no certificates found in CA bundle")
+		}
+	}
+
+	if ref := env.Spec.Sources.RegistryCredentials; ref != nil {
+		credentials, err := (&metalv1.CredentialSource{SecretKeyRef: ref}).Resolve(ctx, r.Client)
+		if err != nil {
+			return nil, fmt.Errorf("error getting registry credentials: %w", err)
+		}
+
+		if src.Keychain, err = assets.ParseDockerConfig([]byte(credentials)); err != nil {
+			return nil, err
+		}
+	}
+
+	return src, nil
+}
+
+// replicaRunnable runs on every replica regardless of the leader election.
+type replicaRunnable func(ctx context.Context) error
+
+// Start implements manager.Runnable.
+func (f replicaRunnable) Start(ctx context.Context) error {
+	return f(ctx)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable.
+func (f replicaRunnable) NeedLeaderElection() bool {
+	return false
+}
+
+type assetTask struct {
+	BaseName string
+	Asset    metalv1.Asset
+}
+
+// removeStaleAssets removes the files of the assets which are no longer referenced by the Environment,
+// so that the blobs they are linked to can be garbage collected.
+func removeStaleAssets(dir string, tasks []assetTask) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
 
+	expected := map[string]struct{}{}
+
+	for _, task := range tasks {
+		if task.Asset.URL != "" {
+			expected[task.BaseName] = struct{}{}
+		}
+	}
+
+	for _, entry := range entries {
+		if _, ok := expected[entry.Name()]; ok || entry.IsDir() {
 			continue
 		}
 
-		l.Info("update required", "file", file)
+		if err = os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
 
-		// At this point the file exists, but the URL for the file has changed. We
-		// need to update the file using the new URL.
+	return nil
+}
 
-		l.Info("updating asset", "url", assetTask.Asset.URL)
-		saveAsset(file)
+// removeEnvironment removes the assets of the deleted Environment and collects the blobs which are no longer referenced.
+func (r *EnvironmentReconciler) removeEnvironment(l logr.Logger, name string) error {
+	if err := os.RemoveAll(filepath.Join(environmentsDirectory, name)); err != nil {
+		return fmt.Errorf("error removing environment directory: %w", err)
 	}
 
-	wg.Wait()
+	collected, err := r.Store.GC()
+	if err != nil {
+		return fmt.Errorf("error collecting assets: %w", err)
+	}
 
-	if result.ErrorOrNil() != nil {
-		return ctrl.Result{}, result.ErrorOrNil()
+	l.Info("removed environment assets", "collected", collected)
+
+	return nil
+}
+
+// collectGarbage removes the directories of the Environments which no longer exist, and the unreferenced blobs.
+//
+// The directories might be left behind if the Environment was deleted while the controller was not running.
+func (r *EnvironmentReconciler) collectGarbage(ctx context.Context) error {
+	var envList metalv1.EnvironmentList
+
+	if err := r.List(ctx, &envList); err != nil {
+		return err
 	}
 
-	env.Status.Conditions = conditions
+	existing := map[string]struct{}{}
 
-	if err := r.Status().Update(ctx, &env); err != nil {
-		return ctrl.Result{}, err
+	for _, env := range envList.Items {
+		existing[env.Name] = struct{}{}
+	}
+
+	entries, err := os.ReadDir(environmentsDirectory)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for _, entry := range entries {
+		if _, ok := existing[entry.Name()]; ok || !entry.IsDir() || strings.HasPrefix(entry.Name(), "agent-") {
+			continue
+		}
+
+		if err = os.RemoveAll(filepath.Join(environmentsDirectory, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	collected, err := r.Store.GC()
+	if err != nil {
+		return err
+	}
+
+	if collected > 0 {
+		r.Log.Info("collected unreferenced assets", "count", collected)
+	}
+
+	return nil
+}
+
+// assetConditions returns the conditions of the asset for the download result.
+func assetConditions(asset metalv1.Asset, err error) []metalv1.AssetCondition {
+	condition := func(conditionType, status, reason, message string) metalv1.AssetCondition {
+		return metalv1.AssetCondition{
+			Asset:   asset,
+			Type:    conditionType,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		}
 	}
 
-	return ctrl.Result{}, nil
+	var checksumErr *assets.ChecksumMismatchError
+
+	switch {
+	case err == nil:
+		verified := condition(metalv1.AssetVerified, "True", metalv1.AssetReasonChecksumMatched, "")
+		if asset.SHA512 == "" {
+			verified = condition(metalv1.AssetVerified, "Unknown", metalv1.AssetReasonNoChecksum, "SHA512 checksum is not set")
+		}
+
+		return []metalv1.AssetCondition{
+			condition(metalv1.AssetReady, "True", metalv1.AssetReasonDownloaded, ""),
+			verified,
+			condition(metalv1.AssetDownloadFailed, "False", metalv1.AssetReasonDownloaded, ""),
+		}
+	case errors.As(err, &checksumErr):
+		return []metalv1.AssetCondition{
+			condition(metalv1.AssetReady, "False", metalv1.AssetReasonChecksumMismatch, err.Error()),
+			condition(metalv1.AssetVerified, "False", metalv1.AssetReasonChecksumMismatch, err.Error()),
+			condition(metalv1.AssetDownloadFailed, "True", metalv1.AssetReasonChecksumMismatch, err.Error()),
+		}
+	default:
+		return []metalv1.AssetCondition{
+			condition(metalv1.AssetReady, "False", metalv1.AssetReasonDownloadError, err.Error()),
+			condition(metalv1.AssetDownloadFailed, "True", metalv1.AssetReasonDownloadError, err.Error()),
+		}
+	}
 }
 
 // ReconcileEnvironmentDefault ensures that Environment "default" exist.
@@ -194,6 +536,11 @@ func ReconcileEnvironmentDefault(ctx context.Context, c client.Client, talosRele
 		env.Spec = *metalv1.EnvironmentDefaultSpec(talosRelease, apiEndpoint, apiPort)
 
 		err = c.Create(ctx, &env)
+
+		// another replica might have created it
+		if apierrors.IsAlreadyExists(err) {
+			err = nil
+		}
 	}
 
 	return err
@@ -204,52 +551,90 @@ func (r *EnvironmentReconciler) SetupWithManager(ctx context.Context, mgr ctrl.M
 		return errors.New("TalosRelease is not set")
 	}
 
+	if r.Store == nil {
+		return errors.New("Store is not set")
+	}
+
+	// the assets are local to the replica, so the garbage is collected on every replica
+	if r.GCInterval > 0 {
+		if err := mgr.Add(replicaRunnable(func(ctx context.Context) error {
+			ticker := time.NewTicker(r.GCInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+
+				if err := r.collectGarbage(ctx); err != nil {
+					r.Log.Error(err, "asset garbage collection failed")
+				}
+			}
+		})); err != nil {
+			return err
+		}
+	}
+
+	// every replica syncs the assets it serves
+	options.NeedLeaderElection = pointer.To(false)
+
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(options).
 		For(&metalv1.Environment{}).
 		Complete(r)
 }
 
-func save(ctx context.Context, asset metalv1.Asset, file string) error {
-	url := asset.URL
-
-	if url == "" {
-		return errors.New("missing URL")
-	}
-
-	requestContext, cancel := context.WithTimeout(ctx, 5*time.Minute)
-	defer cancel()
+// verifySecureBootAssets verifies the signatures of the UKI and the shim against the trust bundle.
+//
+// The verification errors are returned by the asset URL.
+func verifySecureBootAssets(uki *metalv1.UKI, dir string, opts secureboot.VerifyOptions) map[string]error {
+	failed := map[string]error{}
 
-	req, err := http.NewRequestWithContext(requestContext, http.MethodGet, url, nil)
-	if err != nil {
-		return err
+	if err := verifyUKI(uki, dir, opts); err != nil {
+		failed[uki.URL] = err
 	}
 
-	client := &http.Client{}
+	if uki.Shim != nil {
+		image, err := os.ReadFile(filepath.Join(dir, constants.ShimAsset))
+		if err == nil {
+			err = secureboot.VerifyPE(image, opts)
+		}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+		if err != nil {
+			failed[uki.Shim.URL] = err
+		}
 	}
 
-	defer resp.Body.Close()
+	return failed
+}
 
-	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
-		w, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY, 0o666)
+func verifyUKI(uki *metalv1.UKI, dir string, opts secureboot.VerifyOptions) error {
+	if uki.Certificate != nil {
+		data, err := os.ReadFile(filepath.Join(dir, constants.UKICertificateAsset))
 		if err != nil {
 			return err
 		}
 
-		defer w.Close()
+		if opts.Intermediates, err = secureboot.ParseCertificates(data); err != nil {
+			return fmt.Errorf("error parsing certificate: %w", err)
+		}
+	}
 
-		r := resp.Body
+	image, err := os.ReadFile(filepath.Join(dir, constants.UKIAsset))
+	if err != nil {
+		return err
+	}
 
-		if _, err := io.Copy(w, r); err != nil {
-			return err
-		}
-	} else {
-		return fmt.Errorf("failed to download asset: %d", resp.StatusCode)
+	if uki.Signature == nil {
+		return secureboot.VerifyPE(image, opts)
 	}
 
-	return nil
+	signature, err := os.ReadFile(filepath.Join(dir, constants.UKISignatureAsset))
+	if err != nil {
+		return err
+	}
+
+	return secureboot.VerifyDetached(image, signature, opts)
 }
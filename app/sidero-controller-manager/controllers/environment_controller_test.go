@@ -0,0 +1,131 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controllers
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/assets"
+)
+
+func TestAssetConditions(t *testing.T) {
+	asset := metalv1.Asset{URL: "http://example.com/vmlinuz", SHA512: "abcd"}
+
+	conditions := assetConditions(asset, nil)
+	require.Len(t, conditions, 3)
+	assert.Equal(t, metalv1.AssetReady, conditions[0].Type)
+	assert.Equal(t, "True", conditions[0].Status)
+	assert.Equal(t, metalv1.AssetReasonChecksumMatched, conditions[1].Reason)
+	assert.Equal(t, "False", conditions[2].Status)
+
+	conditions = assetConditions(metalv1.Asset{URL: asset.URL}, nil)
+	assert.Equal(t, "Unknown", conditions[1].Status)
+	assert.Equal(t, metalv1.AssetReasonNoChecksum, conditions[1].Reason)
+
+	conditions = assetConditions(asset, &assets.ChecksumMismatchError{Expected: "abcd", Actual: "ef01"})
+	require.Len(t, conditions, 3)
+	assert.Equal(t, "False", conditions[0].Status)
+	assert.Equal(t, metalv1.AssetReasonChecksumMismatch, conditions[1].Reason)
+	assert.Equal(t, "True", conditions[2].Status)
+	assert.Contains(t, conditions[2].Message, "checksum mismatch")
+
+	conditions = assetConditions(asset, os.ErrNotExist)
+	require.Len(t, conditions, 2)
+	assert.Equal(t, metalv1.AssetDownloadFailed, conditions[1].Type)
+	assert.Equal(t, metalv1.AssetReasonDownloadError, conditions[1].Reason)
+}
+
+func TestReplicaStatuses(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	r := &EnvironmentReconciler{
+		ReplicaNamespace: "sidero-system",
+		APIReader: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "sidero-system", Name: "sidero-1"}}).
+			Build(),
+	}
+
+	statuses, err := r.replicaStatuses(context.Background(),
+		[]metalv1.ReplicaStatus{
+			{Name: "sidero-2", Ready: true},
+			{Name: "sidero-1", Ready: true, ObservedGeneration: 1},
+			{Name: "sidero-0", ObservedGeneration: 1},
+		},
+		metalv1.ReplicaStatus{Name: "sidero-0", Ready: true, ObservedGeneration: 2},
+	)
+	require.NoError(t, err)
+
+	// the removed replica is dropped
+	assert.Equal(t, []metalv1.ReplicaStatus{
+		{Name: "sidero-0", Ready: true, ObservedGeneration: 2},
+		{Name: "sidero-1", Ready: true, ObservedGeneration: 1},
+	}, statuses)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, metalv1.AddToScheme(scheme))
+
+	leaderConditions := assetConditions(metalv1.Asset{URL: "http://example.com/vmlinuz"}, nil)
+
+	env := &metalv1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Generation: 2},
+		Status: metalv1.EnvironmentStatus{
+			Conditions: leaderConditions,
+		},
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(env).
+		WithStatusSubresource(env).
+		Build()
+
+	ctx := context.Background()
+
+	// the replica which is not the leader reports its own status only
+	r := &EnvironmentReconciler{
+		Client:  c,
+		Replica: "sidero-1",
+		Elected: make(chan struct{}),
+	}
+
+	require.NoError(t, r.updateStatus(ctx, env, assetConditions(metalv1.Asset{URL: "http://example.com/vmlinuz"}, os.ErrNotExist), false))
+
+	var latest metalv1.Environment
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(env), &latest))
+	assert.Equal(t, leaderConditions, latest.Status.Conditions)
+	assert.Equal(t, []metalv1.ReplicaStatus{{Name: "sidero-1", ObservedGeneration: 2}}, latest.Status.Replicas)
+
+	// the leader updates the conditions
+	elected := make(chan struct{})
+	close(elected)
+
+	r.Replica = "sidero-0"
+	r.Elected = elected
+
+	require.NoError(t, r.updateStatus(ctx, env, nil, true))
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(env), &latest))
+	assert.Empty(t, latest.Status.Conditions)
+	assert.Equal(t, []metalv1.ReplicaStatus{
+		{Name: "sidero-0", Ready: true, ObservedGeneration: 2},
+		{Name: "sidero-1", ObservedGeneration: 2},
+	}, latest.Status.Replicas)
+}
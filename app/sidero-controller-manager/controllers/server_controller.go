@@ -30,6 +30,7 @@ import (
 
 	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
 	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/configtoken"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/power"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/constants"
 	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
@@ -54,11 +55,14 @@ type ServerReconciler struct {
 // +kubebuilder:rbac:groups=metal.sidero.dev,resources=servers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=metal.sidero.dev,resources=servers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=serverbindings,verbs=get;list;watch
-// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=serverbindings/status,verbs=get
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=serverbindings/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=metalmachines,verbs=get;list;watch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=metalmachines/status,verbs=get
+// +kubebuilder:rbac:groups=metal.sidero.dev,resources=machineconfigpatches,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 //nolint:maintidx
 func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -80,6 +84,10 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	if _, ok := s.Annotations[metalv1.HardwareChangeAcknowledgedAnnotation]; ok {
+		r.acknowledgeHardwareChange(&s, serverRef)
+	}
+
 	mgmtClient, err := power.NewManagementClient(ctx, r.Client, &s.Spec)
 	if err != nil {
 		log.Error(err, "failed to create management client")
@@ -110,7 +118,7 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		s.Status.Ready = ready
 
 		if err := patchHelper.Patch(ctx, &s, patch.WithOwnedConditions{
-			Conditions: []clusterv1.ConditionType{metalv1.ConditionPowerCycle, metalv1.ConditionPXEBooted},
+			Conditions: []clusterv1.ConditionType{metalv1.ConditionPowerCycle, metalv1.ConditionPXEBooted, metalv1.ConditionHardwareChanged},
 		}); err != nil {
 			return result, errors.WithStack(err)
 		}
@@ -133,6 +141,11 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 		conditions.Delete(&s, metalv1.ConditionPXEBooted)
 	} else {
+		if !s.Status.InUse {
+			// transitioning to true
+			s.Status.LastAllocated = &v1.Time{Time: time.Now()}
+		}
+
 		s.Status.InUse = true
 		s.Status.IsClean = false
 
@@ -206,6 +219,15 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}
 
 		if !poweredOn {
+			// the server is booted by Sidero, so it gets the new config token on this boot
+			if serverBinding != nil {
+				if err = configtoken.Reset(ctx, r.Client, serverBinding); err != nil {
+					log.Error(err, "failed to reset config token")
+
+					return f(false, ctrl.Result{RequeueAfter: constants.DefaultRequeueAfter})
+				}
+			}
+
 			// it's safe to set server to PXE boot even if it's already installed, as PXE server makes sure server is PXE booted only once
 			err = mgmtClient.SetPXE(pxeMode)
 			if err != nil {
@@ -250,6 +272,11 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 			return f(false, ctrl.Result{RequeueAfter: constants.DefaultRequeueAfter})
 		}
 
+		if progress := s.Status.WipeProgress; progress != nil && progress.Percentage < 100 && conditions.IsFalse(&s, metalv1.ConditionPowerCycle) {
+			// the agent reported wipe progress, but it didn't progress within the reboot timeout
+			r.Recorder.Event(serverRef, corev1.EventTypeWarning, "Server Wipe", fmt.Sprintf("Server wipe is stuck at %d%% since %s, retrying.", progress.Percentage, progress.LastProgressTime.Format(time.RFC3339)))
+		}
+
 		err = mgmtClient.SetPXE(pxeMode)
 		if err != nil {
 			log.Error(err, "failed to set PXE")
@@ -294,6 +321,25 @@ func (r *ServerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return f(false, ctrl.Result{})
 }
 
+// acknowledgeHardwareChange clears the HardwareChanged condition on the operator request.
+//
+// If the server was cordoned because of the hardware change, it gets uncordoned.
+func (r *ServerReconciler) acknowledgeHardwareChange(s *metalv1.Server, serverRef *corev1.ObjectReference) {
+	delete(s.Annotations, metalv1.HardwareChangeAcknowledgedAnnotation)
+
+	if !conditions.IsTrue(s, metalv1.ConditionHardwareChanged) {
+		return
+	}
+
+	if conditions.GetReason(s, metalv1.ConditionHardwareChanged) == metalv1.HardwareChangedAutoCordonedReason {
+		s.Spec.Cordoned = false
+	}
+
+	conditions.MarkFalse(s, metalv1.ConditionHardwareChanged, metalv1.HardwareChangeAcknowledgedReason, clusterv1.ConditionSeverityInfo, "Hardware change acknowledged.")
+
+	r.Recorder.Event(serverRef, corev1.EventTypeNormal, "Server Hardware Changed", "Hardware change acknowledged.")
+}
+
 func (r *ServerReconciler) getServerBinding(ctx context.Context, req ctrl.Request) (bool, *infrav1.ServerBinding, error) {
 	var (
 		serverBinding infrav1.ServerBinding
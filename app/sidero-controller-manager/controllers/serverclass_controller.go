@@ -7,6 +7,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -72,6 +73,7 @@ func (r *ServerClassReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		metalv1.NotCordonedServerFilter,
 		sc.SelectorFilter(),
 		sc.QualifiersFilter(),
+		sc.HardwareValidatedFilter(),
 	)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("unable to filter servers: %w", err)
@@ -80,23 +82,47 @@ func (r *ServerClassReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	avail := []string{}
 	used := []string{}
 
+	var (
+		reserved   []metalv1.ServerReservationStatus
+		nextExpiry time.Duration
+		now        = time.Now()
+	)
+
 	for _, server := range results {
 		if server.Status.InUse {
 			used = append(used, server.Name)
 			continue
 		}
 
+		if reservation := server.Spec.ReservedFor; reservation.Active(now) {
+			reserved = append(reserved, metalv1.ServerReservationStatus{
+				Server:      server.Name,
+				ReservedFor: reservation.String(),
+				ExpiresAt:   reservation.ExpiresAt,
+			})
+
+			// re-reconcile when the reservation expires to make the server available
+			if reservation.ExpiresAt != nil {
+				if expiry := reservation.ExpiresAt.Sub(now); nextExpiry == 0 || expiry < nextExpiry {
+					nextExpiry = expiry
+				}
+			}
+
+			continue
+		}
+
 		avail = append(avail, server.Name)
 	}
 
 	sc.Status.ServersAvailable = avail
 	sc.Status.ServersInUse = used
+	sc.Status.ServersReserved = reserved
 
 	if err := patchHelper.Patch(ctx, &sc); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: nextExpiry}, nil
 }
 
 // ReconcileServerClassAny ensures that ServerClass "any" exist and is in desired state.
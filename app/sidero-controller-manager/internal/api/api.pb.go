@@ -543,6 +543,9 @@ type StorageDevice struct {
 	DeviceName    string                 `protobuf:"bytes,6,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
 	Uuid          string                 `protobuf:"bytes,7,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	Wwid          string                 `protobuf:"bytes,8,opt,name=wwid,proto3" json:"wwid,omitempty"`
+	Rotational    bool                   `protobuf:"varint,9,opt,name=rotational,proto3" json:"rotational,omitempty"`
+	Transport     string                 `protobuf:"bytes,10,opt,name=transport,proto3" json:"transport,omitempty"`
+	SmartHealth   string                 `protobuf:"bytes,11,opt,name=smart_health,json=smartHealth,proto3" json:"smart_health,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -633,6 +636,27 @@ func (x *StorageDevice) GetWwid() string {
 	return ""
 }
 
+func (x *StorageDevice) GetRotational() bool {
+	if x != nil {
+		return x.Rotational
+	}
+	return false
+}
+
+func (x *StorageDevice) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *StorageDevice) GetSmartHealth() string {
+	if x != nil {
+		return x.SmartHealth
+	}
+	return ""
+}
+
 type StorageInformation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TotalSize     uint64                 `protobuf:"varint,1,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
@@ -694,15 +718,18 @@ func (x *StorageInformation) GetDevices() []*StorageDevice {
 }
 
 type NetworkInterface struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Index         uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Flags         string                 `protobuf:"bytes,3,opt,name=flags,proto3" json:"flags,omitempty"`
-	Mtu           uint32                 `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`
-	Mac           string                 `protobuf:"bytes,5,opt,name=mac,proto3" json:"mac,omitempty"`
-	Addresses     []string               `protobuf:"bytes,6,rep,name=addresses,proto3" json:"addresses,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Index           uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Flags           string                 `protobuf:"bytes,3,opt,name=flags,proto3" json:"flags,omitempty"`
+	Mtu             uint32                 `protobuf:"varint,4,opt,name=mtu,proto3" json:"mtu,omitempty"`
+	Mac             string                 `protobuf:"bytes,5,opt,name=mac,proto3" json:"mac,omitempty"`
+	Addresses       []string               `protobuf:"bytes,6,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Driver          string                 `protobuf:"bytes,7,opt,name=driver,proto3" json:"driver,omitempty"`
+	FirmwareVersion string                 `protobuf:"bytes,8,opt,name=firmware_version,json=firmwareVersion,proto3" json:"firmware_version,omitempty"`
+	LinkSpeed       uint32                 `protobuf:"varint,9,opt,name=link_speed,json=linkSpeed,proto3" json:"link_speed,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *NetworkInterface) Reset() {
@@ -777,6 +804,27 @@ func (x *NetworkInterface) GetAddresses() []string {
 	return nil
 }
 
+func (x *NetworkInterface) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetFirmwareVersion() string {
+	if x != nil {
+		return x.FirmwareVersion
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetLinkSpeed() uint32 {
+	if x != nil {
+		return x.LinkSpeed
+	}
+	return 0
+}
+
 type NetworkInformation struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	InterfaceCount uint32                 `protobuf:"varint,1,opt,name=interface_count,json=interfaceCount,proto3" json:"interface_count,omitempty"`
@@ -829,6 +877,270 @@ func (x *NetworkInformation) GetInterfaces() []*NetworkInterface {
 	return nil
 }
 
+type PCIDevice struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Address           string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	VendorId          string                 `protobuf:"bytes,2,opt,name=vendor_id,json=vendorId,proto3" json:"vendor_id,omitempty"`
+	DeviceId          string                 `protobuf:"bytes,3,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	SubsystemVendorId string                 `protobuf:"bytes,4,opt,name=subsystem_vendor_id,json=subsystemVendorId,proto3" json:"subsystem_vendor_id,omitempty"`
+	SubsystemDeviceId string                 `protobuf:"bytes,5,opt,name=subsystem_device_id,json=subsystemDeviceId,proto3" json:"subsystem_device_id,omitempty"`
+	Class             string                 `protobuf:"bytes,6,opt,name=class,proto3" json:"class,omitempty"`
+	Driver            string                 `protobuf:"bytes,7,opt,name=driver,proto3" json:"driver,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *PCIDevice) Reset() {
+	*x = PCIDevice{}
+	mi := &file_api_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PCIDevice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PCIDevice) ProtoMessage() {}
+
+func (x *PCIDevice) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PCIDevice.ProtoReflect.Descriptor instead.
+func (*PCIDevice) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PCIDevice) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *PCIDevice) GetVendorId() string {
+	if x != nil {
+		return x.VendorId
+	}
+	return ""
+}
+
+func (x *PCIDevice) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *PCIDevice) GetSubsystemVendorId() string {
+	if x != nil {
+		return x.SubsystemVendorId
+	}
+	return ""
+}
+
+func (x *PCIDevice) GetSubsystemDeviceId() string {
+	if x != nil {
+		return x.SubsystemDeviceId
+	}
+	return ""
+}
+
+func (x *PCIDevice) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *PCIDevice) GetDriver() string {
+	if x != nil {
+		return x.Driver
+	}
+	return ""
+}
+
+type PCIInformation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceCount   uint32                 `protobuf:"varint,1,opt,name=device_count,json=deviceCount,proto3" json:"device_count,omitempty"`
+	GpuCount      uint32                 `protobuf:"varint,2,opt,name=gpu_count,json=gpuCount,proto3" json:"gpu_count,omitempty"`
+	Devices       []*PCIDevice           `protobuf:"bytes,3,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PCIInformation) Reset() {
+	*x = PCIInformation{}
+	mi := &file_api_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PCIInformation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PCIInformation) ProtoMessage() {}
+
+func (x *PCIInformation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PCIInformation.ProtoReflect.Descriptor instead.
+func (*PCIInformation) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PCIInformation) GetDeviceCount() uint32 {
+	if x != nil {
+		return x.DeviceCount
+	}
+	return 0
+}
+
+func (x *PCIInformation) GetGpuCount() uint32 {
+	if x != nil {
+		return x.GpuCount
+	}
+	return 0
+}
+
+func (x *PCIInformation) GetDevices() []*PCIDevice {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type BIOSInformation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Vendor        string                 `protobuf:"bytes,1,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	ReleaseDate   string                 `protobuf:"bytes,3,opt,name=release_date,json=releaseDate,proto3" json:"release_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BIOSInformation) Reset() {
+	*x = BIOSInformation{}
+	mi := &file_api_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BIOSInformation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BIOSInformation) ProtoMessage() {}
+
+func (x *BIOSInformation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BIOSInformation.ProtoReflect.Descriptor instead.
+func (*BIOSInformation) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BIOSInformation) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *BIOSInformation) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *BIOSInformation) GetReleaseDate() string {
+	if x != nil {
+		return x.ReleaseDate
+	}
+	return ""
+}
+
+type TPMInformation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Present       bool                   `protobuf:"varint,1,opt,name=present,proto3" json:"present,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TPMInformation) Reset() {
+	*x = TPMInformation{}
+	mi := &file_api_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TPMInformation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TPMInformation) ProtoMessage() {}
+
+func (x *TPMInformation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TPMInformation.ProtoReflect.Descriptor instead.
+func (*TPMInformation) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TPMInformation) GetPresent() bool {
+	if x != nil {
+		return x.Present
+	}
+	return false
+}
+
+func (x *TPMInformation) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
 type HardwareInformation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	System        *SystemInformation     `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
@@ -836,13 +1148,16 @@ type HardwareInformation struct {
 	Memory        *MemoryInformation     `protobuf:"bytes,3,opt,name=memory,proto3" json:"memory,omitempty"`
 	Storage       *StorageInformation    `protobuf:"bytes,4,opt,name=storage,proto3" json:"storage,omitempty"`
 	Network       *NetworkInformation    `protobuf:"bytes,5,opt,name=network,proto3" json:"network,omitempty"`
+	Pci           *PCIInformation        `protobuf:"bytes,6,opt,name=pci,proto3" json:"pci,omitempty"`
+	Bios          *BIOSInformation       `protobuf:"bytes,7,opt,name=bios,proto3" json:"bios,omitempty"`
+	Tpm           *TPMInformation        `protobuf:"bytes,8,opt,name=tpm,proto3" json:"tpm,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HardwareInformation) Reset() {
 	*x = HardwareInformation{}
-	mi := &file_api_proto_msgTypes[10]
+	mi := &file_api_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -854,7 +1169,7 @@ func (x *HardwareInformation) String() string {
 func (*HardwareInformation) ProtoMessage() {}
 
 func (x *HardwareInformation) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[10]
+	mi := &file_api_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -867,7 +1182,7 @@ func (x *HardwareInformation) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HardwareInformation.ProtoReflect.Descriptor instead.
 func (*HardwareInformation) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *HardwareInformation) GetSystem() *SystemInformation {
@@ -905,6 +1220,27 @@ func (x *HardwareInformation) GetNetwork() *NetworkInformation {
 	return nil
 }
 
+func (x *HardwareInformation) GetPci() *PCIInformation {
+	if x != nil {
+		return x.Pci
+	}
+	return nil
+}
+
+func (x *HardwareInformation) GetBios() *BIOSInformation {
+	if x != nil {
+		return x.Bios
+	}
+	return nil
+}
+
+func (x *HardwareInformation) GetTpm() *TPMInformation {
+	if x != nil {
+		return x.Tpm
+	}
+	return nil
+}
+
 type CreateServerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Hardware      *HardwareInformation   `protobuf:"bytes,1,opt,name=hardware,proto3" json:"hardware,omitempty"`
@@ -915,7 +1251,7 @@ type CreateServerRequest struct {
 
 func (x *CreateServerRequest) Reset() {
 	*x = CreateServerRequest{}
-	mi := &file_api_proto_msgTypes[11]
+	mi := &file_api_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -927,7 +1263,7 @@ func (x *CreateServerRequest) String() string {
 func (*CreateServerRequest) ProtoMessage() {}
 
 func (x *CreateServerRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[11]
+	mi := &file_api_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -940,7 +1276,7 @@ func (x *CreateServerRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateServerRequest.ProtoReflect.Descriptor instead.
 func (*CreateServerRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{11}
+	return file_api_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *CreateServerRequest) GetHardware() *HardwareInformation {
@@ -967,7 +1303,7 @@ type Address struct {
 
 func (x *Address) Reset() {
 	*x = Address{}
-	mi := &file_api_proto_msgTypes[12]
+	mi := &file_api_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -979,7 +1315,7 @@ func (x *Address) String() string {
 func (*Address) ProtoMessage() {}
 
 func (x *Address) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[12]
+	mi := &file_api_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -992,7 +1328,7 @@ func (x *Address) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Address.ProtoReflect.Descriptor instead.
 func (*Address) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{12}
+	return file_api_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *Address) GetType() string {
@@ -1009,19 +1345,225 @@ func (x *Address) GetAddress() string {
 	return ""
 }
 
+type DiskSelector struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wwid          string                 `protobuf:"bytes,1,opt,name=wwid,proto3" json:"wwid,omitempty"`
+	Serial        string                 `protobuf:"bytes,2,opt,name=serial,proto3" json:"serial,omitempty"`
+	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskSelector) Reset() {
+	*x = DiskSelector{}
+	mi := &file_api_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskSelector) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskSelector) ProtoMessage() {}
+
+func (x *DiskSelector) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskSelector.ProtoReflect.Descriptor instead.
+func (*DiskSelector) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DiskSelector) GetWwid() string {
+	if x != nil {
+		return x.Wwid
+	}
+	return ""
+}
+
+func (x *DiskSelector) GetSerial() string {
+	if x != nil {
+		return x.Serial
+	}
+	return ""
+}
+
+func (x *DiskSelector) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type WipePolicy struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Method             string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	StorageTypeMethods map[string]string      `protobuf:"bytes,2,rep,name=storage_type_methods,json=storageTypeMethods,proto3" json:"storage_type_methods,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Include            []*DiskSelector        `protobuf:"bytes,3,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude            []*DiskSelector        `protobuf:"bytes,4,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	SkipBootMedia      bool                   `protobuf:"varint,5,opt,name=skip_boot_media,json=skipBootMedia,proto3" json:"skip_boot_media,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WipePolicy) Reset() {
+	*x = WipePolicy{}
+	mi := &file_api_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WipePolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WipePolicy) ProtoMessage() {}
+
+func (x *WipePolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WipePolicy.ProtoReflect.Descriptor instead.
+func (*WipePolicy) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WipePolicy) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *WipePolicy) GetStorageTypeMethods() map[string]string {
+	if x != nil {
+		return x.StorageTypeMethods
+	}
+	return nil
+}
+
+func (x *WipePolicy) GetInclude() []*DiskSelector {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *WipePolicy) GetExclude() []*DiskSelector {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *WipePolicy) GetSkipBootMedia() bool {
+	if x != nil {
+		return x.SkipBootMedia
+	}
+	return false
+}
+
+type HardwareValidation struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	MemoryTestPasses  int32                  `protobuf:"varint,1,opt,name=memory_test_passes,json=memoryTestPasses,proto3" json:"memory_test_passes,omitempty"`
+	DiskHealth        bool                   `protobuf:"varint,2,opt,name=disk_health,json=diskHealth,proto3" json:"disk_health,omitempty"`
+	MinLinkSpeed      int32                  `protobuf:"varint,3,opt,name=min_link_speed,json=minLinkSpeed,proto3" json:"min_link_speed,omitempty"`
+	CpuStressDuration float64                `protobuf:"fixed64,4,opt,name=cpu_stress_duration,json=cpuStressDuration,proto3" json:"cpu_stress_duration,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *HardwareValidation) Reset() {
+	*x = HardwareValidation{}
+	mi := &file_api_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HardwareValidation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HardwareValidation) ProtoMessage() {}
+
+func (x *HardwareValidation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HardwareValidation.ProtoReflect.Descriptor instead.
+func (*HardwareValidation) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *HardwareValidation) GetMemoryTestPasses() int32 {
+	if x != nil {
+		return x.MemoryTestPasses
+	}
+	return 0
+}
+
+func (x *HardwareValidation) GetDiskHealth() bool {
+	if x != nil {
+		return x.DiskHealth
+	}
+	return false
+}
+
+func (x *HardwareValidation) GetMinLinkSpeed() int32 {
+	if x != nil {
+		return x.MinLinkSpeed
+	}
+	return 0
+}
+
+func (x *HardwareValidation) GetCpuStressDuration() float64 {
+	if x != nil {
+		return x.CpuStressDuration
+	}
+	return 0
+}
+
 type CreateServerResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Wipe          bool                   `protobuf:"varint,1,opt,name=wipe,proto3" json:"wipe,omitempty"`
 	InsecureWipe  bool                   `protobuf:"varint,2,opt,name=insecure_wipe,json=insecureWipe,proto3" json:"insecure_wipe,omitempty"`
 	SetupBmc      bool                   `protobuf:"varint,3,opt,name=setup_bmc,json=setupBmc,proto3" json:"setup_bmc,omitempty"`
 	RebootTimeout float64                `protobuf:"fixed64,4,opt,name=reboot_timeout,json=rebootTimeout,proto3" json:"reboot_timeout,omitempty"`
+	WipePolicy    *WipePolicy            `protobuf:"bytes,5,opt,name=wipe_policy,json=wipePolicy,proto3" json:"wipe_policy,omitempty"`
+	Validation    *HardwareValidation    `protobuf:"bytes,6,opt,name=validation,proto3" json:"validation,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateServerResponse) Reset() {
 	*x = CreateServerResponse{}
-	mi := &file_api_proto_msgTypes[13]
+	mi := &file_api_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1033,7 +1575,7 @@ func (x *CreateServerResponse) String() string {
 func (*CreateServerResponse) ProtoMessage() {}
 
 func (x *CreateServerResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[13]
+	mi := &file_api_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1046,7 +1588,7 @@ func (x *CreateServerResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateServerResponse.ProtoReflect.Descriptor instead.
 func (*CreateServerResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{13}
+	return file_api_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *CreateServerResponse) GetWipe() bool {
@@ -1077,6 +1619,20 @@ func (x *CreateServerResponse) GetRebootTimeout() float64 {
 	return 0
 }
 
+func (x *CreateServerResponse) GetWipePolicy() *WipePolicy {
+	if x != nil {
+		return x.WipePolicy
+	}
+	return nil
+}
+
+func (x *CreateServerResponse) GetValidation() *HardwareValidation {
+	if x != nil {
+		return x.Validation
+	}
+	return nil
+}
+
 type MarkServerAsWipedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
@@ -1086,7 +1642,7 @@ type MarkServerAsWipedRequest struct {
 
 func (x *MarkServerAsWipedRequest) Reset() {
 	*x = MarkServerAsWipedRequest{}
-	mi := &file_api_proto_msgTypes[14]
+	mi := &file_api_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1098,7 +1654,7 @@ func (x *MarkServerAsWipedRequest) String() string {
 func (*MarkServerAsWipedRequest) ProtoMessage() {}
 
 func (x *MarkServerAsWipedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[14]
+	mi := &file_api_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1111,7 +1667,7 @@ func (x *MarkServerAsWipedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MarkServerAsWipedRequest.ProtoReflect.Descriptor instead.
 func (*MarkServerAsWipedRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{14}
+	return file_api_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *MarkServerAsWipedRequest) GetUuid() string {
@@ -1130,7 +1686,7 @@ type HeartbeatRequest struct {
 
 func (x *HeartbeatRequest) Reset() {
 	*x = HeartbeatRequest{}
-	mi := &file_api_proto_msgTypes[15]
+	mi := &file_api_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1142,7 +1698,7 @@ func (x *HeartbeatRequest) String() string {
 func (*HeartbeatRequest) ProtoMessage() {}
 
 func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[15]
+	mi := &file_api_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1155,7 +1711,7 @@ func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
 func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{15}
+	return file_api_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *HeartbeatRequest) GetUuid() string {
@@ -1173,7 +1729,7 @@ type MarkServerAsWipedResponse struct {
 
 func (x *MarkServerAsWipedResponse) Reset() {
 	*x = MarkServerAsWipedResponse{}
-	mi := &file_api_proto_msgTypes[16]
+	mi := &file_api_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1185,7 +1741,7 @@ func (x *MarkServerAsWipedResponse) String() string {
 func (*MarkServerAsWipedResponse) ProtoMessage() {}
 
 func (x *MarkServerAsWipedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[16]
+	mi := &file_api_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1198,7 +1754,7 @@ func (x *MarkServerAsWipedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MarkServerAsWipedResponse.ProtoReflect.Descriptor instead.
 func (*MarkServerAsWipedResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{16}
+	return file_api_proto_rawDescGZIP(), []int{23}
 }
 
 type HeartbeatResponse struct {
@@ -1209,7 +1765,7 @@ type HeartbeatResponse struct {
 
 func (x *HeartbeatResponse) Reset() {
 	*x = HeartbeatResponse{}
-	mi := &file_api_proto_msgTypes[17]
+	mi := &file_api_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1221,7 +1777,7 @@ func (x *HeartbeatResponse) String() string {
 func (*HeartbeatResponse) ProtoMessage() {}
 
 func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[17]
+	mi := &file_api_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1234,7 +1790,7 @@ func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
 func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{17}
+	return file_api_proto_rawDescGZIP(), []int{24}
 }
 
 type UpdateBMCInfoRequest struct {
@@ -1247,7 +1803,7 @@ type UpdateBMCInfoRequest struct {
 
 func (x *UpdateBMCInfoRequest) Reset() {
 	*x = UpdateBMCInfoRequest{}
-	mi := &file_api_proto_msgTypes[18]
+	mi := &file_api_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1259,7 +1815,7 @@ func (x *UpdateBMCInfoRequest) String() string {
 func (*UpdateBMCInfoRequest) ProtoMessage() {}
 
 func (x *UpdateBMCInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[18]
+	mi := &file_api_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1272,7 +1828,7 @@ func (x *UpdateBMCInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateBMCInfoRequest.ProtoReflect.Descriptor instead.
 func (*UpdateBMCInfoRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{18}
+	return file_api_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *UpdateBMCInfoRequest) GetUuid() string {
@@ -1297,7 +1853,7 @@ type UpdateBMCInfoResponse struct {
 
 func (x *UpdateBMCInfoResponse) Reset() {
 	*x = UpdateBMCInfoResponse{}
-	mi := &file_api_proto_msgTypes[19]
+	mi := &file_api_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1309,7 +1865,7 @@ func (x *UpdateBMCInfoResponse) String() string {
 func (*UpdateBMCInfoResponse) ProtoMessage() {}
 
 func (x *UpdateBMCInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[19]
+	mi := &file_api_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1322,7 +1878,7 @@ func (x *UpdateBMCInfoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateBMCInfoResponse.ProtoReflect.Descriptor instead.
 func (*UpdateBMCInfoResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{19}
+	return file_api_proto_rawDescGZIP(), []int{26}
 }
 
 type ReconcileServerAddressesRequest struct {
@@ -1335,7 +1891,7 @@ type ReconcileServerAddressesRequest struct {
 
 func (x *ReconcileServerAddressesRequest) Reset() {
 	*x = ReconcileServerAddressesRequest{}
-	mi := &file_api_proto_msgTypes[20]
+	mi := &file_api_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1347,7 +1903,7 @@ func (x *ReconcileServerAddressesRequest) String() string {
 func (*ReconcileServerAddressesRequest) ProtoMessage() {}
 
 func (x *ReconcileServerAddressesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[20]
+	mi := &file_api_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1360,7 +1916,7 @@ func (x *ReconcileServerAddressesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReconcileServerAddressesRequest.ProtoReflect.Descriptor instead.
 func (*ReconcileServerAddressesRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{20}
+	return file_api_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *ReconcileServerAddressesRequest) GetUuid() string {
@@ -1385,7 +1941,7 @@ type ReconcileServerAddressesResponse struct {
 
 func (x *ReconcileServerAddressesResponse) Reset() {
 	*x = ReconcileServerAddressesResponse{}
-	mi := &file_api_proto_msgTypes[21]
+	mi := &file_api_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1397,7 +1953,7 @@ func (x *ReconcileServerAddressesResponse) String() string {
 func (*ReconcileServerAddressesResponse) ProtoMessage() {}
 
 func (x *ReconcileServerAddressesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_msgTypes[21]
+	mi := &file_api_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1410,7 +1966,319 @@ func (x *ReconcileServerAddressesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReconcileServerAddressesResponse.ProtoReflect.Descriptor instead.
 func (*ReconcileServerAddressesResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_rawDescGZIP(), []int{21}
+	return file_api_proto_rawDescGZIP(), []int{28}
+}
+
+type DiskWipeProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceName    string                 `protobuf:"bytes,1,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	BytesWiped    uint64                 `protobuf:"varint,2,opt,name=bytes_wiped,json=bytesWiped,proto3" json:"bytes_wiped,omitempty"`
+	TotalBytes    uint64                 `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	Method        string                 `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	Complete      bool                   `protobuf:"varint,5,opt,name=complete,proto3" json:"complete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiskWipeProgress) Reset() {
+	*x = DiskWipeProgress{}
+	mi := &file_api_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiskWipeProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiskWipeProgress) ProtoMessage() {}
+
+func (x *DiskWipeProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiskWipeProgress.ProtoReflect.Descriptor instead.
+func (*DiskWipeProgress) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *DiskWipeProgress) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *DiskWipeProgress) GetBytesWiped() uint64 {
+	if x != nil {
+		return x.BytesWiped
+	}
+	return 0
+}
+
+func (x *DiskWipeProgress) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *DiskWipeProgress) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *DiskWipeProgress) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+type ReportWipeProgressRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uuid          string                 `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Disks         []*DiskWipeProgress    `protobuf:"bytes,2,rep,name=disks,proto3" json:"disks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportWipeProgressRequest) Reset() {
+	*x = ReportWipeProgressRequest{}
+	mi := &file_api_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportWipeProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportWipeProgressRequest) ProtoMessage() {}
+
+func (x *ReportWipeProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportWipeProgressRequest.ProtoReflect.Descriptor instead.
+func (*ReportWipeProgressRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ReportWipeProgressRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ReportWipeProgressRequest) GetDisks() []*DiskWipeProgress {
+	if x != nil {
+		return x.Disks
+	}
+	return nil
+}
+
+type ReportWipeProgressResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportWipeProgressResponse) Reset() {
+	*x = ReportWipeProgressResponse{}
+	mi := &file_api_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportWipeProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportWipeProgressResponse) ProtoMessage() {}
+
+func (x *ReportWipeProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportWipeProgressResponse.ProtoReflect.Descriptor instead.
+func (*ReportWipeProgressResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{31}
+}
+
+type HardwareValidationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Passed        bool                   `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HardwareValidationResult) Reset() {
+	*x = HardwareValidationResult{}
+	mi := &file_api_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HardwareValidationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HardwareValidationResult) ProtoMessage() {}
+
+func (x *HardwareValidationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HardwareValidationResult.ProtoReflect.Descriptor instead.
+func (*HardwareValidationResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *HardwareValidationResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HardwareValidationResult) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *HardwareValidationResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ReportHardwareValidationRequest struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Uuid          string                      `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Results       []*HardwareValidationResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportHardwareValidationRequest) Reset() {
+	*x = ReportHardwareValidationRequest{}
+	mi := &file_api_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportHardwareValidationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportHardwareValidationRequest) ProtoMessage() {}
+
+func (x *ReportHardwareValidationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportHardwareValidationRequest.ProtoReflect.Descriptor instead.
+func (*ReportHardwareValidationRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ReportHardwareValidationRequest) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *ReportHardwareValidationRequest) GetResults() []*HardwareValidationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ReportHardwareValidationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportHardwareValidationResponse) Reset() {
+	*x = ReportHardwareValidationResponse{}
+	mi := &file_api_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportHardwareValidationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportHardwareValidationResponse) ProtoMessage() {}
+
+func (x *ReportHardwareValidationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportHardwareValidationResponse.ProtoReflect.Descriptor instead.
+func (*ReportHardwareValidationResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_rawDescGZIP(), []int{34}
 }
 
 var File_api_proto protoreflect.FileDescriptor
@@ -1458,7 +2326,7 @@ const file_api_proto_rawDesc = "" +
 	"\n" +
 	"total_size\x18\x01 \x01(\rR\ttotalSize\x12!\n" +
 	"\fmodule_count\x18\x02 \x01(\rR\vmoduleCount\x12+\n" +
-	"\amodules\x18\x03 \x03(\v2\x11.api.MemoryModuleR\amodules\"\xd4\x01\n" +
+	"\amodules\x18\x03 \x03(\v2\x11.api.MemoryModuleR\amodules\"\xb5\x02\n" +
 	"\rStorageDevice\x12$\n" +
 	"\x04type\x18\x01 \x01(\x0e2\x10.api.StorageTypeR\x04type\x12\x12\n" +
 	"\x04size\x18\x02 \x01(\x04R\x04size\x12\x14\n" +
@@ -1468,41 +2336,98 @@ const file_api_proto_rawDesc = "" +
 	"\vdevice_name\x18\x06 \x01(\tR\n" +
 	"deviceName\x12\x12\n" +
 	"\x04uuid\x18\a \x01(\tR\x04uuid\x12\x12\n" +
-	"\x04wwid\x18\b \x01(\tR\x04wwid\"\x84\x01\n" +
+	"\x04wwid\x18\b \x01(\tR\x04wwid\x12\x1e\n" +
+	"\n" +
+	"rotational\x18\t \x01(\bR\n" +
+	"rotational\x12\x1c\n" +
+	"\ttransport\x18\n" +
+	" \x01(\tR\ttransport\x12!\n" +
+	"\fsmart_health\x18\v \x01(\tR\vsmartHealth\"\x84\x01\n" +
 	"\x12StorageInformation\x12\x1d\n" +
 	"\n" +
 	"total_size\x18\x01 \x01(\x04R\ttotalSize\x12!\n" +
 	"\fdevice_count\x18\x02 \x01(\rR\vdeviceCount\x12,\n" +
-	"\adevices\x18\x03 \x03(\v2\x12.api.StorageDeviceR\adevices\"\x94\x01\n" +
+	"\adevices\x18\x03 \x03(\v2\x12.api.StorageDeviceR\adevices\"\xf6\x01\n" +
 	"\x10NetworkInterface\x12\x14\n" +
 	"\x05index\x18\x01 \x01(\rR\x05index\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05flags\x18\x03 \x01(\tR\x05flags\x12\x10\n" +
 	"\x03mtu\x18\x04 \x01(\rR\x03mtu\x12\x10\n" +
 	"\x03mac\x18\x05 \x01(\tR\x03mac\x12\x1c\n" +
-	"\taddresses\x18\x06 \x03(\tR\taddresses\"t\n" +
+	"\taddresses\x18\x06 \x03(\tR\taddresses\x12\x16\n" +
+	"\x06driver\x18\a \x01(\tR\x06driver\x12)\n" +
+	"\x10firmware_version\x18\b \x01(\tR\x0ffirmwareVersion\x12\x1d\n" +
+	"\n" +
+	"link_speed\x18\t \x01(\rR\tlinkSpeed\"t\n" +
 	"\x12NetworkInformation\x12'\n" +
 	"\x0finterface_count\x18\x01 \x01(\rR\x0einterfaceCount\x125\n" +
 	"\n" +
 	"interfaces\x18\x02 \x03(\v2\x15.api.NetworkInterfaceR\n" +
-	"interfaces\"\x8e\x02\n" +
+	"interfaces\"\xed\x01\n" +
+	"\tPCIDevice\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x1b\n" +
+	"\tvendor_id\x18\x02 \x01(\tR\bvendorId\x12\x1b\n" +
+	"\tdevice_id\x18\x03 \x01(\tR\bdeviceId\x12.\n" +
+	"\x13subsystem_vendor_id\x18\x04 \x01(\tR\x11subsystemVendorId\x12.\n" +
+	"\x13subsystem_device_id\x18\x05 \x01(\tR\x11subsystemDeviceId\x12\x14\n" +
+	"\x05class\x18\x06 \x01(\tR\x05class\x12\x16\n" +
+	"\x06driver\x18\a \x01(\tR\x06driver\"z\n" +
+	"\x0ePCIInformation\x12!\n" +
+	"\fdevice_count\x18\x01 \x01(\rR\vdeviceCount\x12\x1b\n" +
+	"\tgpu_count\x18\x02 \x01(\rR\bgpuCount\x12(\n" +
+	"\adevices\x18\x03 \x03(\v2\x0e.api.PCIDeviceR\adevices\"f\n" +
+	"\x0fBIOSInformation\x12\x16\n" +
+	"\x06vendor\x18\x01 \x01(\tR\x06vendor\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12!\n" +
+	"\frelease_date\x18\x03 \x01(\tR\vreleaseDate\"D\n" +
+	"\x0eTPMInformation\x12\x18\n" +
+	"\apresent\x18\x01 \x01(\bR\apresent\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"\x86\x03\n" +
 	"\x13HardwareInformation\x12.\n" +
 	"\x06system\x18\x01 \x01(\v2\x16.api.SystemInformationR\x06system\x121\n" +
 	"\acompute\x18\x02 \x01(\v2\x17.api.ComputeInformationR\acompute\x12.\n" +
 	"\x06memory\x18\x03 \x01(\v2\x16.api.MemoryInformationR\x06memory\x121\n" +
 	"\astorage\x18\x04 \x01(\v2\x17.api.StorageInformationR\astorage\x121\n" +
-	"\anetwork\x18\x05 \x01(\v2\x17.api.NetworkInformationR\anetwork\"g\n" +
+	"\anetwork\x18\x05 \x01(\v2\x17.api.NetworkInformationR\anetwork\x12%\n" +
+	"\x03pci\x18\x06 \x01(\v2\x13.api.PCIInformationR\x03pci\x12(\n" +
+	"\x04bios\x18\a \x01(\v2\x14.api.BIOSInformationR\x04bios\x12%\n" +
+	"\x03tpm\x18\b \x01(\v2\x13.api.TPMInformationR\x03tpm\"g\n" +
 	"\x13CreateServerRequest\x124\n" +
 	"\bhardware\x18\x01 \x01(\v2\x18.api.HardwareInformationR\bhardware\x12\x1a\n" +
 	"\bhostname\x18\x03 \x01(\tR\bhostname\"7\n" +
 	"\aAddress\x12\x12\n" +
 	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
-	"\aaddress\x18\x02 \x01(\tR\aaddress\"\x93\x01\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"P\n" +
+	"\fDiskSelector\x12\x12\n" +
+	"\x04wwid\x18\x01 \x01(\tR\x04wwid\x12\x16\n" +
+	"\x06serial\x18\x02 \x01(\tR\x06serial\x12\x14\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\"\xc8\x02\n" +
+	"\n" +
+	"WipePolicy\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12Y\n" +
+	"\x14storage_type_methods\x18\x02 \x03(\v2'.api.WipePolicy.StorageTypeMethodsEntryR\x12storageTypeMethods\x12+\n" +
+	"\ainclude\x18\x03 \x03(\v2\x11.api.DiskSelectorR\ainclude\x12+\n" +
+	"\aexclude\x18\x04 \x03(\v2\x11.api.DiskSelectorR\aexclude\x12&\n" +
+	"\x0fskip_boot_media\x18\x05 \x01(\bR\rskipBootMedia\x1aE\n" +
+	"\x17StorageTypeMethodsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb9\x01\n" +
+	"\x12HardwareValidation\x12,\n" +
+	"\x12memory_test_passes\x18\x01 \x01(\x05R\x10memoryTestPasses\x12\x1f\n" +
+	"\vdisk_health\x18\x02 \x01(\bR\n" +
+	"diskHealth\x12$\n" +
+	"\x0emin_link_speed\x18\x03 \x01(\x05R\fminLinkSpeed\x12.\n" +
+	"\x13cpu_stress_duration\x18\x04 \x01(\x01R\x11cpuStressDuration\"\xfe\x01\n" +
 	"\x14CreateServerResponse\x12\x12\n" +
 	"\x04wipe\x18\x01 \x01(\bR\x04wipe\x12#\n" +
 	"\rinsecure_wipe\x18\x02 \x01(\bR\finsecureWipe\x12\x1b\n" +
 	"\tsetup_bmc\x18\x03 \x01(\bR\bsetupBmc\x12%\n" +
-	"\x0ereboot_timeout\x18\x04 \x01(\x01R\rrebootTimeout\".\n" +
+	"\x0ereboot_timeout\x18\x04 \x01(\x01R\rrebootTimeout\x120\n" +
+	"\vwipe_policy\x18\x05 \x01(\v2\x0f.api.WipePolicyR\n" +
+	"wipePolicy\x127\n" +
+	"\n" +
+	"validation\x18\x06 \x01(\v2\x17.api.HardwareValidationR\n" +
+	"validation\".\n" +
 	"\x18MarkServerAsWipedRequest\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\"&\n" +
 	"\x10HeartbeatRequest\x12\x12\n" +
@@ -1516,19 +2441,42 @@ const file_api_proto_rawDesc = "" +
 	"\x1fReconcileServerAddressesRequest\x12\x12\n" +
 	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12&\n" +
 	"\aaddress\x18\x02 \x03(\v2\f.api.AddressR\aaddress\"\"\n" +
-	" ReconcileServerAddressesResponse*>\n" +
+	" ReconcileServerAddressesResponse\"\xa9\x01\n" +
+	"\x10DiskWipeProgress\x12\x1f\n" +
+	"\vdevice_name\x18\x01 \x01(\tR\n" +
+	"deviceName\x12\x1f\n" +
+	"\vbytes_wiped\x18\x02 \x01(\x04R\n" +
+	"bytesWiped\x12\x1f\n" +
+	"\vtotal_bytes\x18\x03 \x01(\x04R\n" +
+	"totalBytes\x12\x16\n" +
+	"\x06method\x18\x04 \x01(\tR\x06method\x12\x1a\n" +
+	"\bcomplete\x18\x05 \x01(\bR\bcomplete\"\\\n" +
+	"\x19ReportWipeProgressRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x12+\n" +
+	"\x05disks\x18\x02 \x03(\v2\x15.api.DiskWipeProgressR\x05disks\"\x1c\n" +
+	"\x1aReportWipeProgressResponse\"`\n" +
+	"\x18HardwareValidationResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06passed\x18\x02 \x01(\bR\x06passed\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"n\n" +
+	"\x1fReportHardwareValidationRequest\x12\x12\n" +
+	"\x04uuid\x18\x01 \x01(\tR\x04uuid\x127\n" +
+	"\aresults\x18\x02 \x03(\v2\x1d.api.HardwareValidationResultR\aresults\"\"\n" +
+	" ReportHardwareValidationResponse*>\n" +
 	"\vStorageType\x12\v\n" +
 	"\aUnknown\x10\x00\x12\a\n" +
 	"\x03SSD\x10\x01\x12\a\n" +
 	"\x03HDD\x10\x02\x12\b\n" +
 	"\x04NVMe\x10\x03\x12\x06\n" +
-	"\x02SD\x10\x042\x8d\x03\n" +
+	"\x02SD\x10\x042\xcd\x04\n" +
 	"\x05Agent\x12C\n" +
 	"\fCreateServer\x12\x18.api.CreateServerRequest\x1a\x19.api.CreateServerResponse\x12R\n" +
 	"\x11MarkServerAsWiped\x12\x1d.api.MarkServerAsWipedRequest\x1a\x1e.api.MarkServerAsWipedResponse\x12g\n" +
 	"\x18ReconcileServerAddresses\x12$.api.ReconcileServerAddressesRequest\x1a%.api.ReconcileServerAddressesResponse\x12:\n" +
 	"\tHeartbeat\x12\x15.api.HeartbeatRequest\x1a\x16.api.HeartbeatResponse\x12F\n" +
-	"\rUpdateBMCInfo\x12\x19.api.UpdateBMCInfoRequest\x1a\x1a.api.UpdateBMCInfoResponseBLZJgithub.com/talos-systems/sidero/app/sidero-controller-manager/internal/apib\x06proto3"
+	"\rUpdateBMCInfo\x12\x19.api.UpdateBMCInfoRequest\x1a\x1a.api.UpdateBMCInfoResponse\x12U\n" +
+	"\x12ReportWipeProgress\x12\x1e.api.ReportWipeProgressRequest\x1a\x1f.api.ReportWipeProgressResponse\x12g\n" +
+	"\x18ReportHardwareValidation\x12$.api.ReportHardwareValidationRequest\x1a%.api.ReportHardwareValidationResponseBLZJgithub.com/talos-systems/sidero/app/sidero-controller-manager/internal/apib\x06proto3"
 
 var (
 	file_api_proto_rawDescOnce sync.Once
@@ -1544,7 +2492,7 @@ func file_api_proto_rawDescGZIP() []byte {
 
 var (
 	file_api_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-	file_api_proto_msgTypes  = make([]protoimpl.MessageInfo, 22)
+	file_api_proto_msgTypes  = make([]protoimpl.MessageInfo, 36)
 	file_api_proto_goTypes   = []any{
 		(StorageType)(0),                         // 0: api.StorageType
 		(*BMCInfo)(nil),                          // 1: api.BMCInfo
@@ -1557,18 +2505,32 @@ var (
 		(*StorageInformation)(nil),               // 8: api.StorageInformation
 		(*NetworkInterface)(nil),                 // 9: api.NetworkInterface
 		(*NetworkInformation)(nil),               // 10: api.NetworkInformation
-		(*HardwareInformation)(nil),              // 11: api.HardwareInformation
-		(*CreateServerRequest)(nil),              // 12: api.CreateServerRequest
-		(*Address)(nil),                          // 13: api.Address
-		(*CreateServerResponse)(nil),             // 14: api.CreateServerResponse
-		(*MarkServerAsWipedRequest)(nil),         // 15: api.MarkServerAsWipedRequest
-		(*HeartbeatRequest)(nil),                 // 16: api.HeartbeatRequest
-		(*MarkServerAsWipedResponse)(nil),        // 17: api.MarkServerAsWipedResponse
-		(*HeartbeatResponse)(nil),                // 18: api.HeartbeatResponse
-		(*UpdateBMCInfoRequest)(nil),             // 19: api.UpdateBMCInfoRequest
-		(*UpdateBMCInfoResponse)(nil),            // 20: api.UpdateBMCInfoResponse
-		(*ReconcileServerAddressesRequest)(nil),  // 21: api.ReconcileServerAddressesRequest
-		(*ReconcileServerAddressesResponse)(nil), // 22: api.ReconcileServerAddressesResponse
+		(*PCIDevice)(nil),                        // 11: api.PCIDevice
+		(*PCIInformation)(nil),                   // 12: api.PCIInformation
+		(*BIOSInformation)(nil),                  // 13: api.BIOSInformation
+		(*TPMInformation)(nil),                   // 14: api.TPMInformation
+		(*HardwareInformation)(nil),              // 15: api.HardwareInformation
+		(*CreateServerRequest)(nil),              // 16: api.CreateServerRequest
+		(*Address)(nil),                          // 17: api.Address
+		(*DiskSelector)(nil),                     // 18: api.DiskSelector
+		(*WipePolicy)(nil),                       // 19: api.WipePolicy
+		(*HardwareValidation)(nil),               // 20: api.HardwareValidation
+		(*CreateServerResponse)(nil),             // 21: api.CreateServerResponse
+		(*MarkServerAsWipedRequest)(nil),         // 22: api.MarkServerAsWipedRequest
+		(*HeartbeatRequest)(nil),                 // 23: api.HeartbeatRequest
+		(*MarkServerAsWipedResponse)(nil),        // 24: api.MarkServerAsWipedResponse
+		(*HeartbeatResponse)(nil),                // 25: api.HeartbeatResponse
+		(*UpdateBMCInfoRequest)(nil),             // 26: api.UpdateBMCInfoRequest
+		(*UpdateBMCInfoResponse)(nil),            // 27: api.UpdateBMCInfoResponse
+		(*ReconcileServerAddressesRequest)(nil),  // 28: api.ReconcileServerAddressesRequest
+		(*ReconcileServerAddressesResponse)(nil), // 29: api.ReconcileServerAddressesResponse
+		(*DiskWipeProgress)(nil),                 // 30: api.DiskWipeProgress
+		(*ReportWipeProgressRequest)(nil),        // 31: api.ReportWipeProgressRequest
+		(*ReportWipeProgressResponse)(nil),       // 32: api.ReportWipeProgressResponse
+		(*HardwareValidationResult)(nil),         // 33: api.HardwareValidationResult
+		(*ReportHardwareValidationRequest)(nil),  // 34: api.ReportHardwareValidationRequest
+		(*ReportHardwareValidationResponse)(nil), // 35: api.ReportHardwareValidationResponse
+		nil,                                      // 36: api.WipePolicy.StorageTypeMethodsEntry
 	}
 )
 
@@ -1578,29 +2540,44 @@ var file_api_proto_depIdxs = []int32{
 	0,  // 2: api.StorageDevice.type:type_name -> api.StorageType
 	7,  // 3: api.StorageInformation.devices:type_name -> api.StorageDevice
 	9,  // 4: api.NetworkInformation.interfaces:type_name -> api.NetworkInterface
-	2,  // 5: api.HardwareInformation.system:type_name -> api.SystemInformation
-	4,  // 6: api.HardwareInformation.compute:type_name -> api.ComputeInformation
-	6,  // 7: api.HardwareInformation.memory:type_name -> api.MemoryInformation
-	8,  // 8: api.HardwareInformation.storage:type_name -> api.StorageInformation
-	10, // 9: api.HardwareInformation.network:type_name -> api.NetworkInformation
-	11, // 10: api.CreateServerRequest.hardware:type_name -> api.HardwareInformation
-	1,  // 11: api.UpdateBMCInfoRequest.bmc_info:type_name -> api.BMCInfo
-	13, // 12: api.ReconcileServerAddressesRequest.address:type_name -> api.Address
-	12, // 13: api.Agent.CreateServer:input_type -> api.CreateServerRequest
-	15, // 14: api.Agent.MarkServerAsWiped:input_type -> api.MarkServerAsWipedRequest
-	21, // 15: api.Agent.ReconcileServerAddresses:input_type -> api.ReconcileServerAddressesRequest
-	16, // 16: api.Agent.Heartbeat:input_type -> api.HeartbeatRequest
-	19, // 17: api.Agent.UpdateBMCInfo:input_type -> api.UpdateBMCInfoRequest
-	14, // 18: api.Agent.CreateServer:output_type -> api.CreateServerResponse
-	17, // 19: api.Agent.MarkServerAsWiped:output_type -> api.MarkServerAsWipedResponse
-	22, // 20: api.Agent.ReconcileServerAddresses:output_type -> api.ReconcileServerAddressesResponse
-	18, // 21: api.Agent.Heartbeat:output_type -> api.HeartbeatResponse
-	20, // 22: api.Agent.UpdateBMCInfo:output_type -> api.UpdateBMCInfoResponse
-	18, // [18:23] is the sub-list for method output_type
-	13, // [13:18] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+	11, // 5: api.PCIInformation.devices:type_name -> api.PCIDevice
+	2,  // 6: api.HardwareInformation.system:type_name -> api.SystemInformation
+	4,  // 7: api.HardwareInformation.compute:type_name -> api.ComputeInformation
+	6,  // 8: api.HardwareInformation.memory:type_name -> api.MemoryInformation
+	8,  // 9: api.HardwareInformation.storage:type_name -> api.StorageInformation
+	10, // 10: api.HardwareInformation.network:type_name -> api.NetworkInformation
+	12, // 11: api.HardwareInformation.pci:type_name -> api.PCIInformation
+	13, // 12: api.HardwareInformation.bios:type_name -> api.BIOSInformation
+	14, // 13: api.HardwareInformation.tpm:type_name -> api.TPMInformation
+	15, // 14: api.CreateServerRequest.hardware:type_name -> api.HardwareInformation
+	36, // 15: api.WipePolicy.storage_type_methods:type_name -> api.WipePolicy.StorageTypeMethodsEntry
+	18, // 16: api.WipePolicy.include:type_name -> api.DiskSelector
+	18, // 17: api.WipePolicy.exclude:type_name -> api.DiskSelector
+	19, // 18: api.CreateServerResponse.wipe_policy:type_name -> api.WipePolicy
+	20, // 19: api.CreateServerResponse.validation:type_name -> api.HardwareValidation
+	1,  // 20: api.UpdateBMCInfoRequest.bmc_info:type_name -> api.BMCInfo
+	17, // 21: api.ReconcileServerAddressesRequest.address:type_name -> api.Address
+	30, // 22: api.ReportWipeProgressRequest.disks:type_name -> api.DiskWipeProgress
+	33, // 23: api.ReportHardwareValidationRequest.results:type_name -> api.HardwareValidationResult
+	16, // 24: api.Agent.CreateServer:input_type -> api.CreateServerRequest
+	22, // 25: api.Agent.MarkServerAsWiped:input_type -> api.MarkServerAsWipedRequest
+	28, // 26: api.Agent.ReconcileServerAddresses:input_type -> api.ReconcileServerAddressesRequest
+	23, // 27: api.Agent.Heartbeat:input_type -> api.HeartbeatRequest
+	26, // 28: api.Agent.UpdateBMCInfo:input_type -> api.UpdateBMCInfoRequest
+	31, // 29: api.Agent.ReportWipeProgress:input_type -> api.ReportWipeProgressRequest
+	34, // 30: api.Agent.ReportHardwareValidation:input_type -> api.ReportHardwareValidationRequest
+	21, // 31: api.Agent.CreateServer:output_type -> api.CreateServerResponse
+	24, // 32: api.Agent.MarkServerAsWiped:output_type -> api.MarkServerAsWipedResponse
+	29, // 33: api.Agent.ReconcileServerAddresses:output_type -> api.ReconcileServerAddressesResponse
+	25, // 34: api.Agent.Heartbeat:output_type -> api.HeartbeatResponse
+	27, // 35: api.Agent.UpdateBMCInfo:output_type -> api.UpdateBMCInfoResponse
+	32, // 36: api.Agent.ReportWipeProgress:output_type -> api.ReportWipeProgressResponse
+	35, // 37: api.Agent.ReportHardwareValidation:output_type -> api.ReportHardwareValidationResponse
+	31, // [31:38] is the sub-list for method output_type
+	24, // [24:31] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_init() }
@@ -1614,7 +2591,7 @@ func file_api_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_rawDesc), len(file_api_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   22,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,8 @@ const (
 	Agent_ReconcileServerAddresses_FullMethodName = "/api.Agent/ReconcileServerAddresses"
 	Agent_Heartbeat_FullMethodName                = "/api.Agent/Heartbeat"
 	Agent_UpdateBMCInfo_FullMethodName            = "/api.Agent/UpdateBMCInfo"
+	Agent_ReportWipeProgress_FullMethodName       = "/api.Agent/ReportWipeProgress"
+	Agent_ReportHardwareValidation_FullMethodName = "/api.Agent/ReportHardwareValidation"
 )
 
 // AgentClient is the client API for Agent service.
@@ -36,6 +38,8 @@ type AgentClient interface {
 	ReconcileServerAddresses(ctx context.Context, in *ReconcileServerAddressesRequest, opts ...grpc.CallOption) (*ReconcileServerAddressesResponse, error)
 	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
 	UpdateBMCInfo(ctx context.Context, in *UpdateBMCInfoRequest, opts ...grpc.CallOption) (*UpdateBMCInfoResponse, error)
+	ReportWipeProgress(ctx context.Context, in *ReportWipeProgressRequest, opts ...grpc.CallOption) (*ReportWipeProgressResponse, error)
+	ReportHardwareValidation(ctx context.Context, in *ReportHardwareValidationRequest, opts ...grpc.CallOption) (*ReportHardwareValidationResponse, error)
 }
 
 type agentClient struct {
@@ -96,6 +100,26 @@ func (c *agentClient) UpdateBMCInfo(ctx context.Context, in *UpdateBMCInfoReques
 	return out, nil
 }
 
+func (c *agentClient) ReportWipeProgress(ctx context.Context, in *ReportWipeProgressRequest, opts ...grpc.CallOption) (*ReportWipeProgressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportWipeProgressResponse)
+	err := c.cc.Invoke(ctx, Agent_ReportWipeProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) ReportHardwareValidation(ctx context.Context, in *ReportHardwareValidationRequest, opts ...grpc.CallOption) (*ReportHardwareValidationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportHardwareValidationResponse)
+	err := c.cc.Invoke(ctx, Agent_ReportHardwareValidation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 // All implementations must embed UnimplementedAgentServer
 // for forward compatibility.
@@ -105,6 +129,8 @@ type AgentServer interface {
 	ReconcileServerAddresses(context.Context, *ReconcileServerAddressesRequest) (*ReconcileServerAddressesResponse, error)
 	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
 	UpdateBMCInfo(context.Context, *UpdateBMCInfoRequest) (*UpdateBMCInfoResponse, error)
+	ReportWipeProgress(context.Context, *ReportWipeProgressRequest) (*ReportWipeProgressResponse, error)
+	ReportHardwareValidation(context.Context, *ReportHardwareValidationRequest) (*ReportHardwareValidationResponse, error)
 	mustEmbedUnimplementedAgentServer()
 }
 
@@ -134,6 +160,14 @@ func (UnimplementedAgentServer) Heartbeat(context.Context, *HeartbeatRequest) (*
 func (UnimplementedAgentServer) UpdateBMCInfo(context.Context, *UpdateBMCInfoRequest) (*UpdateBMCInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateBMCInfo not implemented")
 }
+
+func (UnimplementedAgentServer) ReportWipeProgress(context.Context, *ReportWipeProgressRequest) (*ReportWipeProgressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportWipeProgress not implemented")
+}
+
+func (UnimplementedAgentServer) ReportHardwareValidation(context.Context, *ReportHardwareValidationRequest) (*ReportHardwareValidationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportHardwareValidation not implemented")
+}
 func (UnimplementedAgentServer) mustEmbedUnimplementedAgentServer() {}
 func (UnimplementedAgentServer) testEmbeddedByValue()               {}
 
@@ -245,6 +279,42 @@ func _Agent_UpdateBMCInfo_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_ReportWipeProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportWipeProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ReportWipeProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_ReportWipeProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ReportWipeProgress(ctx, req.(*ReportWipeProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_ReportHardwareValidation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportHardwareValidationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ReportHardwareValidation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_ReportHardwareValidation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ReportHardwareValidation(ctx, req.(*ReportHardwareValidationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Agent_ServiceDesc is the grpc.ServiceDesc for Agent service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -272,6 +342,14 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateBMCInfo",
 			Handler:    _Agent_UpdateBMCInfo_Handler,
 		},
+		{
+			MethodName: "ReportWipeProgress",
+			Handler:    _Agent_ReportWipeProgress_Handler,
+		},
+		{
+			MethodName: "ReportHardwareValidation",
+			Handler:    _Agent_ReportHardwareValidation_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
@@ -0,0 +1,41 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package assets
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	cacheHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sidero_asset_cache_hits_total",
+		Help: "Number of the Environment assets installed from the asset cache.",
+	})
+
+	cacheMisses = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sidero_asset_cache_misses_total",
+		Help: "Number of the Environment assets downloaded as they were missing in the asset cache.",
+	})
+
+	cacheBlobs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "sidero_asset_cache_blobs",
+		Help: "Number of the blobs in the asset cache.",
+	})
+
+	cacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "sidero_asset_cache_size_bytes",
+		Help: "Total size of the blobs in the asset cache.",
+	})
+
+	gcCollected = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "sidero_asset_cache_gc_collected_total",
+		Help: "Number of the unreferenced blobs removed from the asset cache.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(cacheHits, cacheMisses, cacheBlobs, cacheSize, gcCollected)
+}
@@ -0,0 +1,325 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Source opens the asset contents by the URL.
+type Source interface {
+	Open(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// Sources opens the assets by the URL scheme.
+//
+// The zero value supports the public HTTP(S) and OCI sources only.
+type Sources struct {
+	// Reader is used to read the Secret and ConfigMap sources.
+	Reader client.Reader
+	// Namespaces the Secret and ConfigMap sources are allowed to be read from.
+	Namespaces []string
+	// FileRoot is the directory the file sources are confined to, the file sources are disabled if not set.
+	FileRoot string
+	// RootCAs are trusted by the HTTPS and OCI registry sources, the system ones are used if not set.
+	RootCAs *x509.CertPool
+	// Keychain provides the OCI registry credentials, the anonymous access is used if not set.
+	Keychain authn.Keychain
+}
+
+// Open implements Source.
+func (s *Sources) Open(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return s.openHTTP(ctx, rawURL)
+	case "file":
+		return s.openFile(u)
+	case "oci":
+		return s.openOCI(ctx, u)
+	case "secret", "configmap":
+		return s.openObject(ctx, u)
+	default:
+		return nil, fmt.Errorf("unsupported asset URL scheme %q", u.Scheme)
+	}
+}
+
+func (s *Sources) transport() http.RoundTripper {
+	if s.RootCAs == nil {
+		return http.DefaultTransport
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    s.RootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	return transport
+}
+
+func (s *Sources) openHTTP(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := (&http.Client{Transport: s.transport()}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close() //nolint:errcheck
+
+		return nil, fmt.Errorf("failed to download asset: %d", resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+// openFile opens the file referenced by the absolute path under the file root.
+//
+// The path should not escape the root, including via the symlinks.
+func (s *Sources) openFile(u *url.URL) (io.ReadCloser, error) {
+	if s.FileRoot == "" {
+		return nil, errors.New("file sources are not enabled")
+	}
+
+	rel, err := filepath.Rel(filepath.Clean(s.FileRoot), filepath.Clean(u.Path))
+	if err != nil || !filepath.IsAbs(u.Path) || !filepath.IsLocal(rel) {
+		return nil, fmt.Errorf("file %q is outside of the asset file root %q", u.Path, s.FileRoot)
+	}
+
+	root, err := os.OpenRoot(s.FileRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	defer root.Close() //nolint:errcheck
+
+	// the root rejects the symlinks pointing outside of it
+	return root.Open(rel)
+}
+
+// openOCI opens the single layer of the OCI artifact, or the file in the image layers if the URL has the fragment.
+func (s *Sources) openOCI(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	ref, err := name.ParseReference(u.Host + u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	keychain := s.Keychain
+	if keychain == nil {
+		keychain = staticKeychain{}
+	}
+
+	img, err := remote.Image(ref,
+		remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(keychain),
+		remote.WithTransport(s.transport()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching image %q: %w", ref, err)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Fragment == "" {
+		if len(layers) != 1 {
+			return nil, fmt.Errorf("expected a single layer in %q, got %d, the file path should be set", ref, len(layers))
+		}
+
+		return layers[0].Compressed()
+	}
+
+	return extractFile(layers, u.Fragment)
+}
+
+// extractFile opens the file in the image layers, the upper layers take precedence.
+func extractFile(layers []v1.Layer, filePath string) (io.ReadCloser, error) {
+	filePath = path.Clean(strings.TrimPrefix(filePath, "/"))
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		r, err := layers[i].Uncompressed()
+		if err != nil {
+			return nil, err
+		}
+
+		tr := tar.NewReader(r)
+
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				r.Close() //nolint:errcheck
+
+				return nil, err
+			}
+
+			if hdr.Typeflag == tar.TypeReg && path.Clean(strings.TrimPrefix(hdr.Name, "/")) == filePath {
+				return struct {
+					io.Reader
+					io.Closer
+				}{tr, r}, nil
+			}
+		}
+
+		r.Close() //nolint:errcheck
+	}
+
+	return nil, fmt.Errorf("file %q is not found in the image", filePath)
+}
+
+// openObject opens the Secret or ConfigMap key referenced as <scheme>://<namespace>/<name>/<key>.
+func (s *Sources) openObject(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	if s.Reader == nil {
+		return nil, fmt.Errorf("%s sources are not supported", u.Scheme)
+	}
+
+	objectName, key, ok := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if !ok || u.Host == "" || objectName == "" || key == "" {
+		return nil, fmt.Errorf("invalid %s asset URL, expected %s://<namespace>/<name>/<key>", u.Scheme, u.Scheme)
+	}
+
+	if !slices.Contains(s.Namespaces, u.Host) {
+		return nil, fmt.Errorf("%s sources are not allowed in namespace %q", u.Scheme, u.Host)
+	}
+
+	objectKey := types.NamespacedName{Namespace: u.Host, Name: objectName}
+
+	var (
+		data  []byte
+		found bool
+	)
+
+	switch u.Scheme {
+	case "secret":
+		var secret corev1.Secret
+
+		if err := s.Reader.Get(ctx, objectKey, &secret); err != nil {
+			return nil, fmt.Errorf("error getting secret %q: %w", objectKey, err)
+		}
+
+		data, found = secret.Data[key]
+	default:
+		var configMap corev1.ConfigMap
+
+		if err := s.Reader.Get(ctx, objectKey, &configMap); err != nil {
+			return nil, fmt.Errorf("error getting config map %q: %w", objectKey, err)
+		}
+
+		data, found = configMap.BinaryData[key]
+		if !found {
+			var value string
+
+			value, found = configMap.Data[key]
+			data = []byte(value)
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("key %q is missing in %s %q", key, u.Scheme, objectKey)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// dockerConfig is the subset of the Docker config file with the registry credentials.
+type dockerConfig struct {
+	Auths map[string]struct {
+		Username      string `json:"username,omitempty"`
+		Password      string `json:"password,omitempty"`
+		Auth          string `json:"auth,omitempty"`
+		IdentityToken string `json:"identitytoken,omitempty"`
+	} `json:"auths"`
+}
+
+type staticKeychain map[string]authn.AuthConfig
+
+// Resolve implements authn.Keychain.
+func (k staticKeychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	cfg, ok := k[target.RegistryStr()]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+
+	return authn.FromConfig(cfg), nil
+}
+
+// ParseDockerConfig returns the keychain with the registry credentials from the Docker config file.
+func ParseDockerConfig(data []byte) (authn.Keychain, error) {
+	var cfg dockerConfig
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing registry credentials: %w", err)
+	}
+
+	keychain := staticKeychain{}
+
+	for registry, auth := range cfg.Auths {
+		authConfig := authn.AuthConfig{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			IdentityToken: auth.IdentityToken,
+		}
+
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("error decoding credentials of %q: %w", registry, err)
+			}
+
+			authConfig.Username, authConfig.Password, _ = strings.Cut(string(decoded), ":")
+		}
+
+		// registries are keyed as hostnames or URLs
+		if u, err := url.Parse(registry); err == nil && u.Host != "" {
+			registry = u.Host
+		}
+
+		if registry == "docker.io" || registry == "index.docker.io" {
+			registry = name.DefaultRegistry
+		}
+
+		keychain[registry] = authConfig
+	}
+
+	return keychain, nil
+}
@@ -0,0 +1,250 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package assets
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func readSource(t *testing.T, src Source, url string) (string, error) {
+	r, err := src.Open(context.Background(), url)
+	if err != nil {
+		return "", err
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(data), nil
+}
+
+func newTestLayer(t *testing.T, files map[string]string) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	tw := tar.NewWriter(&buf)
+
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+
+	return &buf
+}
+
+func TestSourcesFile(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "assets")
+
+	require.NoError(t, os.Mkdir(root, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "vmlinuz"), []byte("kernel"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secret"), []byte("secret"), 0o644))
+	require.NoError(t, os.Symlink("vmlinuz", filepath.Join(root, "kernel")))
+	require.NoError(t, os.Symlink("../secret", filepath.Join(root, "escape")))
+	require.NoError(t, os.Symlink(filepath.Join(dir, "secret"), filepath.Join(root, "absolute")))
+
+	src := &Sources{FileRoot: root}
+
+	for _, tt := range []struct {
+		url      string
+		expected string
+		err      string
+	}{
+		{url: "file://" + root + "/vmlinuz", expected: "kernel"},
+		{url: "file://" + root + "/kernel", expected: "kernel"},
+		{url: "file://" + root + "/../secret", err: "outside"},
+		{url: "file://" + dir + "/secret", err: "outside"},
+		{url: "file://" + root + "/escape", err: "escapes"},
+		{url: "file://" + root + "/absolute", err: "escapes"},
+		{url: "file:vmlinuz", err: "outside"},
+		{url: "ftp://example.com/vmlinuz", err: "unsupported"},
+	} {
+		t.Run(tt.url, func(t *testing.T) {
+			contents, err := readSource(t, src, tt.url) //nolint:scopelint
+
+			if tt.err != "" { //nolint:scopelint
+				assert.ErrorContains(t, err, tt.err) //nolint:scopelint
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, contents) //nolint:scopelint
+		})
+	}
+
+	_, err := readSource(t, &Sources{}, "file://"+root+"/vmlinuz")
+	assert.ErrorContains(t, err, "not enabled")
+}
+
+func TestSourcesObject(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	src := &Sources{
+		Namespaces: []string{"sidero-system"},
+		Reader: fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "sidero-system", Name: "assets"},
+					Data:       map[string][]byte{"uki.pem": []byte("certificate")},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "sidero-system", Name: "assets"},
+					Data:       map[string]string{"uki.pem": "text"},
+					BinaryData: map[string][]byte{"vmlinuz": []byte("kernel")},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "assets"},
+					Data:       map[string][]byte{"uki.pem": []byte("certificate")},
+				},
+			).
+			Build(),
+	}
+
+	for _, tt := range []struct {
+		url      string
+		expected string
+		err      string
+	}{
+		{url: "secret://sidero-system/assets/uki.pem", expected: "certificate"},
+		{url: "configmap://sidero-system/assets/uki.pem", expected: "text"},
+		{url: "configmap://sidero-system/assets/vmlinuz", expected: "kernel"},
+		{url: "secret://sidero-system/assets/vmlinuz", err: "missing"},
+		{url: "secret://sidero-system/other/uki.pem", err: "not found"},
+		{url: "secret://sidero-system/assets", err: "invalid"},
+		{url: "secret://kube-system/assets/uki.pem", err: "not allowed"},
+		{url: "configmap://kube-system/assets/uki.pem", err: "not allowed"},
+	} {
+		t.Run(tt.url, func(t *testing.T) {
+			contents, err := readSource(t, src, tt.url) //nolint:scopelint
+
+			if tt.err != "" { //nolint:scopelint
+				assert.ErrorContains(t, err, tt.err) //nolint:scopelint
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, contents) //nolint:scopelint
+		})
+	}
+
+	_, err := readSource(t, &Sources{}, "secret://sidero-system/assets/uki.pem")
+	assert.ErrorContains(t, err, "not supported")
+}
+
+func TestSourcesOCI(t *testing.T) {
+	srv := httptest.NewServer(registry.New())
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	// image with the files in the layers, the upper layer overrides the file
+	lower, err := tarball.LayerFromReader(newTestLayer(t, map[string]string{"usr/install/amd64/vmlinuz": "old", "usr/install/amd64/initramfs.xz": "initrd"}))
+	require.NoError(t, err)
+
+	upper, err := tarball.LayerFromReader(newTestLayer(t, map[string]string{"./usr/install/amd64/vmlinuz": "kernel"}))
+	require.NoError(t, err)
+
+	img, err := mutate.AppendLayers(empty.Image, lower, upper)
+	require.NoError(t, err)
+
+	imageRef, err := name.ParseReference(u.Host + "/siderolabs/installer:v1.7.0")
+	require.NoError(t, err)
+
+	require.NoError(t, remote.Write(imageRef, img))
+
+	// artifact with the single layer
+	artifact, err := mutate.AppendLayers(empty.Image, static.NewLayer([]byte("uki"), types.MediaType("application/vnd.sidero.uki")))
+	require.NoError(t, err)
+
+	artifactRef, err := name.ParseReference(u.Host + "/siderolabs/uki:v1.7.0")
+	require.NoError(t, err)
+
+	require.NoError(t, remote.Write(artifactRef, artifact))
+
+	src := &Sources{}
+
+	contents, err := readSource(t, src, "oci://"+u.Host+"/siderolabs/installer:v1.7.0#/usr/install/amd64/vmlinuz")
+	require.NoError(t, err)
+	assert.Equal(t, "kernel", contents)
+
+	contents, err = readSource(t, src, "oci://"+u.Host+"/siderolabs/installer:v1.7.0#usr/install/amd64/initramfs.xz")
+	require.NoError(t, err)
+	assert.Equal(t, "initrd", contents)
+
+	_, err = readSource(t, src, "oci://"+u.Host+"/siderolabs/installer:v1.7.0#usr/install/arm64/vmlinuz")
+	assert.ErrorContains(t, err, "not found")
+
+	_, err = readSource(t, src, "oci://"+u.Host+"/siderolabs/installer:v1.7.0")
+	assert.ErrorContains(t, err, "single layer")
+
+	contents, err = readSource(t, src, "oci://"+u.Host+"/siderolabs/uki:v1.7.0")
+	require.NoError(t, err)
+	assert.Equal(t, "uki", contents)
+}
+
+func TestParseDockerConfig(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("user:secret"))
+
+	keychain, err := ParseDockerConfig([]byte(`{"auths": {"https://registry.example.com/v1/": {"auth": "` + auth + `"}, "docker.io": {"username": "hub", "password": "token"}}}`))
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		image    string
+		expected authn.AuthConfig
+	}{
+		{image: "registry.example.com/siderolabs/installer", expected: authn.AuthConfig{Username: "user", Password: "secret"}},
+		{image: "siderolabs/installer", expected: authn.AuthConfig{Username: "hub", Password: "token"}},
+		{image: "ghcr.io/siderolabs/installer"},
+	} {
+		repo, err := name.NewRepository(tt.image) //nolint:scopelint
+		require.NoError(t, err)
+
+		authenticator, err := keychain.Resolve(repo)
+		require.NoError(t, err)
+
+		cfg, err := authenticator.Authorization()
+		require.NoError(t, err)
+
+		assert.Equal(t, tt.expected, *cfg) //nolint:scopelint
+	}
+
+	_, err = ParseDockerConfig([]byte("not json"))
+	assert.Error(t, err)
+}
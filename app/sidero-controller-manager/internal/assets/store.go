@@ -0,0 +1,397 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package assets implements the content-addressed store of the Environment assets.
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+// ChecksumMismatchError is returned when the downloaded asset doesn't match the SHA512 checksum.
+type ChecksumMismatchError struct {
+	Expected, Actual string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("SHA512 checksum mismatch: expected %s, got %s", e.Expected, e.Actual)
+}
+
+// Store keeps the assets as blobs named by their SHA512 checksum, so the assets with the same contents are stored once.
+//
+// Environments reference the blobs via the hard links in the environment directories,
+// so the blob which has no links other than the one in the store is not referenced, and it is garbage collected.
+// The environment directories should be on the same filesystem as the store.
+type Store struct {
+	dir string
+
+	// mu serializes linking the blobs with the garbage collection.
+	mu sync.Mutex
+}
+
+// NewStore creates the store in the directory.
+func NewStore(dir string) (*Store, error) {
+	s := &Store{
+		dir: dir,
+	}
+
+	for _, d := range []string{s.blobsDir(), s.urlsDir(), s.tmpDir()} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			return nil, fmt.Errorf("error creating asset store directory: %w", err)
+		}
+	}
+
+	// leftovers of the interrupted downloads
+	if err := cleanDir(s.tmpDir()); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateMetrics(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Store) blobsDir() string { return filepath.Join(s.dir, "sha512") }
+func (s *Store) urlsDir() string  { return filepath.Join(s.dir, "urls") }
+func (s *Store) tmpDir() string   { return filepath.Join(s.dir, "tmp") }
+
+// blobPath returns the path of the blob, the digest is validated as it comes from the Environment spec.
+func (s *Store) blobPath(digest string) (string, error) {
+	if !validDigest(digest) {
+		return "", fmt.Errorf("invalid SHA512 digest %q", digest)
+	}
+
+	return filepath.Join(s.blobsDir(), digest), nil
+}
+
+// validDigest checks whether the digest is a lowercase hex-encoded SHA512 checksum.
+func validDigest(digest string) bool {
+	if len(digest) != sha512.Size*2 {
+		return false
+	}
+
+	return strings.IndexFunc(digest, func(r rune) bool {
+		return (r < '0' || r > '9') && (r < 'a' || r > 'f')
+	}) == -1
+}
+
+// urlIndexPath returns the path of the file which holds the digest of the last download of the URL.
+func (s *Store) urlIndexPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return filepath.Join(s.urlsDir(), hex.EncodeToString(sum[:]))
+}
+
+// lookup returns the digest of the cached asset.
+//
+// Assets with the checksum are looked up by the checksum, other remote assets are looked up by the URL.
+// Local assets without the checksum are read again, as they are cheap to read and might change.
+// Invalid digests are never returned.
+func (s *Store) lookup(asset metalv1.Asset) string {
+	if asset.SHA512 != "" {
+		if digest := strings.ToLower(asset.SHA512); validDigest(digest) {
+			return digest
+		}
+
+		return ""
+	}
+
+	for _, scheme := range []string{"file:", "secret:", "configmap:"} {
+		if strings.HasPrefix(asset.URL, scheme) {
+			return ""
+		}
+	}
+
+	digest, err := os.ReadFile(s.urlIndexPath(asset.URL))
+	if err != nil || !validDigest(string(digest)) {
+		return ""
+	}
+
+	return string(digest)
+}
+
+// Install links the asset into the file, downloading it if it is not in the store yet.
+//
+// The file is replaced atomically, the previous copy of the file is kept if the download or the verification fails.
+func (s *Store) Install(ctx context.Context, src Source, asset metalv1.Asset, file string) error {
+	if digest := s.lookup(asset); digest != "" {
+		s.mu.Lock()
+		err := s.link(digest, file)
+		s.mu.Unlock()
+
+		if err == nil {
+			cacheHits.Inc()
+
+			return nil
+		}
+
+		// the blob is missing, download it again
+	}
+
+	cacheMisses.Inc()
+
+	tmp, digest, err := s.download(ctx, src, asset)
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp) //nolint:errcheck
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	blob, err := s.blobPath(digest)
+	if err != nil {
+		return err
+	}
+
+	// keep the existing blob, if the same contents were downloaded from another URL
+	if _, err = os.Stat(blob); errors.Is(err, os.ErrNotExist) {
+		if err = os.Rename(tmp, blob); err != nil {
+			return err
+		}
+	}
+
+	if err = writeFileAtomic(s.urlIndexPath(asset.URL), []byte(digest)); err != nil {
+		return err
+	}
+
+	if err = s.link(digest, file); err != nil {
+		return err
+	}
+
+	return s.updateMetrics()
+}
+
+// Installed checks whether the file is linked to the blob of the asset in the store.
+//
+// The assets which are read again on every install (see lookup) are never reported as installed.
+func (s *Store) Installed(asset metalv1.Asset, file string) bool {
+	digest := s.lookup(asset)
+	if digest == "" {
+		return false
+	}
+
+	blob, err := s.blobPath(digest)
+	if err != nil {
+		return false
+	}
+
+	blobInfo, err := os.Stat(blob)
+	if err != nil {
+		return false
+	}
+
+	fileInfo, err := os.Stat(file)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(blobInfo, fileInfo)
+}
+
+// link atomically replaces the file with the hard link to the blob.
+func (s *Store) link(digest, file string) error {
+	blob, err := s.blobPath(digest)
+	if err != nil {
+		return err
+	}
+
+	tmp := fmt.Sprintf("%s.%d.tmp", file, time.Now().UnixNano())
+
+	if err := os.Link(blob, tmp); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp) //nolint:errcheck
+
+		return err
+	}
+
+	return nil
+}
+
+// download reads the asset from the source to a temporary file and verifies its SHA512 checksum (if set).
+func (s *Store) download(ctx context.Context, src Source, asset metalv1.Asset) (file, digest string, err error) {
+	if asset.URL == "" {
+		return "", "", errors.New("missing URL")
+	}
+
+	requestContext, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	r, err := src.Open(requestContext, asset.URL)
+	if err != nil {
+		return "", "", err
+	}
+
+	defer r.Close() //nolint:errcheck
+
+	w, err := os.CreateTemp(s.tmpDir(), "download-*")
+	if err != nil {
+		return "", "", err
+	}
+
+	committed := false
+
+	defer func() {
+		w.Close() //nolint:errcheck
+
+		if !committed {
+			os.Remove(w.Name()) //nolint:errcheck
+		}
+	}()
+
+	hash := sha512.New()
+
+	if _, err = io.Copy(io.MultiWriter(w, hash), r); err != nil {
+		return "", "", err
+	}
+
+	digest = hex.EncodeToString(hash.Sum(nil))
+
+	if asset.SHA512 != "" && !strings.EqualFold(digest, asset.SHA512) {
+		return "", "", &ChecksumMismatchError{Expected: asset.SHA512, Actual: digest}
+	}
+
+	if err = w.Sync(); err != nil {
+		return "", "", err
+	}
+
+	if err = w.Close(); err != nil {
+		return "", "", err
+	}
+
+	// blobs are shared via the hard links, so they should never be modified in place
+	if err = os.Chmod(w.Name(), 0o444); err != nil {
+		return "", "", err
+	}
+
+	committed = true
+
+	return w.Name(), digest, nil
+}
+
+// GC removes the blobs which are not linked into any environment directory, and the stale URL index entries.
+func (s *Store) GC() (collected int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.blobsDir())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return collected, err
+		}
+
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Nlink > 1 {
+			continue
+		}
+
+		if err = os.Remove(filepath.Join(s.blobsDir(), entry.Name())); err != nil {
+			return collected, err
+		}
+
+		collected++
+	}
+
+	gcCollected.Add(float64(collected))
+
+	urls, err := os.ReadDir(s.urlsDir())
+	if err != nil {
+		return collected, err
+	}
+
+	for _, entry := range urls {
+		path := filepath.Join(s.urlsDir(), entry.Name())
+
+		digest, err := os.ReadFile(path)
+		if err != nil {
+			return collected, err
+		}
+
+		// the index entries with the invalid digests are stale as well
+		blob, pathErr := s.blobPath(string(digest))
+		if pathErr == nil {
+			_, err = os.Stat(blob)
+		}
+
+		if pathErr != nil || errors.Is(err, os.ErrNotExist) {
+			if err = os.Remove(path); err != nil {
+				return collected, err
+			}
+		}
+	}
+
+	return collected, s.updateMetrics()
+}
+
+func (s *Store) updateMetrics() error {
+	entries, err := os.ReadDir(s.blobsDir())
+	if err != nil {
+		return err
+	}
+
+	var size int64
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		size += info.Size()
+	}
+
+	cacheBlobs.Set(float64(len(entries)))
+	cacheSize.Set(float64(size))
+
+	return nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := fmt.Sprintf("%s.%d.tmp", path, time.Now().UnixNano())
+
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func cleanDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err = os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
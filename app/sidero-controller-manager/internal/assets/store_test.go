@@ -0,0 +1,163 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package assets
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func newTestServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		switch r.URL.Path {
+		case "/vmlinuz", "/mirror/vmlinuz":
+			w.Write([]byte("kernel")) //nolint:errcheck
+		case "/initramfs.xz":
+			w.Write([]byte("initrd")) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestInstall(t *testing.T) {
+	ctx := context.Background()
+
+	var requests atomic.Int32
+
+	srv := newTestServer(t, &requests)
+
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	sum := sha512.Sum512([]byte("kernel"))
+	checksum := hex.EncodeToString(sum[:])
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "vmlinuz")
+
+	require.NoError(t, os.WriteFile(file, []byte("previous"), 0o644))
+
+	assertContents := func(expected string) {
+		contents, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, string(contents))
+
+		// temporary files are cleaned up
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	}
+
+	// the previous copy is kept on failures
+	err = store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/vmlinuz", SHA512: checksum[:len(checksum)-1] + "0"}, file)
+
+	var checksumErr *ChecksumMismatchError
+
+	require.ErrorAs(t, err, &checksumErr)
+	assertContents("previous")
+
+	require.Error(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/missing"}, file))
+	assertContents("previous")
+
+	assert.False(t, store.Installed(metalv1.Asset{URL: srv.URL + "/vmlinuz", SHA512: checksum}, file))
+
+	require.NoError(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/vmlinuz", SHA512: checksum}, file))
+	assertContents("kernel")
+
+	assert.True(t, store.Installed(metalv1.Asset{URL: srv.URL + "/vmlinuz", SHA512: checksum}, file))
+	assert.True(t, store.Installed(metalv1.Asset{URL: srv.URL + "/vmlinuz"}, file))
+	assert.False(t, store.Installed(metalv1.Asset{URL: srv.URL + "/vmlinuz", SHA512: checksum[:len(checksum)-1] + "0"}, file))
+	assert.False(t, store.Installed(metalv1.Asset{URL: "file://" + file}, file))
+
+	// the checksum is used as the blob name, so the paths are not accepted
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0o644))
+
+	traversal := metalv1.Asset{URL: srv.URL + "/vmlinuz", SHA512: "../../../../../../../.." + secret}
+
+	assert.False(t, store.Installed(traversal, secret))
+	require.ErrorAs(t, store.Install(ctx, &Sources{}, traversal, file), &checksumErr)
+	assertContents("kernel")
+
+	requests.Store(0)
+
+	// the same asset is linked from the store without downloading it again
+	other := filepath.Join(t.TempDir(), "vmlinuz")
+
+	require.NoError(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/vmlinuz", SHA512: checksum}, other))
+	require.NoError(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/vmlinuz"}, other))
+	assert.EqualValues(t, 0, requests.Load())
+
+	// the same contents from the other URL are stored once
+	require.NoError(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/mirror/vmlinuz"}, other))
+	assert.EqualValues(t, 1, requests.Load())
+
+	blobs, err := os.ReadDir(store.blobsDir())
+	require.NoError(t, err)
+	assert.Len(t, blobs, 1)
+
+	fileInfo, err := os.Stat(file)
+	require.NoError(t, err)
+
+	otherInfo, err := os.Stat(other)
+	require.NoError(t, err)
+
+	assert.True(t, os.SameFile(fileInfo, otherInfo))
+}
+
+func TestGC(t *testing.T) {
+	ctx := context.Background()
+
+	var requests atomic.Int32
+
+	srv := newTestServer(t, &requests)
+
+	store, err := NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+
+	require.NoError(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/vmlinuz"}, filepath.Join(dir, "vmlinuz")))
+	require.NoError(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/initramfs.xz"}, filepath.Join(dir, "initramfs.xz")))
+
+	collected, err := store.GC()
+	require.NoError(t, err)
+	assert.Equal(t, 0, collected)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "initramfs.xz")))
+
+	collected, err = store.GC()
+	require.NoError(t, err)
+	assert.Equal(t, 1, collected)
+
+	urls, err := os.ReadDir(store.urlsDir())
+	require.NoError(t, err)
+	assert.Len(t, urls, 1)
+
+	// the collected asset is downloaded again
+	requests.Store(0)
+
+	require.NoError(t, store.Install(ctx, &Sources{}, metalv1.Asset{URL: srv.URL + "/initramfs.xz"}, filepath.Join(dir, "initramfs.xz")))
+	assert.EqualValues(t, 1, requests.Load())
+}
@@ -0,0 +1,179 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package configtoken implements the one-time tokens the servers use to fetch the machine configuration.
+//
+// The token is issued on the iPXE boot of the allocated server and it is passed via the talos.config kernel argument.
+// Only the hash of the token is stored in the ServerBinding status, along with the address of the client it was issued to,
+// and the token is accepted only from that address.
+//
+// The token is issued once per allocation (the ServerBinding) and once per boot initiated by Sidero (see Reset),
+// so that the clients which only know the server UUID can't get another token once the server has fetched its configuration.
+package configtoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
+)
+
+// TTL is the lifetime of the token.
+const TTL = time.Hour
+
+// QueryParam is the name of the talos.config URL query parameter with the token.
+const QueryParam = "token"
+
+// Token validation errors.
+var (
+	ErrMissing     = errors.New("config token is missing")
+	ErrNotIssued   = errors.New("config token was not issued")
+	ErrInvalid     = errors.New("config token is invalid")
+	ErrExpired     = errors.New("config token is expired")
+	ErrAlreadyUsed = errors.New("config token was already used")
+	ErrAddress     = errors.New("config token was issued to another address")
+)
+
+// ErrAlreadyIssued is returned when the token was already issued for the current boot of the server.
+var ErrAlreadyIssued = errors.New("config token was already issued for the current boot")
+
+// ClientAddress returns the address of the HTTP client, or an empty string if it's not known.
+func ClientAddress(r *http.Request) string {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return ""
+	}
+
+	return addrPort.Addr().Unmap().String()
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue generates the token bound to the client address and records it in the ServerBinding status.
+//
+// The token is issued only if no token was issued for the current boot yet.
+// The unused token which is not expired yet is replaced if it was issued to the same address,
+// as the client retries the iPXE request if the boot fails, and the client already holds the valid token anyway.
+func Issue(ctx context.Context, c client.Client, serverBinding *infrav1.ServerBinding, address string) (string, error) {
+	if address == "" {
+		return "", errors.New("config token client address is not known")
+	}
+
+	if previous := serverBinding.Status.ConfigToken; previous != nil &&
+		(previous.UsedAt != nil || time.Now().After(previous.ExpiresAt.Time) || previous.Address != address) {
+		return "", ErrAlreadyIssued
+	}
+
+	var buf [32]byte
+
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf[:])
+
+	patch := client.MergeFrom(serverBinding.DeepCopy())
+	now := metav1.Now()
+
+	serverBinding.Status.ConfigToken = &infrav1.ConfigToken{
+		Hash:      hash(token),
+		Address:   address,
+		IssuedAt:  now,
+		ExpiresAt: metav1.NewTime(now.Add(TTL)),
+	}
+
+	if err := c.Status().Patch(ctx, serverBinding, patch); err != nil {
+		return "", fmt.Errorf("error recording config token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Reset removes the token of the previous boot, so that the new token can be issued on the next boot.
+//
+// It should be called when Sidero boots the allocated server.
+func Reset(ctx context.Context, c client.Client, serverBinding *infrav1.ServerBinding) error {
+	if serverBinding.Status.ConfigToken == nil {
+		return nil
+	}
+
+	patch := client.MergeFrom(serverBinding.DeepCopy())
+
+	serverBinding.Status.ConfigToken = nil
+
+	if err := c.Status().Patch(ctx, serverBinding, patch); err != nil {
+		return fmt.Errorf("error resetting config token: %w", err)
+	}
+
+	return nil
+}
+
+// Verify checks the token sent from the client address against the ServerBinding status without using it.
+func Verify(serverBinding *infrav1.ServerBinding, token, address string) error {
+	return validate(serverBinding.Status.ConfigToken, token, address)
+}
+
+// Use verifies the token sent from the client address and marks it as used, so that it can't be replayed.
+func Use(ctx context.Context, c client.Client, serverBinding *infrav1.ServerBinding, token, address string) error {
+	attempt := 0
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// the conflict might be caused by another update of the status, so the token is validated again
+		if attempt > 0 {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(serverBinding), serverBinding); err != nil {
+				return err
+			}
+		}
+
+		attempt++
+
+		if err := validate(serverBinding.Status.ConfigToken, token, address); err != nil {
+			return err
+		}
+
+		// the optimistic lock makes sure the token is used once, even if the requests race
+		patch := client.MergeFromWithOptions(serverBinding.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		now := metav1.Now()
+
+		serverBinding.Status.ConfigToken.UsedAt = &now
+
+		return c.Status().Patch(ctx, serverBinding, patch)
+	})
+}
+
+func validate(configToken *infrav1.ConfigToken, token, address string) error {
+	switch {
+	case token == "":
+		return ErrMissing
+	case configToken == nil:
+		return ErrNotIssued
+	case subtle.ConstantTimeCompare([]byte(hash(token)), []byte(configToken.Hash)) != 1:
+		return ErrInvalid
+	case address == "" || configToken.Address != address:
+		return ErrAddress
+	case configToken.UsedAt != nil:
+		return ErrAlreadyUsed
+	case time.Now().After(configToken.ExpiresAt.Time):
+		return ErrExpired
+	default:
+		return nil
+	}
+}
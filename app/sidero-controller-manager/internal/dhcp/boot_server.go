@@ -0,0 +1,203 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dhcp
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+)
+
+// BootEndpoint maps the subnet of the clients to the boot server address advertised to them.
+type BootEndpoint struct {
+	Subnet   *net.IPNet
+	Endpoint net.IP
+}
+
+// ParseBootEndpoints parses the subnet to boot endpoint mapping in the `<subnet>=<endpoint>` format,
+// e.g. `10.0.1.0/24=10.0.1.5`.
+func ParseBootEndpoints(mappings []string) ([]BootEndpoint, error) {
+	endpoints := make([]BootEndpoint, 0, len(mappings))
+
+	for _, mapping := range mappings {
+		if mapping == "" {
+			continue
+		}
+
+		subnet, endpoint, ok := strings.Cut(mapping, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid boot endpoint mapping %q, expected <subnet>=<endpoint>", mapping)
+		}
+
+		_, network, err := net.ParseCIDR(subnet)
+		if err != nil || network.IP.To4() == nil {
+			return nil, fmt.Errorf("invalid subnet in boot endpoint mapping %q: should be IPv4 CIDR", mapping)
+		}
+
+		ip := net.ParseIP(endpoint)
+		if ip == nil || ip.To4() == nil {
+			return nil, fmt.Errorf("invalid endpoint in boot endpoint mapping %q: should be IPv4 address", mapping)
+		}
+
+		endpoints = append(endpoints, BootEndpoint{
+			Subnet:   network,
+			Endpoint: ip.To4(),
+		})
+	}
+
+	return endpoints, nil
+}
+
+// bootServerSelector picks the boot server address advertised to the clients for the interface the request was received on.
+type bootServerSelector struct {
+	// defaultIP is the API endpoint address.
+	defaultIP net.IP
+	// ifaceAddrs are the IPv4 addresses of the receiving interface.
+	ifaceAddrs []*net.IPNet
+	endpoints  []BootEndpoint
+}
+
+// serverIP returns the boot server address for the request.
+//
+// For relayed requests the client subnet is looked up by the relay agent link selection sub-option (option 82),
+// the subnet selection option (option 118) or the relay agent address (giaddr), in that order.
+// The subnet to boot endpoint mapping is preferred, then the address of the receiving interface on the client subnet.
+func (s *bootServerSelector) serverIP(req *dhcpv4.DHCPv4) net.IP {
+	if clientAddr := clientSubnetAddr(req); clientAddr != nil {
+		if endpoint := s.mappedEndpoint(clientAddr); endpoint != nil {
+			return endpoint
+		}
+
+		for _, addr := range s.ifaceAddrs {
+			if addr.Contains(clientAddr) {
+				return addr.IP
+			}
+		}
+
+		return s.defaultIP
+	}
+
+	for _, addr := range s.ifaceAddrs {
+		if endpoint := s.mappedEndpoint(addr.IP); endpoint != nil {
+			return endpoint
+		}
+	}
+
+	for _, addr := range s.ifaceAddrs {
+		if addr.IP.Equal(s.defaultIP) {
+			return s.defaultIP
+		}
+	}
+
+	if len(s.ifaceAddrs) > 0 {
+		return s.ifaceAddrs[0].IP
+	}
+
+	return s.defaultIP
+}
+
+func (s *bootServerSelector) mappedEndpoint(addr net.IP) net.IP {
+	for _, endpoint := range s.endpoints {
+		if endpoint.Subnet.Contains(addr) {
+			return endpoint.Endpoint
+		}
+	}
+
+	return nil
+}
+
+// clientSubnetAddr returns the address on the client subnet for the relayed requests.
+func clientSubnetAddr(req *dhcpv4.DHCPv4) net.IP {
+	if relayInfo := req.RelayAgentInfo(); relayInfo != nil {
+		if linkSelection := relayInfo.Get(dhcpv4.LinkSelectionSubOption); len(linkSelection) == net.IPv4len {
+			return net.IP(linkSelection)
+		}
+	}
+
+	if subnetSelection := req.GetOneOption(dhcpv4.OptionSubnetSelection); len(subnetSelection) == net.IPv4len {
+		return net.IP(subnetSelection)
+	}
+
+	if !req.GatewayIPAddr.IsUnspecified() {
+		return req.GatewayIPAddr
+	}
+
+	return nil
+}
+
+// relayAgentInfo returns the relay agent circuit and remote IDs (option 82) as log key-value pairs.
+func relayAgentInfo(req *dhcpv4.DHCPv4) []any {
+	relayInfo := req.RelayAgentInfo()
+	if relayInfo == nil {
+		return nil
+	}
+
+	var kv []any
+
+	if circuitID := relayInfo.Get(dhcpv4.AgentCircuitIDSubOption); circuitID != nil {
+		kv = append(kv, "circuit_id", string(circuitID))
+	}
+
+	if remoteID := relayInfo.Get(dhcpv4.AgentRemoteIDSubOption); remoteID != nil {
+		kv = append(kv, "remote_id", string(remoteID))
+	}
+
+	return kv
+}
+
+// interfaceAddrs returns the IPv4 addresses of the interface.
+func interfaceAddrs(name string) ([]*net.IPNet, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*net.IPNet
+
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.To4() != nil {
+			result = append(result, &net.IPNet{IP: ipNet.IP.To4(), Mask: ipNet.Mask})
+		}
+	}
+
+	return result, nil
+}
+
+// findEndpointInterfaces returns the interfaces which have addresses on the mapped subnets.
+func findEndpointInterfaces(endpoints []BootEndpoint) ([]string, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get network interfaces: %w", err)
+	}
+
+	var result []string
+
+	for _, iface := range interfaces {
+		addrs, err := interfaceAddrs(iface.Name)
+		if err != nil {
+			continue
+		}
+
+	addrLoop:
+		for _, addr := range addrs {
+			for _, endpoint := range endpoints {
+				if endpoint.Subnet.Contains(addr.IP) {
+					result = append(result, iface.Name)
+
+					break addrLoop
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
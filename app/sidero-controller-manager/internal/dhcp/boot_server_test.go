@@ -0,0 +1,118 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package dhcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+func TestBootServerSelector(t *testing.T) {
+	endpoints, err := ParseBootEndpoints([]string{"10.0.1.0/24=10.0.1.5", "10.0.2.0/24=10.0.2.5"})
+	require.NoError(t, err)
+
+	ipNet := func(cidr string) *net.IPNet {
+		ip, network, err := net.ParseCIDR(cidr)
+		require.NoError(t, err)
+
+		network.IP = ip.To4()
+
+		return network
+	}
+
+	relayInfo := func(linkSelection string) dhcpv4.Modifier {
+		return dhcpv4.WithOption(dhcpv4.OptRelayAgentInfo(
+			dhcpv4.OptGeneric(dhcpv4.AgentCircuitIDSubOption, []byte("eth1")),
+			dhcpv4.OptGeneric(dhcpv4.LinkSelectionSubOption, net.ParseIP(linkSelection).To4()),
+		))
+	}
+
+	for _, tt := range []struct {
+		name       string
+		ifaceAddrs []*net.IPNet
+		modifiers  []dhcpv4.Modifier
+		expected   string
+	}{
+		{
+			name:       "default interface",
+			ifaceAddrs: []*net.IPNet{ipNet("172.20.0.2/24")},
+			expected:   "172.20.0.2",
+		},
+		{
+			name:       "mapped interface",
+			ifaceAddrs: []*net.IPNet{ipNet("10.0.1.2/24")},
+			expected:   "10.0.1.5",
+		},
+		{
+			name:       "other interface",
+			ifaceAddrs: []*net.IPNet{ipNet("192.168.0.2/24")},
+			expected:   "192.168.0.2",
+		},
+		{
+			name:       "relayed from mapped subnet",
+			ifaceAddrs: []*net.IPNet{ipNet("172.20.0.2/24")},
+			modifiers:  []dhcpv4.Modifier{dhcpv4.WithGatewayIP(net.ParseIP("10.0.2.1"))},
+			expected:   "10.0.2.5",
+		},
+		{
+			name:       "relayed with link selection",
+			ifaceAddrs: []*net.IPNet{ipNet("172.20.0.2/24")},
+			modifiers:  []dhcpv4.Modifier{dhcpv4.WithGatewayIP(net.ParseIP("172.20.0.1")), relayInfo("10.0.1.1")},
+			expected:   "10.0.1.5",
+		},
+		{
+			name:       "relayed from unknown subnet",
+			ifaceAddrs: []*net.IPNet{ipNet("192.168.0.2/24")},
+			modifiers:  []dhcpv4.Modifier{dhcpv4.WithGatewayIP(net.ParseIP("10.0.3.1"))},
+			expected:   "172.20.0.2",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			selector := &bootServerSelector{
+				defaultIP:  net.ParseIP("172.20.0.2"),
+				ifaceAddrs: tt.ifaceAddrs,
+				endpoints:  endpoints,
+			}
+
+			req, err := dhcpv4.NewDiscovery(net.HardwareAddr{0xaa, 0xbb, 0xcc, 0, 0, 1}, tt.modifiers...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, selector.serverIP(req).String())
+		})
+	}
+}
+
+func TestOfferDHCPRelayed(t *testing.T) {
+	req, err := dhcpv4.NewDiscovery(net.HardwareAddr{0xaa, 0xbb, 0xcc, 0, 0, 1},
+		dhcpv4.WithGatewayIP(net.ParseIP("10.0.1.1")),
+		dhcpv4.WithOption(dhcpv4.OptRelayAgentInfo(dhcpv4.OptGeneric(dhcpv4.AgentCircuitIDSubOption, []byte("eth1")))),
+	)
+	require.NoError(t, err)
+
+	resp, err := offerDHCP(req, net.ParseIP("10.0.1.5"), 8081, siderotypes.HTTPBootIPXE, FirmwareX86HTTP)
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://10.0.1.5:8081/tftp/snp.efi", resp.BootFileNameOption())
+	assert.Equal(t, "10.0.1.1", resp.GatewayIPAddr.String())
+	assert.Equal(t, []any{"circuit_id", "eth1"}, relayAgentInfo(resp), "relay agent information is echoed back")
+}
+
+func TestParseBootEndpoints(t *testing.T) {
+	endpoints, err := ParseBootEndpoints([]string{""})
+	require.NoError(t, err)
+	assert.Empty(t, endpoints)
+
+	for _, mapping := range []string{"10.0.1.0/24", "10.0.1.0=10.0.1.5", "10.0.1.0/24=host", "fd00::/64=fd00::1"} {
+		_, err = ParseBootEndpoints([]string{mapping})
+		assert.Error(t, err, mapping)
+	}
+}
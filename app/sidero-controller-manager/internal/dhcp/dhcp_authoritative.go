@@ -0,0 +1,338 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dhcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/dhcpv4/server4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+// Mode of the DHCP server.
+type Mode string
+
+// DHCP server modes.
+const (
+	// ModeProxy answers only the PXE boot requests with the boot options, addresses are leased by another DHCP server.
+	ModeProxy Mode = "proxy"
+	// ModeAuthoritative leases the addresses from the DHCPPools and answers the PXE boot requests with the boot options.
+	ModeAuthoritative Mode = "authoritative"
+)
+
+// AuthoritativeServer leases the addresses from the DHCPPools.
+//
+// Leases are stored in the DHCPPool status, so they survive restarts.
+type AuthoritativeServer struct {
+	// Client is used to update the leases.
+	Client client.Client
+	// Reader is used to read the pools and the servers bypassing the cache, as leases are updated concurrently.
+	Reader client.Reader
+	Logger logr.Logger
+
+	ServerIP     net.IP
+	APIPort      int
+	HTTPBootMode siderotypes.HTTPBootMode
+}
+
+// ServeAuthoritativeDHCP starts the authoritative DHCP server.
+func ServeAuthoritativeDHCP(ctx context.Context, logger logr.Logger, c client.Client, reader client.Reader, apiEndpoint string, apiPort int, httpBootMode siderotypes.HTTPBootMode) error {
+	serverIP, iface, err := resolveServerInterface(apiEndpoint, false)
+	if err != nil {
+		return err
+	}
+
+	s := &AuthoritativeServer{
+		Client:       c,
+		Reader:       reader,
+		Logger:       logger,
+		ServerIP:     serverIP,
+		APIPort:      apiPort,
+		HTTPBootMode: httpBootMode,
+	}
+
+	server, err := server4.NewServer(iface, nil, s.handlePacket(ctx))
+	if err != nil {
+		logger.Error(err, "error on DHCP4 server startup")
+
+		return err
+	}
+
+	return server.Serve()
+}
+
+func (s *AuthoritativeServer) handlePacket(ctx context.Context) server4.Handler {
+	return func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+		resp, err := s.Respond(ctx, m, time.Now())
+		if err != nil {
+			s.Logger.Error(err, "failed to handle DHCP packet", "source", m.ClientHWAddr, "type", m.MessageType())
+
+			return
+		}
+
+		if resp == nil {
+			return
+		}
+
+		s.Logger.Info("sending DHCP reply", "source", m.ClientHWAddr, "type", resp.MessageType(), "address", resp.YourIPAddr, "boot_filename", resp.BootFileNameOption())
+
+		if _, err = conn.WriteTo(resp.ToBytes(), replyAddr(m, resp)); err != nil {
+			s.Logger.Error(err, "failure sending response", "source", m.ClientHWAddr)
+		}
+	}
+}
+
+// replyAddr returns the destination of the reply as described in RFC 2131, section 4.1.
+func replyAddr(req, resp *dhcpv4.DHCPv4) net.Addr {
+	switch {
+	case !req.GatewayIPAddr.IsUnspecified():
+		return &net.UDPAddr{IP: req.GatewayIPAddr, Port: dhcpv4.ServerPort}
+	case resp.MessageType() == dhcpv4.MessageTypeNak:
+		return &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpv4.ClientPort}
+	case !req.ClientIPAddr.IsUnspecified():
+		return &net.UDPAddr{IP: req.ClientIPAddr, Port: dhcpv4.ClientPort}
+	default:
+		return &net.UDPAddr{IP: net.IPv4bcast, Port: dhcpv4.ClientPort}
+	}
+}
+
+// Respond handles the DHCP request, nil reply means that the request should be ignored.
+func (s *AuthoritativeServer) Respond(ctx context.Context, req *dhcpv4.DHCPv4, now time.Time) (*dhcpv4.DHCPv4, error) {
+	if req.OpCode != dhcpv4.OpcodeBootRequest {
+		return nil, nil
+	}
+
+	switch req.MessageType() { //nolint:exhaustive
+	case dhcpv4.MessageTypeDiscover:
+		return s.offer(ctx, req, now)
+	case dhcpv4.MessageTypeRequest:
+		return s.ack(ctx, req, now)
+	case dhcpv4.MessageTypeRelease:
+		return nil, s.release(ctx, req)
+	case dhcpv4.MessageTypeDecline:
+		s.Logger.Info("client declined the address", "source", req.ClientHWAddr, "address", req.RequestedIPAddress())
+
+		return nil, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (s *AuthoritativeServer) offer(ctx context.Context, req *dhcpv4.DHCPv4, now time.Time) (*dhcpv4.DHCPv4, error) {
+	pool, err := s.findPool(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	ip, err := pool.Allocate(req.ClientHWAddr.String(), req.RequestedIPAddress(), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate address from pool %q: %w", pool.Name, err)
+	}
+
+	return s.reply(req, pool, ip, dhcpv4.MessageTypeOffer)
+}
+
+func (s *AuthoritativeServer) ack(ctx context.Context, req *dhcpv4.DHCPv4, now time.Time) (*dhcpv4.DHCPv4, error) {
+	if serverID := req.ServerIdentifier(); serverID != nil && !serverID.Equal(s.ServerIP) {
+		// client selected another server
+		return nil, nil
+	}
+
+	requested := req.RequestedIPAddress()
+	if requested == nil || requested.IsUnspecified() {
+		requested = req.ClientIPAddr
+	}
+
+	mac := req.ClientHWAddr.String()
+
+	serverName, err := s.findServer(ctx, mac)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool *metalv1.DHCPPool
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var findErr error
+
+		pool, findErr = s.findPool(ctx, req)
+		if findErr != nil {
+			return findErr
+		}
+
+		ip, allocateErr := pool.Allocate(mac, requested, now)
+		if allocateErr != nil && !errors.Is(allocateErr, metalv1.ErrDHCPPoolExhausted) {
+			return allocateErr
+		}
+
+		if !ip.Equal(requested) {
+			pool = nil
+
+			return nil
+		}
+
+		pool.SetLease(metalv1.DHCPLease{
+			MAC:       mac,
+			IP:        ip.String(),
+			Hostname:  req.HostName(),
+			Server:    serverName,
+			ExpiresAt: metav1.NewTime(now.Add(pool.LeaseTime())),
+		})
+
+		return s.Client.Status().Update(ctx, pool)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lease address %s: %w", requested, err)
+	}
+
+	if pool == nil {
+		s.Logger.Info("requested address is not available", "source", req.ClientHWAddr, "address", requested)
+
+		return dhcpv4.NewReplyFromRequest(req,
+			dhcpv4.WithMessageType(dhcpv4.MessageTypeNak),
+			dhcpv4.WithOption(dhcpv4.OptServerIdentifier(s.ServerIP)),
+		)
+	}
+
+	return s.reply(req, pool, requested, dhcpv4.MessageTypeAck)
+}
+
+func (s *AuthoritativeServer) release(ctx context.Context, req *dhcpv4.DHCPv4) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pool, err := s.findPool(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if !pool.Release(req.ClientHWAddr.String()) {
+			return nil
+		}
+
+		return s.Client.Status().Update(ctx, pool)
+	})
+}
+
+func (s *AuthoritativeServer) reply(req *dhcpv4.DHCPv4, pool *metalv1.DHCPPool, ip net.IP, messageType dhcpv4.MessageType) (*dhcpv4.DHCPv4, error) {
+	network, err := pool.Network()
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet in pool %q: %w", pool.Name, err)
+	}
+
+	modifiers := []dhcpv4.Modifier{
+		dhcpv4.WithMessageType(messageType),
+		dhcpv4.WithYourIP(ip),
+		dhcpv4.WithNetmask(network.Mask),
+		dhcpv4.WithLeaseTime(uint32(pool.LeaseTime().Seconds())),
+		dhcpv4.WithOption(dhcpv4.OptServerIdentifier(s.ServerIP)),
+	}
+
+	if gateway := net.ParseIP(pool.Spec.Gateway); gateway != nil {
+		modifiers = append(modifiers, dhcpv4.WithRouter(gateway))
+	}
+
+	if len(pool.Spec.DNSServers) > 0 {
+		var dnsServers []net.IP
+
+		for _, dns := range pool.Spec.DNSServers {
+			if ip := net.ParseIP(dns); ip != nil {
+				dnsServers = append(dnsServers, ip)
+			}
+		}
+
+		modifiers = append(modifiers, dhcpv4.WithDNS(dnsServers...))
+	}
+
+	resp, err := dhcpv4.NewReplyFromRequest(req, modifiers...)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Options[93] == nil {
+		// not a PXE boot request
+		return resp, nil
+	}
+
+	fwtype, err := validateDHCP(req)
+	if err != nil {
+		s.Logger.Info("not sending boot options", "source", req.ClientHWAddr, "reason", err)
+
+		return resp, nil
+	}
+
+	if err = addBootOptions(req, resp, s.ServerIP, s.APIPort, s.HTTPBootMode, fwtype); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// findPool returns the pool for the subnet of the relay agent or of the server itself.
+func (s *AuthoritativeServer) findPool(ctx context.Context, req *dhcpv4.DHCPv4) (*metalv1.DHCPPool, error) {
+	target := s.ServerIP
+	if !req.GatewayIPAddr.IsUnspecified() {
+		target = req.GatewayIPAddr
+	}
+
+	var pools metalv1.DHCPPoolList
+
+	if err := s.Reader.List(ctx, &pools); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(pools.Items, func(a, b metalv1.DHCPPool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for i := range pools.Items {
+		if pools.Items[i].Contains(target) {
+			return &pools.Items[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("no DHCP pool found for the subnet of %s", target)
+}
+
+// findServer returns the name of the Server which has a network interface with the MAC address.
+func (s *AuthoritativeServer) findServer(ctx context.Context, mac string) (string, error) {
+	var servers metalv1.ServerList
+
+	if err := s.Reader.List(ctx, &servers); err != nil {
+		return "", err
+	}
+
+	return ServerMACs(servers.Items)[mac], nil
+}
+
+// ServerMACs maps lowercase MAC addresses of the Servers network interfaces to the names of the Servers.
+func ServerMACs(servers []metalv1.Server) map[string]string {
+	macs := map[string]string{}
+
+	for _, server := range servers {
+		if server.Spec.Hardware == nil || server.Spec.Hardware.Network == nil {
+			continue
+		}
+
+		for _, iface := range server.Spec.Hardware.Network.Interfaces {
+			if iface != nil && iface.MAC != "" {
+				macs[strings.ToLower(iface.MAC)] = server.Name
+			}
+		}
+	}
+
+	return macs
+}
@@ -9,17 +9,24 @@ import (
 	"fmt"
 	"log"
 	"net"
-	"strconv"
+	"slices"
 
 	"github.com/go-logr/logr"
 	"github.com/insomniacslk/dhcp/dhcpv4"
 	"github.com/insomniacslk/dhcp/dhcpv4/server4"
 	"github.com/insomniacslk/dhcp/iana"
 	"github.com/siderolabs/gen/xslices"
+	"golang.org/x/sync/errgroup"
+
+	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
 )
 
 // ServeDHCP starts the DHCP proxy server.
-func ServeDHCP(logger logr.Logger, apiEndpoint string, apiPort int) error {
+//
+// DHCPv4 and DHCPv6 proxies are started for the IPv4 and IPv6 addresses of the API endpoint.
+// DHCPv4 proxy is also started on the interfaces with the addresses on the subnets of the boot endpoints,
+// the advertised boot server address is picked for each request by the receiving interface and the relay agent information.
+func ServeDHCP(logger logr.Logger, apiEndpoint string, apiPort int, httpBootMode siderotypes.HTTPBootMode, bootEndpoints []BootEndpoint) error {
 	serverIPs, err := net.LookupIP(apiEndpoint)
 	if err != nil {
 		return err
@@ -29,23 +36,108 @@ func ServeDHCP(logger logr.Logger, apiEndpoint string, apiPort int) error {
 		return fmt.Errorf("no IPs found for %s", apiEndpoint)
 	}
 
-	iface, err := findMatchingInterface(serverIPs[0])
-	if err != nil {
-		return err
+	var (
+		eg            errgroup.Group
+		dhcpv4Started bool
+	)
+
+	if serverIP := pickServerIP(serverIPs, false); serverIP != nil {
+		iface, err := findMatchingInterface(serverIP)
+		if err != nil {
+			return err
+		}
+
+		endpointIfaces, err := findEndpointInterfaces(bootEndpoints)
+		if err != nil {
+			return err
+		}
+
+		for _, iface := range slices.Compact(slices.Sorted(slices.Values(append(endpointIfaces, iface)))) {
+			ifaceAddrs, err := interfaceAddrs(iface)
+			if err != nil {
+				return err
+			}
+
+			server, err := server4.NewServer(
+				iface,
+				nil,
+				handlePacket(logger.WithValues("interface", iface), &bootServerSelector{
+					defaultIP:  serverIP,
+					ifaceAddrs: ifaceAddrs,
+					endpoints:  bootEndpoints,
+				}, apiPort, httpBootMode),
+			)
+			if err != nil {
+				logger.Error(err, "error on DHCP4 proxy startup", "interface", iface)
+
+				return err
+			}
+
+			eg.Go(server.Serve)
+
+			dhcpv4Started = true
+		}
 	}
 
-	server, err := server4.NewServer(
-		iface,
-		nil,
-		handlePacket(logger, apiEndpoint, apiPort),
-	)
+	if serverIP := pickServerIP(serverIPs, true); serverIP != nil {
+		iface, err := findMatchingInterface(serverIP)
+
+		switch {
+		case err == nil:
+			server, err := newDHCPv6Server(logger, iface, serverIP, apiPort, httpBootMode)
+			if err != nil {
+				logger.Error(err, "error on DHCP6 proxy startup")
+
+				return err
+			}
+
+			eg.Go(server.Serve)
+		case dhcpv4Started:
+			// the AAAA record of the endpoint might point to the address which is not assigned locally (e.g. load balancer),
+			// which shouldn't take down the DHCPv4 proxies
+			logger.Info("skipping DHCP6 proxy, no local interface for the endpoint address", "address", serverIP, "reason", err)
+		default:
+			return err
+		}
+	}
+
+	return eg.Wait()
+}
+
+// pickServerIP returns the first address of the family.
+func pickServerIP(serverIPs []net.IP, ipv6 bool) net.IP {
+	for _, ip := range serverIPs {
+		if (ip.To4() == nil) == ipv6 {
+			return ip
+		}
+	}
+
+	return nil
+}
+
+// resolveServerInterface returns the first address of the family of the API endpoint and the name of the interface it is assigned to.
+func resolveServerInterface(apiEndpoint string, ipv6 bool) (net.IP, string, error) {
+	serverIPs, err := net.LookupIP(apiEndpoint)
 	if err != nil {
-		logger.Error(err, "error on DHCP4 proxy startup")
+		return nil, "", err
+	}
 
-		return err
+	serverIP := pickServerIP(serverIPs, ipv6)
+	if serverIP == nil {
+		family := "IPv4"
+		if ipv6 {
+			family = "IPv6"
+		}
+
+		return nil, "", fmt.Errorf("no %s addresses found for %s", family, apiEndpoint)
+	}
+
+	iface, err := findMatchingInterface(serverIP)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return server.Serve()
+	return serverIP, iface, nil
 }
 
 func findMatchingInterface(targetIP net.IP) (string, error) {
@@ -79,7 +171,7 @@ func findMatchingInterface(targetIP net.IP) (string, error) {
 	return "", fmt.Errorf("no interface found for: %s", targetIP)
 }
 
-func handlePacket(logger logr.Logger, apiEndpoint string, apiPort int) func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
+func handlePacket(logger logr.Logger, selector *bootServerSelector, apiPort int, httpBootMode siderotypes.HTTPBootMode) func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
 	return func(conn net.PacketConn, peer net.Addr, m *dhcpv4.DHCPv4) {
 		if err := isBootDHCP(m); err != nil {
 			logger.Info("ignoring packet", "source", m.ClientHWAddr, "reason", err)
@@ -94,14 +186,18 @@ func handlePacket(logger logr.Logger, apiEndpoint string, apiPort int) func(conn
 			return
 		}
 
-		resp, err := offerDHCP(m, apiEndpoint, apiPort, fwtype)
+		serverIP := selector.serverIP(m)
+
+		resp, err := offerDHCP(m, serverIP, apiPort, httpBootMode, fwtype)
 		if err != nil {
 			logger.Error(err, "failed to construct ProxyDHCP offer", "source", m.ClientHWAddr)
 
 			return
 		}
 
-		logger.Info("offering boot response", "source", m.ClientHWAddr, "server", resp.TFTPServerName(), "boot_filename", resp.BootFileNameOption())
+		logger.Info("offering boot response",
+			append([]any{"source", m.ClientHWAddr, "server", serverIP, "boot_filename", resp.BootFileNameOption()}, relayAgentInfo(m)...)...,
+		)
 
 		_, err = conn.WriteTo(resp.ToBytes(), peer)
 		if err != nil {
@@ -178,30 +274,27 @@ func validateDHCP(m *dhcpv4.DHCPv4) (fwtype Firmware, err error) {
 	return fwtype, nil
 }
 
-func offerDHCP(req *dhcpv4.DHCPv4, apiEndpoint string, apiPort int, fwtype Firmware) (*dhcpv4.DHCPv4, error) {
-	serverIPs, err := net.LookupIP(apiEndpoint)
+func offerDHCP(req *dhcpv4.DHCPv4, serverIP net.IP, apiPort int, httpBootMode siderotypes.HTTPBootMode, fwtype Firmware) (*dhcpv4.DHCPv4, error) {
+	resp, err := dhcpv4.NewReplyFromRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(serverIPs) == 0 {
-		return nil, fmt.Errorf("no IPs found for %s", apiEndpoint)
+	if err = addBootOptions(req, resp, serverIP, apiPort, httpBootMode, fwtype); err != nil {
+		return nil, err
 	}
 
-	// pick up the first address
-	serverIP := serverIPs[0]
+	return resp, nil
+}
 
-	modifiers := []dhcpv4.Modifier{
+// addBootOptions fills in the boot server and the boot filename for the firmware type.
+func addBootOptions(req, resp *dhcpv4.DHCPv4, serverIP net.IP, apiPort int, httpBootMode siderotypes.HTTPBootMode, fwtype Firmware) error {
+	for _, modifier := range []dhcpv4.Modifier{
 		dhcpv4.WithServerIP(serverIP),
 		dhcpv4.WithOptionCopied(req, dhcpv4.OptionClientMachineIdentifier),
 		dhcpv4.WithOptionCopied(req, dhcpv4.OptionClassIdentifier),
-	}
-
-	resp, err := dhcpv4.NewReplyFromRequest(req,
-		modifiers...,
-	)
-	if err != nil {
-		return nil, err
+	} {
+		modifier(resp)
 	}
 
 	if resp.GetOneOption(dhcpv4.OptionClassIdentifier) == nil {
@@ -224,19 +317,16 @@ func offerDHCP(req *dhcpv4.DHCPv4, apiEndpoint string, apiPort int, fwtype Firmw
 		// This is completely standard PXE: just load a file from TFTP.
 		resp.UpdateOption(dhcpv4.OptTFTPServerName(serverIP.String()))
 		resp.UpdateOption(dhcpv4.OptBootFileName("snp-arm64.efi"))
-	case FirmwareX86HTTP:
+	case FirmwareX86HTTP, FirmwareARMHTTP:
 		// This is completely standard HTTP-boot: just load a file from HTTP.
-		resp.UpdateOption(dhcpv4.OptBootFileName(fmt.Sprintf("http://%s/tftp/snp.efi", net.JoinHostPort(serverIP.String(), strconv.Itoa(apiPort)))))
-	case FirmwareARMHTTP:
-		// This is completely standard HTTP-boot: just load a file from HTTP.
-		resp.UpdateOption(dhcpv4.OptBootFileName(fmt.Sprintf("http://%s/tftp/snp-arm64.efi", net.JoinHostPort(serverIP.String(), strconv.Itoa(apiPort)))))
+		resp.UpdateOption(dhcpv4.OptBootFileName(httpBootURL(serverIP, apiPort, fwtype, req.ClientHWAddr, httpBootMode)))
 	case FirmwareUnsupported:
 		fallthrough
 	default:
-		return nil, fmt.Errorf("unsupported firmware type %d", fwtype)
+		return fmt.Errorf("unsupported firmware type %d", fwtype)
 	}
 
-	return resp, nil
+	return nil
 }
 
 // Firmware describes a kind of firmware attempting to boot.
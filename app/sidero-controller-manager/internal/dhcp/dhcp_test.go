@@ -4,11 +4,146 @@
 
 package dhcp_test
 
-import "testing"
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
 
-func TestEmpty(t *testing.T) {
-	// added for accurate coverage estimation
-	//
-	// please remove it once any unit-test is added
-	// for this package
+	"github.com/go-logr/logr"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/dhcp"
+)
+
+func TestAuthoritativeServer(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, metalv1.AddToScheme(scheme))
+
+	pool := &metalv1.DHCPPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "provisioning",
+		},
+		Spec: metalv1.DHCPPoolSpec{
+			Subnet:     "172.16.0.0/24",
+			RangeStart: "172.16.0.100",
+			RangeEnd:   "172.16.0.101",
+			Gateway:    "172.16.0.1",
+			DNSServers: []string{"172.16.0.1"},
+			LeaseTime:  &metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+
+	server := &metalv1.Server{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "server-1",
+		},
+		Spec: metalv1.ServerSpec{
+			Hardware: &metalv1.HardwareInformation{
+				Network: &metalv1.NetworkInformation{
+					Interfaces: []*metalv1.NetworkInterface{
+						{Name: "eth0", MAC: "AA:BB:CC:00:00:01"},
+					},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pool, server).
+		WithStatusSubresource(pool).
+		Build()
+
+	s := &dhcp.AuthoritativeServer{
+		Client:   fakeClient,
+		Reader:   fakeClient,
+		Logger:   logr.Discard(),
+		ServerIP: net.ParseIP("172.16.0.2"),
+		APIPort:  8081,
+	}
+
+	serverMAC := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0, 0, 1}
+	otherMAC := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0, 0, 2}
+
+	// PXE boot of the server
+	discover, err := dhcpv4.NewDiscovery(serverMAC, dhcpv4.WithOption(dhcpv4.OptClientArch(iana.EFI_X86_64)))
+	require.NoError(t, err)
+
+	offer, err := s.Respond(ctx, discover, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, dhcpv4.MessageTypeOffer, offer.MessageType())
+	assert.Equal(t, "172.16.0.100", offer.YourIPAddr.String())
+	assert.Equal(t, "172.16.0.2", offer.ServerIdentifier().String())
+	assert.Equal(t, []net.IP{net.ParseIP("172.16.0.1").To4()}, offer.Router())
+	assert.Equal(t, net.IPv4Mask(255, 255, 255, 0), offer.SubnetMask())
+	assert.Equal(t, 10*time.Minute, offer.IPAddressLeaseTime(0))
+	assert.Equal(t, "snp.efi", offer.BootFileNameOption())
+
+	request, err := dhcpv4.NewRequestFromOffer(offer)
+	require.NoError(t, err)
+
+	ack, err := s.Respond(ctx, request, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, dhcpv4.MessageTypeAck, ack.MessageType())
+	assert.Equal(t, "172.16.0.100", ack.YourIPAddr.String())
+
+	// the lease is persisted and it is static, as the MAC belongs to the server
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: pool.Name}, pool))
+	require.Len(t, pool.Status.Leases, 1)
+	assert.Equal(t, "172.16.0.100", pool.Status.Leases[0].IP)
+	assert.Equal(t, "server-1", pool.Status.Leases[0].Server)
+
+	// other client can't take the leased address
+	discover, err = dhcpv4.NewDiscovery(otherMAC, dhcpv4.WithOption(dhcpv4.OptRequestedIPAddress(net.ParseIP("172.16.0.100"))))
+	require.NoError(t, err)
+
+	offer, err = s.Respond(ctx, discover, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "172.16.0.101", offer.YourIPAddr.String())
+	assert.Empty(t, offer.BootFileNameOption(), "not a PXE boot request")
+
+	request, err = dhcpv4.NewRequestFromOffer(offer, dhcpv4.WithOption(dhcpv4.OptRequestedIPAddress(net.ParseIP("172.16.0.100"))))
+	require.NoError(t, err)
+
+	nak, err := s.Respond(ctx, request, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, dhcpv4.MessageTypeNak, nak.MessageType())
+
+	// request to another DHCP server is ignored
+	request, err = dhcpv4.NewRequestFromOffer(offer, dhcpv4.WithOption(dhcpv4.OptServerIdentifier(net.ParseIP("172.16.0.3"))))
+	require.NoError(t, err)
+
+	reply, err := s.Respond(ctx, request, now)
+	require.NoError(t, err)
+	assert.Nil(t, reply)
+
+	// static leases are not released
+	release, err := dhcpv4.New(
+		dhcpv4.WithHwAddr(serverMAC),
+		dhcpv4.WithMessageType(dhcpv4.MessageTypeRelease),
+		dhcpv4.WithClientIP(net.ParseIP("172.16.0.100")),
+	)
+	require.NoError(t, err)
+
+	_, err = s.Respond(ctx, release, now)
+	require.NoError(t, err)
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: pool.Name}, pool))
+	assert.Len(t, pool.Status.Leases, 1)
 }
@@ -0,0 +1,155 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dhcp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/go-logr/logr"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/dhcpv6/server6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/siderolabs/gen/xslices"
+
+	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+func newDHCPv6Server(logger logr.Logger, iface string, serverIP net.IP, apiPort int, httpBootMode siderotypes.HTTPBootMode) (*server6.Server, error) {
+	link, err := net.InterfaceByName(iface)
+	if err != nil {
+		return nil, err
+	}
+
+	serverID := &dhcpv6.DUIDLL{
+		HWType:        iana.HWTypeEthernet,
+		LinkLayerAddr: link.HardwareAddr,
+	}
+
+	return server6.NewServer(iface, nil, handlePacket6(logger, serverIP, apiPort, httpBootMode, serverID))
+}
+
+func handlePacket6(logger logr.Logger, serverIP net.IP, apiPort int, httpBootMode siderotypes.HTTPBootMode, serverID dhcpv6.DUID) server6.Handler {
+	return func(conn net.PacketConn, peer net.Addr, m dhcpv6.DHCPv6) {
+		msg, err := m.GetInnerMessage()
+		if err != nil {
+			logger.Info("invalid packet", "source", peer, "reason", err)
+
+			return
+		}
+
+		if id := msg.Options.ServerID(); id != nil && !id.Equal(serverID) {
+			logger.Info("ignoring packet", "source", peer, "reason", "addressed to another server")
+
+			return
+		}
+
+		fwtype, err := validateDHCPv6(msg)
+		if err != nil {
+			logger.Info("ignoring packet", "source", peer, "reason", err)
+
+			return
+		}
+
+		resp, err := offerDHCPv6(m, msg, serverIP, apiPort, httpBootMode, serverID, fwtype)
+		if err != nil {
+			logger.Error(err, "failed to construct DHCPv6 boot response", "source", peer)
+
+			return
+		}
+
+		var reply dhcpv6.DHCPv6 = resp
+
+		if relay, ok := m.(*dhcpv6.RelayMessage); ok {
+			if reply, err = dhcpv6.NewRelayReplFromRelayForw(relay, resp); err != nil {
+				logger.Error(err, "failed to construct DHCPv6 relay reply", "source", peer)
+
+				return
+			}
+		}
+
+		logger.Info("offering boot response", "source", peer, "type", resp.Type(), "boot_file_url", resp.Options.BootFileURL())
+
+		if _, err = conn.WriteTo(reply.ToBytes(), peer); err != nil {
+			logger.Error(err, "failure sending response", "source", peer)
+		}
+	}
+}
+
+func validateDHCPv6(msg *dhcpv6.Message) (fwtype Firmware, err error) {
+	switch msg.Type() { //nolint:exhaustive
+	case dhcpv6.MessageTypeSolicit, dhcpv6.MessageTypeRequest, dhcpv6.MessageTypeInformationRequest:
+	default:
+		return 0, fmt.Errorf("packet is %s, not a boot request", msg.Type())
+	}
+
+	arches := msg.Options.ArchTypes()
+	if len(arches) == 0 {
+		return 0, errors.New("not a PXE boot request (missing option 61)")
+	}
+
+	for _, arch := range arches {
+		switch arch { //nolint:exhaustive
+		case iana.EFI_IA32, iana.EFI_X86_64, iana.EFI_BC:
+			fwtype = FirmwareX86EFI
+		case iana.EFI_ARM64:
+			fwtype = FirmwareARMEFI
+		case iana.EFI_X86_HTTP, iana.EFI_X86_64_HTTP:
+			fwtype = FirmwareX86HTTP
+		case iana.EFI_ARM64_HTTP:
+			fwtype = FirmwareARMHTTP
+		}
+	}
+
+	if fwtype == FirmwareUnsupported {
+		return 0, fmt.Errorf("unsupported client arch: %v", xslices.Map(arches, func(a iana.Arch) string { return a.String() }))
+	}
+
+	return fwtype, nil
+}
+
+// offerDHCPv6 builds the boot response for the message.
+//
+// The packet is the original (possibly relayed) packet the message was extracted from, it is used to find the client MAC.
+func offerDHCPv6(packet dhcpv6.DHCPv6, msg *dhcpv6.Message, serverIP net.IP, apiPort int, httpBootMode siderotypes.HTTPBootMode, serverID dhcpv6.DUID, fwtype Firmware) (*dhcpv6.Message, error) {
+	// the MAC is only used to build the native HTTP boot URL, so it's not an error if it can't be found
+	mac, _ := dhcpv6.ExtractMAC(packet)
+
+	bootFileURL, err := bootFileURLv6(serverIP, apiPort, httpBootMode, mac, fwtype)
+	if err != nil {
+		return nil, err
+	}
+
+	modifiers := []dhcpv6.Modifier{
+		dhcpv6.WithServerID(serverID),
+		dhcpv6.WithOption(dhcpv6.OptBootFileURL(bootFileURL)),
+	}
+
+	// UEFI HTTP boot clients expect the vendor class to be mirrored back
+	if vendorClass := msg.GetOneOption(dhcpv6.OptionVendorClass); vendorClass != nil {
+		modifiers = append(modifiers, dhcpv6.WithOption(vendorClass))
+	}
+
+	if msg.Type() == dhcpv6.MessageTypeSolicit && msg.GetOneOption(dhcpv6.OptionRapidCommit) == nil {
+		return dhcpv6.NewAdvertiseFromSolicit(msg, modifiers...)
+	}
+
+	return dhcpv6.NewReplyFromMessage(msg, modifiers...)
+}
+
+// bootFileURLv6 returns the boot file URL (option 59) for the firmware type.
+func bootFileURLv6(serverIP net.IP, apiPort int, httpBootMode siderotypes.HTTPBootMode, mac net.HardwareAddr, fwtype Firmware) (string, error) {
+	switch fwtype { //nolint:exhaustive
+	case FirmwareX86EFI:
+		return fmt.Sprintf("tftp://[%s]/snp.efi", serverIP), nil
+	case FirmwareARMEFI:
+		return fmt.Sprintf("tftp://[%s]/snp-arm64.efi", serverIP), nil
+	case FirmwareX86HTTP, FirmwareARMHTTP:
+		return httpBootURL(serverIP, apiPort, fwtype, mac, httpBootMode), nil
+	default:
+		return "", fmt.Errorf("unsupported firmware type %d", fwtype)
+	}
+}
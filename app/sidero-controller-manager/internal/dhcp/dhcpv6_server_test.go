@@ -0,0 +1,77 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package dhcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+func TestOfferDHCPv6(t *testing.T) {
+	serverIP := net.ParseIP("2001:db8::1")
+	serverID := &dhcpv6.DUIDLL{HWType: iana.HWTypeEthernet, LinkLayerAddr: net.HardwareAddr{0, 1, 2, 3, 4, 5}}
+	clientMAC := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0, 0, 1}
+
+	for _, tt := range []struct {
+		name         string
+		arch         iana.Arch
+		vendorClass  string
+		httpBootMode siderotypes.HTTPBootMode
+		expectedURL  string
+	}{
+		{
+			name:        "PXE",
+			arch:        iana.EFI_X86_64,
+			vendorClass: "PXEClient",
+			expectedURL: "tftp://[2001:db8::1]/snp.efi",
+		},
+		{
+			name:        "HTTP boot",
+			arch:        iana.EFI_ARM64_HTTP,
+			vendorClass: "HTTPClient",
+			expectedURL: "http://[2001:db8::1]:8081/tftp/snp-arm64.efi",
+		},
+		{
+			name:         "native HTTP boot",
+			arch:         iana.EFI_X86_64_HTTP,
+			vendorClass:  "HTTPClient",
+			httpBootMode: siderotypes.HTTPBootNative,
+			expectedURL:  "http://[2001:db8::1]:8081/httpboot/amd64/aa-bb-cc-00-00-01/boot.efi",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			solicit, err := dhcpv6.NewSolicit(clientMAC,
+				dhcpv6.WithArchType(tt.arch),
+				dhcpv6.WithOption(&dhcpv6.OptVendorClass{EnterpriseNumber: 343, Data: [][]byte{[]byte(tt.vendorClass)}}),
+			)
+			require.NoError(t, err)
+
+			fwtype, err := validateDHCPv6(solicit)
+			require.NoError(t, err)
+
+			advertise, err := offerDHCPv6(solicit, solicit, serverIP, 8081, tt.httpBootMode, serverID, fwtype)
+			require.NoError(t, err)
+
+			assert.Equal(t, dhcpv6.MessageTypeAdvertise, advertise.Type())
+			assert.Equal(t, tt.expectedURL, advertise.Options.BootFileURL())
+			assert.Equal(t, [][]byte{[]byte(tt.vendorClass)}, advertise.Options.VendorClass(343))
+			assert.True(t, advertise.Options.ServerID().Equal(serverID))
+		})
+	}
+
+	solicit, err := dhcpv6.NewSolicit(clientMAC)
+	require.NoError(t, err)
+
+	_, err = validateDHCPv6(solicit)
+	assert.Error(t, err)
+}
@@ -0,0 +1,34 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package dhcp
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+// httpBootURL returns the boot file URL for the UEFI HTTP boot clients.
+//
+// With the native HTTP boot the client is pointed to the boot endpoint of the machine, which serves the environment UKI directly,
+// otherwise iPXE is chainloaded.
+func httpBootURL(serverIP net.IP, apiPort int, fwtype Firmware, mac net.HardwareAddr, mode siderotypes.HTTPBootMode) string {
+	host := net.JoinHostPort(serverIP.String(), strconv.Itoa(apiPort))
+
+	arch, ipxeBinary := "amd64", "snp.efi"
+	if fwtype == FirmwareARMHTTP {
+		arch, ipxeBinary = "arm64", "snp-arm64.efi"
+	}
+
+	if mode == siderotypes.HTTPBootNative && len(mac) > 0 {
+		// the MAC is formatted the same way iPXE does with `hexhyp`
+		return fmt.Sprintf("http://%s/httpboot/%s/%s/boot.efi", host, arch, strings.ReplaceAll(mac.String(), ":", "-"))
+	}
+
+	return fmt.Sprintf("http://%s/tftp/%s", host, ipxeBinary)
+}
@@ -0,0 +1,70 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package dhcp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+func TestOfferDHCPHTTPBoot(t *testing.T) {
+	clientMAC := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0, 0, 1}
+
+	for _, tt := range []struct {
+		name         string
+		arch         iana.Arch
+		httpBootMode siderotypes.HTTPBootMode
+		expected     string
+	}{
+		{
+			name:         "iPXE",
+			arch:         iana.EFI_X86_64_HTTP,
+			httpBootMode: siderotypes.HTTPBootIPXE,
+			expected:     "http://10.0.1.5:8081/tftp/snp.efi",
+		},
+		{
+			name:         "native amd64",
+			arch:         iana.EFI_X86_64_HTTP,
+			httpBootMode: siderotypes.HTTPBootNative,
+			expected:     "http://10.0.1.5:8081/httpboot/amd64/aa-bb-cc-00-00-01/boot.efi",
+		},
+		{
+			name:         "native arm64",
+			arch:         iana.EFI_ARM64_HTTP,
+			httpBootMode: siderotypes.HTTPBootNative,
+			expected:     "http://10.0.1.5:8081/httpboot/arm64/aa-bb-cc-00-00-01/boot.efi",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := dhcpv4.NewDiscovery(clientMAC, dhcpv4.WithOption(dhcpv4.OptClientArch(tt.arch)))
+			require.NoError(t, err)
+
+			fwtype, err := validateDHCP(req)
+			require.NoError(t, err)
+
+			resp, err := offerDHCP(req, net.ParseIP("10.0.1.5"), 8081, tt.httpBootMode, fwtype)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, resp.BootFileNameOption())
+		})
+	}
+
+	// PXE clients still chainload iPXE
+	req, err := dhcpv4.NewDiscovery(clientMAC, dhcpv4.WithOption(dhcpv4.OptClientArch(iana.EFI_X86_64)))
+	require.NoError(t, err)
+
+	resp, err := offerDHCP(req, net.ParseIP("10.0.1.5"), 8081, siderotypes.HTTPBootNative, FirmwareX86EFI)
+	require.NoError(t, err)
+
+	assert.Equal(t, "snp.efi", resp.BootFileNameOption())
+}
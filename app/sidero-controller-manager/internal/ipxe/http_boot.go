@@ -0,0 +1,280 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package ipxe
+
+import (
+	"context"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	talosconstants "github.com/siderolabs/talos/pkg/machinery/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/siderolink"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/constants"
+)
+
+const (
+	envDir  = "/var/lib/sidero/env"
+	tftpDir = "/var/lib/sidero/tftp"
+)
+
+// errNoHTTPBootAsset is returned when the environment doesn't provide the requested file.
+var errNoHTTPBootAsset = errors.New("no such HTTP boot asset")
+
+// httpBootHandler serves the native UEFI HTTP boot clients.
+//
+// The request path is `/httpboot/<arch>/<mac>/<file>`, the server is looked up by the `uuid` query parameter if set,
+// otherwise by the MAC address of its network interfaces.
+// The environment is picked the same way as for iPXE, environments without the UKI (including the agent environment)
+// chainload iPXE, so that the regular iPXE flow is used, unless the agent UKI is provided.
+func httpBootHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/httpboot/"), "/")
+	if len(parts) != 3 {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	arch, macStr, file := parts[0], parts[1], parts[2]
+
+	if arch != "amd64" && arch != "arm64" {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	macAddr, err := parseMAC(macStr)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "invalid MAC address %q", macStr)
+
+		return
+	}
+
+	mac := macAddr.String()
+	ctx := r.Context()
+
+	uuid := r.URL.Query().Get("uuid")
+	if uuid == "" {
+		if uuid, err = lookupServerUUID(ctx, mac); err != nil {
+			log.Printf("Error looking up server by MAC %q: %v", mac, err)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+	}
+
+	server, serverBinding, err := lookupServer(ctx, uuid)
+	if err != nil {
+		log.Printf("Error looking up server: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	env, err := newEnvironment(ctx, server, serverBinding, arch, mac)
+	if err != nil {
+		if errors.Is(err, ErrBootFromDisk) {
+			// the firmware falls through to the next boot option on failure
+			log.Printf("Server %q booting from disk", uuid)
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if apierrors.IsNotFound(err) {
+			log.Printf("Environment not found: %v", err)
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		log.Printf("%v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	if server == nil || serverBinding == nil {
+		setAgentUKI(env)
+	}
+
+	if !env.IsReadyOn(replica) {
+		log.Printf("Environment not ready: %q", env.Name)
+
+		w.WriteHeader(http.StatusPreconditionFailed)
+		fmt.Fprintf(w, "environment %q is not ready", env.Name)
+
+		return
+	}
+
+	if issueConfigTokens && serverBinding != nil && env.Spec.UKI != nil {
+		if err = checkUKIConfigAccess(env); err != nil {
+			log.Printf("Environment %q can't be booted by %q: %v", env.Name, uuid, err)
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprintf(w, "UKI of environment %q can't fetch the machine configuration: %s", env.Name, err)
+
+			return
+		}
+	}
+
+	path, err := httpBootAsset(env, arch, file)
+	if err != nil {
+		log.Printf("Environment %q: %v: %q", env.Name, err, file)
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	if server != nil {
+		log.Printf("Using %q environment for %q, serving %q", env.Name, server.Name, path)
+	} else {
+		log.Printf("Using %q environment, serving %q", env.Name, path)
+	}
+
+	http.ServeFile(w, r, path)
+}
+
+// httpBootAsset returns the path to the file served to the HTTP boot client.
+//
+// `boot.efi` is the shim if the environment has one, or the UKI otherwise.
+// The shim loads the second stage from the same directory as `grubx64.efi` (`grubaa64.efi` on arm64), which is the UKI.
+func httpBootAsset(env *metalv1.Environment, arch, file string) (string, error) {
+	if env.Spec.UKI == nil {
+		if file != "boot.efi" {
+			return "", errNoHTTPBootAsset
+		}
+
+		if arch == "arm64" {
+			return filepath.Join(tftpDir, "snp-arm64.efi"), nil
+		}
+
+		return filepath.Join(tftpDir, "snp.efi"), nil
+	}
+
+	secondStage := "grubx64.efi"
+	if arch == "arm64" {
+		secondStage = "grubaa64.efi"
+	}
+
+	switch {
+	case file == "boot.efi" && env.Spec.UKI.Shim != nil:
+		return filepath.Join(envDir, env.Name, constants.ShimAsset), nil
+	case file == "boot.efi", file == secondStage && env.Spec.UKI.Shim != nil:
+		return filepath.Join(envDir, env.Name, constants.UKIAsset), nil
+	default:
+		return "", errNoHTTPBootAsset
+	}
+}
+
+// checkUKIConfigAccess checks that the allocated server booting the UKI of the environment can fetch the machine configuration.
+//
+// The UKI has the kernel command line embedded, so the config token can't be passed to it,
+// and the configuration request is allowed only if it comes from the SideroLink address of the server.
+func checkUKIConfigAccess(env *metalv1.Environment) error {
+	cmdline, err := ukiCommandLine(filepath.Join(envDir, env.Name, constants.UKIAsset))
+	if err != nil {
+		return err
+	}
+
+	return checkUKIConfigURL(cmdline, siderolink.Cfg.ServerAddress.Addr())
+}
+
+// ukiCommandLine returns the kernel command line embedded into the UKI.
+func ukiCommandLine(path string) (string, error) {
+	f, err := pe.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading UKI: %w", err)
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	section := f.Section(".cmdline")
+	if section == nil {
+		return "", nil
+	}
+
+	data, err := section.Data()
+	if err != nil {
+		return "", fmt.Errorf("error reading UKI command line: %w", err)
+	}
+
+	return strings.TrimRight(string(data), "\x00"), nil
+}
+
+// checkUKIConfigURL checks that the `talos.config` argument of the kernel command line points to the SideroLink address.
+func checkUKIConfigURL(cmdline string, sideroLinkAddr netip.Addr) error {
+	for _, arg := range strings.Fields(cmdline) {
+		value, ok := strings.CutPrefix(arg, talosconstants.KernelParamConfig+"=")
+		if !ok {
+			continue
+		}
+
+		u, err := url.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s URL: %w", talosconstants.KernelParamConfig, err)
+		}
+
+		if addr, err := netip.ParseAddr(u.Hostname()); err != nil || addr != sideroLinkAddr {
+			return fmt.Errorf("%s should point to the SideroLink address %s, got %q", talosconstants.KernelParamConfig, sideroLinkAddr, u.Host)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("%s is not set in the UKI command line", talosconstants.KernelParamConfig)
+}
+
+// setAgentUKI enables the native HTTP boot of the agent environment if the agent UKI is provided.
+//
+// The agent UKI (and optionally the shim) is either built into the image or mounted to the agent environment directory.
+// As the kernel command line of the UKI is fixed, it should have the `sidero.endpoint` argument embedded.
+func setAgentUKI(env *metalv1.Environment) {
+	if _, err := os.Stat(filepath.Join(envDir, env.Name, constants.UKIAsset)); err != nil {
+		return
+	}
+
+	env.Spec.UKI = &metalv1.UKI{}
+
+	if _, err := os.Stat(filepath.Join(envDir, env.Name, constants.ShimAsset)); err == nil {
+		env.Spec.UKI.Shim = &metalv1.Asset{}
+	}
+}
+
+// lookupServerUUID returns the name of the server which has the network interface with the MAC address.
+//
+// Empty name is returned if there is no such server.
+func lookupServerUUID(ctx context.Context, mac string) (string, error) {
+	var servers metalv1.ServerList
+
+	if err := c.List(ctx, &servers); err != nil {
+		return "", err
+	}
+
+	for _, server := range servers.Items {
+		if server.Spec.Hardware == nil || server.Spec.Hardware.Network == nil {
+			continue
+		}
+
+		for _, iface := range server.Spec.Hardware.Network.Interfaces {
+			if iface != nil && strings.EqualFold(iface.MAC, mac) {
+				return server.Name, nil
+			}
+		}
+	}
+
+	return "", nil
+}
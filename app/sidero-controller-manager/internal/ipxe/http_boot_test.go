@@ -0,0 +1,174 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+//nolint:scopelint
+package ipxe
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+func TestHTTPBootAsset(t *testing.T) {
+	agent := &metalv1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-amd64"},
+	}
+
+	uki := &metalv1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "uki"},
+		Spec: metalv1.EnvironmentSpec{
+			UKI: &metalv1.UKI{
+				Asset: metalv1.Asset{URL: "http://example.com/uki.efi"},
+			},
+		},
+	}
+
+	shim := &metalv1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "shim"},
+		Spec: metalv1.EnvironmentSpec{
+			UKI: &metalv1.UKI{
+				Asset: metalv1.Asset{URL: "http://example.com/uki.efi"},
+				Shim:  &metalv1.Asset{URL: "http://example.com/shim.efi"},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		env      *metalv1.Environment
+		arch     string
+		file     string
+		expected string
+	}{
+		{
+			name:     "agent amd64",
+			env:      agent,
+			arch:     "amd64",
+			file:     "boot.efi",
+			expected: "/var/lib/sidero/tftp/snp.efi",
+		},
+		{
+			name:     "agent arm64",
+			env:      agent,
+			arch:     "arm64",
+			file:     "boot.efi",
+			expected: "/var/lib/sidero/tftp/snp-arm64.efi",
+		},
+		{
+			name:     "uki",
+			env:      uki,
+			arch:     "amd64",
+			file:     "boot.efi",
+			expected: "/var/lib/sidero/env/uki/uki.efi",
+		},
+		{
+			name: "uki without shim",
+			env:  uki,
+			arch: "amd64",
+			file: "grubx64.efi",
+		},
+		{
+			name:     "shim",
+			env:      shim,
+			arch:     "amd64",
+			file:     "boot.efi",
+			expected: "/var/lib/sidero/env/shim/shim.efi",
+		},
+		{
+			name:     "shim second stage",
+			env:      shim,
+			arch:     "arm64",
+			file:     "grubaa64.efi",
+			expected: "/var/lib/sidero/env/shim/uki.efi",
+		},
+		{
+			name: "shim wrong arch",
+			env:  shim,
+			arch: "arm64",
+			file: "grubx64.efi",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := httpBootAsset(tt.env, tt.arch, tt.file)
+
+			if tt.expected == "" {
+				assert.ErrorIs(t, err, errNoHTTPBootAsset)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func TestIPXETemplateUKI(t *testing.T) {
+	var buf bytes.Buffer
+
+	require.NoError(t, ipxeTemplate.Execute(&buf, map[string]any{
+		"Env":      &metalv1.Environment{ObjectMeta: metav1.ObjectMeta{Name: "uki"}},
+		"ChainUKI": true,
+		"UKIAsset": "uki.efi",
+	}))
+
+	assert.Equal(t, "#!ipxe\nchain /env/uki/uki.efi\n", buf.String())
+
+	buf.Reset()
+
+	require.NoError(t, ipxeTemplate.Execute(&buf, map[string]any{
+		"Env": &metalv1.Environment{
+			ObjectMeta: metav1.ObjectMeta{Name: "default"},
+			Spec:       metalv1.EnvironmentSpec{Kernel: metalv1.Kernel{Args: []string{"console=tty0"}}},
+		},
+		"KernelAsset": "vmlinuz",
+		"InitrdAsset": "initramfs.xz",
+	}))
+
+	assert.Equal(t, "#!ipxe\nkernel /env/default/vmlinuz  console=tty0\ninitrd /env/default/initramfs.xz\nboot\n", buf.String())
+}
+
+func TestCheckUKIConfigURL(t *testing.T) {
+	sideroLinkAddr := netip.MustParseAddr("fdae:41e4:649b:9303::1")
+
+	for _, tt := range []struct {
+		name     string
+		cmdline  string
+		expected string
+	}{
+		{
+			name:    "SideroLink",
+			cmdline: "console=ttyS0 talos.config=http://[fdae:41e4:649b:9303::1]:8081/configdata?uuid=${uuid} siderolink.api=grpc://192.168.1.1:8081",
+		},
+		{
+			name:     "physical network",
+			cmdline:  "console=ttyS0 talos.config=http://192.168.1.1:8081/configdata?uuid=${uuid}",
+			expected: `talos.config should point to the SideroLink address fdae:41e4:649b:9303::1, got "192.168.1.1:8081"`,
+		},
+		{
+			name:     "missing",
+			cmdline:  "console=ttyS0",
+			expected: "talos.config is not set in the UKI command line",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUKIConfigURL(tt.cmdline, sideroLinkAddr)
+
+			if tt.expected == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
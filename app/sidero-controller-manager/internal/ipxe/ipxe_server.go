@@ -13,6 +13,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"text/template"
@@ -32,6 +33,7 @@ import (
 
 	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
 	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/configtoken"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/siderolink"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/constants"
 	siderotypes "github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
@@ -62,7 +64,7 @@ set x:int32 0
 
 		ifclose
 		iflinkwait --timeout 5000 net${idx} || goto next_iface
-		dhcp net${idx} || goto next_iface
+		dhcp net${idx} || ifconf --configurator ipv6 net${idx} || goto next_iface
 		goto boot
 
 	:next_iface
@@ -97,10 +99,16 @@ set x:int32 0
 `))
 
 // ipxeTemplate is returned as response to `chain` request from the bootFile/bootTemplate to boot actual OS (or Sidero agent).
+//
+// Environments with the UKI and without the kernel chainload the UKI, which has the kernel command line embedded.
 var ipxeTemplate = template.Must(template.New("iPXE config").Parse(`#!ipxe
+{{- if .ChainUKI }}
+chain /env/{{ .Env.Name }}/{{ .UKIAsset }}
+{{- else }}
 kernel /env/{{ .Env.Name }}/{{ .KernelAsset }} {{range $arg := .Env.Spec.Kernel.Args}} {{$arg}}{{end}}
 initrd /env/{{ .Env.Name }}/{{ .InitrdAsset }}
 boot
+{{- end }}
 `))
 
 // ipxeBootFromDiskExit script is used to skip PXE booting and boot from disk via exit.
@@ -119,6 +127,10 @@ var (
 	extraAgentKernelArgs      string
 	defaultBootFromDiskMethod siderotypes.BootFromDisk
 	c                         client.Client
+	// replica is the name of this controller manager replica, Environments are served once the assets are synced to it.
+	replica string
+	// issueConfigTokens enables the one-time tokens to fetch the machine configuration.
+	issueConfigTokens bool
 )
 
 func bootFileHandler(w http.ResponseWriter, r *http.Request) {
@@ -197,7 +209,7 @@ func ipxeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if !env.IsReady() {
+	if !env.IsReadyOn(replica) {
 		log.Printf("Environment not ready: %q", env.Name)
 
 		w.WriteHeader(http.StatusPreconditionFailed)
@@ -214,12 +226,48 @@ func ipxeHandler(w http.ResponseWriter, r *http.Request) {
 
 	args := struct {
 		Env         *metalv1.Environment
+		ChainUKI    bool
 		KernelAsset string
 		InitrdAsset string
+		UKIAsset    string
 	}{
 		Env:         env,
+		ChainUKI:    env.Spec.UKI != nil && env.Spec.Kernel.URL == "",
 		KernelAsset: constants.KernelAsset,
 		InitrdAsset: constants.InitrdAsset,
+		UKIAsset:    constants.UKIAsset,
+	}
+
+	// the UKI has the kernel command line embedded, so the token can't be passed to it,
+	// and the server should fetch the machine configuration via SideroLink
+	if issueConfigTokens && serverBinding != nil && args.ChainUKI {
+		if err = checkUKIConfigAccess(env); err != nil {
+			log.Printf("Environment %q can't be booted by %q: %v", env.Name, uuid, err)
+
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprintf(w, "UKI of environment %q can't fetch the machine configuration: %s", env.Name, err)
+
+			return
+		}
+	}
+
+	// the token proves the identity of the server when it fetches the machine configuration
+	if issueConfigTokens && serverBinding != nil && !args.ChainUKI {
+		token, err := configtoken.Issue(ctx, c, serverBinding, configtoken.ClientAddress(r))
+
+		switch {
+		case errors.Is(err, configtoken.ErrAlreadyIssued):
+			// the environment is still served, as the server might not need the configuration (e.g. with pxeBootAlways),
+			// or it might fetch it via SideroLink
+			log.Printf("Config token for %q was already issued for the current boot, not issuing another one to %s", uuid, r.RemoteAddr)
+		case err != nil:
+			log.Printf("error issuing config token: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		default:
+			addConfigToken(env, token)
+		}
 	}
 
 	var buf bytes.Buffer
@@ -269,15 +317,17 @@ func getBootFromDiskMethod(ctx context.Context, server *metalv1.Server, serverBi
 
 var embeddedScriptBuf bytes.Buffer
 
-func RegisterIPXE(mux *http.ServeMux, endpoint string, port int, args string, bootMethod siderotypes.BootFromDisk, iPXEPort int, mgrClient client.Client) error {
+func RegisterIPXE(mux *http.ServeMux, endpoint string, port int, args string, bootMethod siderotypes.BootFromDisk, iPXEPort int, replicaName string, configTokens bool, mgrClient client.Client) error {
 	apiEndpoint = endpoint
+	replica = replicaName
+	issueConfigTokens = configTokens
 	apiPort = port
 	extraAgentKernelArgs = args
 	defaultBootFromDiskMethod = bootMethod
 	c = mgrClient
 
 	if err := BootTemplate.Execute(&embeddedScriptBuf, map[string]string{
-		"Endpoint": apiEndpoint,
+		"Endpoint": urlHost(apiEndpoint),
 		"Port":     strconv.Itoa(iPXEPort),
 	}); err != nil {
 		return err
@@ -289,12 +339,22 @@ func RegisterIPXE(mux *http.ServeMux, endpoint string, port int, args string, bo
 
 	mux.Handle("/boot.ipxe", logRequest(http.HandlerFunc(bootFileHandler)))
 	mux.Handle("/ipxe", logRequest(http.HandlerFunc(ipxeHandler)))
-	mux.Handle("/env/", logRequest(http.StripPrefix("/env/", http.FileServer(http.Dir("/var/lib/sidero/env")))))
-	mux.Handle("/tftp/", logRequest(http.StripPrefix("/tftp/", http.FileServer(http.Dir("/var/lib/sidero/tftp")))))
+	mux.Handle("/httpboot/", logRequest(http.HandlerFunc(httpBootHandler)))
+	mux.Handle("/env/", logRequest(http.StripPrefix("/env/", http.FileServer(http.Dir(envDir)))))
+	mux.Handle("/tftp/", logRequest(http.StripPrefix("/tftp/", http.FileServer(http.Dir(tftpDir)))))
 
 	return nil
 }
 
+// urlHost brackets IPv6 addresses to be used as the URL host.
+func urlHost(endpoint string) string {
+	if ip := net.ParseIP(endpoint); ip != nil && ip.To4() == nil {
+		return "[" + endpoint + "]"
+	}
+
+	return endpoint
+}
+
 func logRequest(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		log.Printf("HTTP %s %v %s", r.Method, r.URL, r.RemoteAddr)
@@ -335,6 +395,10 @@ func parseMAC(s string) (net.HardwareAddr, error) {
 }
 
 func lookupServer(ctx context.Context, uuid string) (*metalv1.Server, *infrav1.ServerBinding, error) {
+	if uuid == "" {
+		return nil, nil, nil
+	}
+
 	key := client.ObjectKey{
 		Name: uuid,
 	}
@@ -408,7 +472,7 @@ func newAgentEnvironment(arch, mac string) *metalv1.Environment {
 		"initrd=initramfs.xz",
 		"panic=30",
 		fmt.Sprintf("%s=%s", constants.AgentMACArg, mac),
-		fmt.Sprintf("%s=%s:%d", constants.AgentEndpointArg, apiEndpoint, apiPort),
+		fmt.Sprintf("%s=%s", constants.AgentEndpointArg, net.JoinHostPort(apiEndpoint, strconv.Itoa(apiPort))),
 	)
 
 	cmdline := procfs.NewCmdline(strings.Join(args, " "))
@@ -526,6 +590,31 @@ outer:
 	}
 }
 
+// addConfigToken adds the token to the Sidero metadata server URL in the talos.config kernel argument.
+func addConfigToken(env *metalv1.Environment, token string) {
+	talosConfigPrefix := talosconstants.KernelParamConfig + "="
+
+	for i, arg := range env.Spec.Kernel.Args {
+		if !strings.HasPrefix(arg, talosConfigPrefix) {
+			continue
+		}
+
+		u, err := url.Parse(strings.TrimPrefix(arg, talosConfigPrefix))
+		if err != nil || !strings.HasSuffix(u.Path, "/configdata") {
+			// the custom configuration URL, don't leak the token
+			return
+		}
+
+		query := u.Query()
+		query.Set(configtoken.QueryParam, token)
+		u.RawQuery = query.Encode()
+
+		env.Spec.Kernel.Args[i] = talosConfigPrefix + u.String()
+
+		return
+	}
+}
+
 func Check(addr string) healthz.Checker {
 	return func(_ *http.Request) error {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -0,0 +1,105 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+// resolveConfigPatches returns the MachineConfigPatches applied to the server in the order they should be applied.
+//
+// The patches are either selected by the Server labels, or referenced by the ServerClass, the MetalMachine or the Server.
+func (m *metadataConfigs) resolveConfigPatches(
+	ctx context.Context,
+	serverObj *metalv1.Server,
+	serverClassObj *metalv1.ServerClass,
+	metalMachine *infrav1.MetalMachine,
+) ([]metalv1.MachineConfigPatch, errorWithCode) {
+	var patchList metalv1.MachineConfigPatchList
+
+	if err := m.client.List(ctx, &patchList); err != nil {
+		return nil, errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure listing machine config patches: %w", err)}
+	}
+
+	byName := make(map[string]metalv1.MachineConfigPatch, len(patchList.Items))
+
+	for _, patch := range patchList.Items {
+		byName[patch.Name] = patch
+	}
+
+	applied := map[string]struct{}{}
+
+	for _, refs := range []struct {
+		source string
+		refs   []corev1.LocalObjectReference
+	}{
+		{source: "serverclass " + serverClassObj.Name, refs: serverClassObj.Spec.ConfigPatchRefs},
+		{source: "metalmachine " + metalMachine.Namespace + "/" + metalMachine.Name, refs: metalMachine.Spec.ConfigPatchRefs},
+		{source: "server " + serverObj.Name, refs: serverObj.Spec.ConfigPatchRefs},
+	} {
+		for _, ref := range refs.refs {
+			if _, ok := byName[ref.Name]; !ok {
+				return nil, errorWithCode{
+					http.StatusUnprocessableEntity,
+					fmt.Errorf("machine config patch %q referenced by %s is not found", ref.Name, refs.source),
+				}
+			}
+
+			applied[ref.Name] = struct{}{}
+		}
+	}
+
+	for _, patch := range patchList.Items {
+		selected, err := patch.Selects(serverObj)
+		if err != nil {
+			return nil, errorWithCode{http.StatusUnprocessableEntity, err}
+		}
+
+		if selected {
+			applied[patch.Name] = struct{}{}
+		}
+	}
+
+	patches := make([]metalv1.MachineConfigPatch, 0, len(applied))
+
+	for name := range applied {
+		patches = append(patches, byName[name])
+	}
+
+	metalv1.SortMachineConfigPatches(patches)
+
+	return patches, errorWithCode{}
+}
+
+// recordAppliedConfigPatches records the applied MachineConfigPatches and their generations in the ServerBinding status.
+func (m *metadataConfigs) recordAppliedConfigPatches(ctx context.Context, serverBinding *infrav1.ServerBinding, patches []metalv1.MachineConfigPatch) error {
+	var appliedPatches []infrav1.AppliedConfigPatch
+
+	for _, patch := range patches {
+		appliedPatches = append(appliedPatches, infrav1.AppliedConfigPatch{
+			Name:       patch.Name,
+			Generation: patch.Generation,
+		})
+	}
+
+	if slices.Equal(serverBinding.Status.AppliedConfigPatches, appliedPatches) {
+		return nil
+	}
+
+	patch := runtimeclient.MergeFrom(serverBinding.DeepCopy())
+
+	serverBinding.Status.AppliedConfigPatches = appliedPatches
+
+	return m.client.Status().Patch(ctx, serverBinding, patch)
+}
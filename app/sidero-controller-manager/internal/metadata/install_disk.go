@@ -0,0 +1,107 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metadata
+
+import (
+	"fmt"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+// injectInstallDisk sets the install disk selected from the server hardware information in the machine configuration.
+//
+// The disk is pinned by its WWID or serial number via the disk selector, the device name is used only if neither is known.
+// The install disk selector of the server takes precedence over the one of the server class.
+// If no selector is set, the machine configuration is returned as is.
+func (m *metadataConfigs) injectInstallDisk(decodedData []byte, serverObj *metalv1.Server, serverClassObj *metalv1.ServerClass) ([]byte, errorWithCode) {
+	selector := serverClassObj.Spec.InstallDisk
+	if serverObj.Spec.InstallDisk != nil {
+		selector = serverObj.Spec.InstallDisk
+	}
+
+	if selector == nil {
+		return decodedData, errorWithCode{}
+	}
+
+	device, err := selector.Select(serverObj.Spec.Hardware)
+	if err != nil {
+		if m.recorder != nil {
+			m.recorder.Eventf(serverObj, corev1.EventTypeWarning, "InstallDiskNotFound", "Failed to select the install disk: %s.", err)
+		}
+
+		return nil, errorWithCode{http.StatusUnprocessableEntity, fmt.Errorf("failure selecting install disk of server %s: %w", serverObj.Name, err)}
+	}
+
+	// avoid using the `configloader` from Talos machinery here, as it will fail on "unknown" fields
+	// causing a dependency on Talos version that Sidero was built with
+	var cfg struct {
+		Machine *struct {
+			Install *struct {
+				Disk         *string        `yaml:"disk"`
+				DiskSelector map[string]any `yaml:"diskSelector"`
+			} `yaml:"install"`
+		} `yaml:"machine"`
+	}
+
+	if err = yaml.Unmarshal(decodedData, &cfg); err != nil {
+		return nil, errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure creating config struct: %s", err)}
+	}
+
+	// the device name might change across reboots, so the disk is pinned by the stable identifier if it is known
+	var diskSelector map[string]any
+
+	switch {
+	case device.WWID != "":
+		diskSelector = map[string]any{"wwid": device.WWID}
+	case device.Serial != "":
+		diskSelector = map[string]any{"serial": device.Serial}
+	}
+
+	// Talos prefers the disk selector over the install disk, and the selector keys would be merged with the existing ones,
+	// so the existing selector is removed, and the install disk is removed as well if the selector is set
+	remove := map[string]any{}
+
+	if cfg.Machine != nil && cfg.Machine.Install != nil {
+		if cfg.Machine.Install.DiskSelector != nil {
+			remove["diskSelector"] = map[string]any{"$patch": "delete"}
+		}
+
+		if cfg.Machine.Install.Disk != nil && diskSelector != nil {
+			remove["disk"] = map[string]any{"$patch": "delete"}
+		}
+	}
+
+	if len(remove) > 0 {
+		var patchErr errorWithCode
+
+		if decodedData, patchErr = patchInstall(decodedData, remove); patchErr.errorObj != nil {
+			return nil, patchErr
+		}
+	}
+
+	if diskSelector != nil {
+		return patchInstall(decodedData, map[string]any{"diskSelector": diskSelector})
+	}
+
+	return patchInstall(decodedData, map[string]any{"disk": device.DeviceName})
+}
+
+// patchInstall applies the strategic merge patch to the `machine.install` section of the machine configuration.
+func patchInstall(decodedData []byte, install map[string]any) ([]byte, errorWithCode) {
+	patch, err := yaml.Marshal(map[string]any{
+		"machine": map[string]any{
+			"install": install,
+		},
+	})
+	if err != nil {
+		return nil, errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure marshaling install disk: %s", err)}
+	}
+
+	return patchConfig(decodedData, patch)
+}
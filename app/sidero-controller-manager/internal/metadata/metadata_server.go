@@ -8,10 +8,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strconv"
 
@@ -25,11 +27,13 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/cluster-api/util"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
 	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/configtoken"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/siderolink"
 )
 
@@ -40,8 +44,10 @@ type errorWithCode struct {
 
 type metadataConfigs struct {
 	client      runtimeclient.Client
+	recorder    record.EventRecorder
 	apiEndpoint string
 	apiPort     int
+	insecure    bool
 }
 
 func throwError(w http.ResponseWriter, ewc errorWithCode) {
@@ -49,11 +55,17 @@ func throwError(w http.ResponseWriter, ewc errorWithCode) {
 	log.Println(ewc.errorObj)
 }
 
-func RegisterServer(mux *http.ServeMux, k8sClient runtimeclient.Client, apiEndpoint string, apiPort int) error {
+// RegisterServer registers the machine configuration endpoint.
+//
+// Unless insecure is set, the configuration is served only to the requests from the SideroLink address of the server,
+// or the requests with the one-time token issued to the server on boot.
+func RegisterServer(mux *http.ServeMux, k8sClient runtimeclient.Client, recorder record.EventRecorder, apiEndpoint string, apiPort int, insecure bool) error {
 	mm := metadataConfigs{
 		client:      k8sClient,
+		recorder:    recorder,
 		apiEndpoint: apiEndpoint,
 		apiPort:     apiPort,
+		insecure:    insecure,
 	}
 
 	mux.HandleFunc("/configdata", mm.FetchConfig)
@@ -97,44 +109,93 @@ func (m *metadataConfigs) FetchConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Given the MetalMachine, find the Machine resource that owns it
-	ownerMachine, err := util.GetOwnerMachine(ctx, m.client, metalMachine.ObjectMeta)
-	if err != nil || ownerMachine == nil {
+	tokenAuth, ewc := m.authorize(r, &serverBinding)
+	if ewc.errorObj != nil {
+		m.recorder.Eventf(&serverBinding, v1.EventTypeWarning, "ConfigDataDenied", "%s (remote address %s)", ewc.errorObj, r.RemoteAddr)
+
 		throwError(
 			w,
-			errorWithCode{
-				http.StatusInternalServerError,
-				fmt.Errorf(
-					"failure fetching owner machine from metal machine %s/%s: %s",
-					metalMachine.GetNamespace(),
-					metalMachine.GetName(),
-					err,
-				),
-			},
+			ewc,
 		)
 
 		return
 	}
 
-	// Dig bootstrap secret name out of owner Machine resource and fetch secret data
-	bootstrapSecretName := ownerMachine.Spec.Bootstrap.DataSecretName
-
-	if bootstrapSecretName == nil {
+	decodedData, machineConfigPatches, ewc := m.renderConfig(ctx, uuid, &metalMachine, &serverBinding)
+	if ewc.errorObj != nil {
 		throwError(
 			w,
-			errorWithCode{
-				http.StatusNotFound,
-				fmt.Errorf(
-					"no dataSecretName present for machine %s/%s",
-					ownerMachine.Namespace,
-					ownerMachine.Name,
-				),
-			},
+			ewc,
 		)
 
 		return
 	}
 
+	// the token is used only when the configuration is served, so that the server can retry until the bootstrap data is ready
+	if tokenAuth {
+		if ewc = m.useToken(ctx, r, &serverBinding); ewc.errorObj != nil {
+			m.recorder.Eventf(&serverBinding, v1.EventTypeWarning, "ConfigDataDenied", "%s (remote address %s)", ewc.errorObj, r.RemoteAddr)
+
+			throwError(
+				w,
+				ewc,
+			)
+
+			return
+		}
+	}
+
+	if err := m.recordAppliedConfigPatches(ctx, &serverBinding, machineConfigPatches); err != nil {
+		log.Printf("failed to record applied machine config patches for %q: %v", uuid, err)
+	}
+
+	// Finally return config data
+	if _, err := w.Write(decodedData); err != nil {
+		log.Printf("failed to write data: %v", err)
+		return
+	}
+
+	log.Printf("successfully returned metadata for %q", uuid)
+}
+
+// renderConfig renders the machine configuration of the server: the bootstrap data with the config patches applied,
+// the node labels and the SideroLink configuration.
+//
+// It also returns the MachineConfigPatches applied to the configuration.
+func (m *metadataConfigs) renderConfig(
+	ctx context.Context,
+	uuid string,
+	metalMachine *infrav1.MetalMachine,
+	serverBinding *infrav1.ServerBinding,
+) ([]byte, []metalv1.MachineConfigPatch, errorWithCode) {
+	// Given the MetalMachine, find the Machine resource that owns it
+	ownerMachine, err := util.GetOwnerMachine(ctx, m.client, metalMachine.ObjectMeta)
+	if err != nil || ownerMachine == nil {
+		return nil, nil, errorWithCode{
+			http.StatusInternalServerError,
+			fmt.Errorf(
+				"failure fetching owner machine from metal machine %s/%s: %s",
+				metalMachine.GetNamespace(),
+				metalMachine.GetName(),
+				err,
+			),
+		}
+	}
+
+	// Dig bootstrap secret name out of owner Machine resource and fetch secret data
+	bootstrapSecretName := ownerMachine.Spec.Bootstrap.DataSecretName
+
+	if bootstrapSecretName == nil {
+		return nil, nil, errorWithCode{
+			http.StatusNotFound,
+			fmt.Errorf(
+				"no dataSecretName present for machine %s/%s",
+				ownerMachine.Namespace,
+				ownerMachine.Name,
+			),
+		}
+	}
+
 	decodedData, ewc := m.fetchBootstrapSecret(
 		ctx,
 		types.NamespacedName{
@@ -143,12 +204,7 @@ func (m *metadataConfigs) FetchConfig(w http.ResponseWriter, r *http.Request) {
 		},
 	)
 	if ewc.errorObj != nil {
-		throwError(
-			w,
-			ewc,
-		)
-
-		return
+		return nil, nil, ewc
 	}
 
 	// Get the server resource by the UUID that was passed in.
@@ -164,19 +220,14 @@ func (m *metadataConfigs) FetchConfig(w http.ResponseWriter, r *http.Request) {
 		serverObj,
 	)
 	if err != nil {
-		throwError(
-			w,
-			errorWithCode{
-				http.StatusInternalServerError,
-				fmt.Errorf(
-					"failure fetching server %s: %s",
-					uuid,
-					err,
-				),
-			},
-		)
-
-		return
+		return nil, nil, errorWithCode{
+			http.StatusInternalServerError,
+			fmt.Errorf(
+				"failure fetching server %s: %s",
+				uuid,
+				err,
+			),
+		}
 	}
 
 	// Given a server object, see if it came from a serverclass (it will have an ownerref)
@@ -193,73 +244,88 @@ func (m *metadataConfigs) FetchConfig(w http.ResponseWriter, r *http.Request) {
 			serverClassObj,
 		)
 		if err != nil {
-			throwError(
-				w,
-				errorWithCode{
-					http.StatusInternalServerError,
-					fmt.Errorf(
-						"failure fetching serverclass %s: %s",
-						serverBinding.Spec.ServerClassRef.Name,
-						err,
-					),
-				},
-			)
-
-			return
+			return nil, nil, errorWithCode{
+				http.StatusInternalServerError,
+				fmt.Errorf(
+					"failure fetching serverclass %s: %s",
+					serverBinding.Spec.ServerClassRef.Name,
+					err,
+				),
+			}
 		}
 	}
 
-	decodedData, ewc = handlePatches(decodedData, serverClassObj.Spec.ConfigPatches, serverClassObj.Spec.StrategicPatches)
+	// MachineConfigPatches are applied before the inline patches of the ServerClass and the Server
+	machineConfigPatches, ewc := m.resolveConfigPatches(ctx, serverObj, serverClassObj, metalMachine)
 	if ewc.errorObj != nil {
-		throwError(
-			w,
-			ewc,
-		)
+		return nil, nil, ewc
+	}
 
-		return
+	for _, patch := range machineConfigPatches {
+		decodedData, ewc = handlePatches(decodedData, patch.Spec.ConfigPatches, patch.Spec.StrategicPatches)
+		if ewc.errorObj != nil {
+			ewc.errorObj = fmt.Errorf("machine config patch %q: %w", patch.Name, ewc.errorObj)
+
+			return nil, nil, ewc
+		}
 	}
 
-	decodedData, ewc = handlePatches(decodedData, serverObj.Spec.ConfigPatches, serverObj.Spec.StrategicPatches)
+	serverClassPatches, serverClassStrategicPatches := serverClassObj.Spec.ConfigPatches, serverClassObj.Spec.StrategicPatches
+	serverPatches, serverStrategicPatches := serverObj.Spec.ConfigPatches, serverObj.Spec.StrategicPatches
+
+	if serverClassObj.Spec.PatchTemplating != nil || serverObj.Spec.PatchTemplating != nil {
+		data, ewc := m.newTemplateData(ctx, serverObj, serverClassObj, serverBinding, ownerMachine)
+		if ewc.errorObj != nil {
+			return nil, nil, ewc
+		}
+
+		if serverClassObj.Spec.PatchTemplating != nil {
+			serverClassPatches, serverClassStrategicPatches, ewc = renderPatches(data, "serverclass "+serverClassObj.Name, serverClassPatches, serverClassStrategicPatches)
+			if ewc.errorObj != nil {
+				return nil, nil, ewc
+			}
+		}
+
+		if serverObj.Spec.PatchTemplating != nil {
+			serverPatches, serverStrategicPatches, ewc = renderPatches(data, "server "+serverObj.Name, serverPatches, serverStrategicPatches)
+			if ewc.errorObj != nil {
+				return nil, nil, ewc
+			}
+		}
+	}
+
+	decodedData, ewc = handlePatches(decodedData, serverClassPatches, serverClassStrategicPatches)
 	if ewc.errorObj != nil {
-		throwError(
-			w,
-			ewc,
-		)
+		return nil, nil, ewc
+	}
 
-		return
+	decodedData, ewc = handlePatches(decodedData, serverPatches, serverStrategicPatches)
+	if ewc.errorObj != nil {
+		return nil, nil, ewc
+	}
+
+	// The install disk is set after the patches are applied, as the selected disk replaces the install disk and the disk selector
+	// set by the patches
+	decodedData, ewc = m.injectInstallDisk(decodedData, serverObj, serverClassObj)
+	if ewc.errorObj != nil {
+		return nil, nil, ewc
 	}
 
 	// Append or add a node label to kubelet extra args.
 	// We must do this so that we can map a given server resource to a k8s node in the workload cluster.
 	decodedData, ewc = labelNodes(decodedData, serverObj.Name)
 	if ewc.errorObj != nil {
-		throwError(
-			w,
-			ewc,
-		)
-
-		return
+		return nil, nil, ewc
 	}
 
 	// Patch machine configuration with SideroLink config so it survives reboots
 	// TODO(laurazard): only do this if Talos v1.10+
 	decodedData, ewc = m.patchSideroLinkConfig(decodedData)
 	if ewc.errorObj != nil {
-		throwError(
-			w,
-			ewc,
-		)
-
-		return
+		return nil, nil, ewc
 	}
 
-	// Finally return config data
-	if _, err = w.Write(decodedData); err != nil {
-		log.Printf("failed to write data: %v", err)
-		return
-	}
-
-	log.Printf("successfully returned metadata for %q", uuid)
+	return decodedData, machineConfigPatches, errorWithCode{}
 }
 
 // this function is responsible for applying rfc6902 and a strategic merge patch to bootstrap data.
@@ -494,6 +560,47 @@ func (m *metadataConfigs) findMetalMachineServerBinding(ctx context.Context, ser
 	return metalMachine, serverBinding, errorWithCode{}
 }
 
+// authorize checks that the request comes from the server: either from its SideroLink address, or with the valid one-time token.
+//
+// The token is only verified here, it should be used with useToken once the configuration is served.
+func (m *metadataConfigs) authorize(r *http.Request, serverBinding *infrav1.ServerBinding) (tokenAuth bool, ewc errorWithCode) {
+	if m.insecure {
+		return false, errorWithCode{}
+	}
+
+	if remoteAddr, err := netip.ParseAddrPort(r.RemoteAddr); err == nil && serverBinding.Spec.SideroLink.NodeAddress != "" {
+		nodeAddress, err := netip.ParsePrefix(serverBinding.Spec.SideroLink.NodeAddress)
+		if err == nil && nodeAddress.Addr() == remoteAddr.Addr().Unmap() {
+			return false, errorWithCode{}
+		}
+	}
+
+	if err := configtoken.Verify(serverBinding, r.URL.Query().Get(configtoken.QueryParam), configtoken.ClientAddress(r)); err != nil {
+		return false, tokenError(serverBinding, err)
+	}
+
+	return true, errorWithCode{}
+}
+
+// useToken marks the token as used, so that the configuration can't be fetched with it again.
+func (m *metadataConfigs) useToken(ctx context.Context, r *http.Request, serverBinding *infrav1.ServerBinding) errorWithCode {
+	if err := configtoken.Use(ctx, m.client, serverBinding, r.URL.Query().Get(configtoken.QueryParam), configtoken.ClientAddress(r)); err != nil {
+		return tokenError(serverBinding, err)
+	}
+
+	return errorWithCode{}
+}
+
+func tokenError(serverBinding *infrav1.ServerBinding, err error) errorWithCode {
+	for _, tokenErr := range []error{configtoken.ErrMissing, configtoken.ErrNotIssued, configtoken.ErrInvalid, configtoken.ErrExpired, configtoken.ErrAlreadyUsed, configtoken.ErrAddress} {
+		if errors.Is(err, tokenErr) {
+			return errorWithCode{http.StatusForbidden, fmt.Errorf("machine configuration request for %q denied: %w", serverBinding.Name, err)}
+		}
+	}
+
+	return errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure verifying config token for %q: %w", serverBinding.Name, err)}
+}
+
 func (m *metadataConfigs) patchSideroLinkConfig(decodedData []byte) ([]byte, errorWithCode) {
 	var ewc errorWithCode
 
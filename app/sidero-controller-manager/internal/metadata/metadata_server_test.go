@@ -5,11 +5,13 @@
 package metadata_test
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/netip"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -18,11 +20,14 @@ import (
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
 	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/configtoken"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/metadata"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/siderolink"
 )
@@ -82,7 +87,7 @@ func TestMetadataService(t *testing.T) {
 
 	mux := http.NewServeMux()
 
-	metadata.RegisterServer(mux, fakeClient, "192.168.1.1", 8081)
+	metadata.RegisterServer(mux, fakeClient, record.NewFakeRecorder(100), "192.168.1.1", 8081, true)
 
 	srv := httptest.NewServer(mux)
 	t.Cleanup(srv.Close)
@@ -244,6 +249,167 @@ func TestMetadataService(t *testing.T) {
 				extensionServiceCfg,
 			}, sideroLinkCfgs...),
 		},
+		{
+			name:         "templated server and server class patches",
+			path:         "/configdata?uuid=7777-8888-9999",
+			expectedCode: http.StatusOK,
+			expectedConfigs: append([]map[string]any{
+				{
+					"version": "v1alpha1",
+					"cluster": nil,
+					"machine": map[string]any{
+						"token":    "",
+						"type":     "",
+						"certSANs": []any{},
+						"kubelet": map[string]any{
+							"extraArgs": map[string]any{
+								"node-labels": "metal.sidero.dev/uuid=7777-8888-9999",
+							},
+						},
+						"install": map[string]any{
+							"disk":            "/dev/nvme0n1",
+							"extraKernelArgs": []any{"sidero.wwid=eui.0025388b71b03a6e"},
+							"wipe":            nil,
+						},
+						"network": map[string]any{
+							"hostname":    "rack7-cluster-7-7777-8888-9999",
+							"nameservers": []any{"10.0.0.53", "10.0.0.54"},
+						},
+					},
+				},
+			}, sideroLinkCfgs...),
+		},
+		{
+			name:         "templated patch with missing required variable",
+			path:         "/configdata?uuid=8888-9999-0000",
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "failure rendering config patch template: template: server 8888-9999-0000 strategicPatches[0]:3:34: executing \"server 8888-9999-0000 strategicPatches[0]\" at <required \"hostname variable is not set\">: error calling required: hostname variable is not set\n",
+		},
+		{
+			name:         "machine config patches",
+			path:         "/configdata?uuid=9999-0000-1111",
+			expectedCode: http.StatusOK,
+			expectedConfigs: append([]map[string]any{
+				{
+					"version": "v1alpha1",
+					"cluster": nil,
+					"machine": map[string]any{
+						"token":    "",
+						"type":     "",
+						"certSANs": []any{},
+						"kubelet": map[string]any{
+							"extraArgs": map[string]any{
+								"node-labels": "metal.sidero.dev/uuid=9999-0000-1111",
+							},
+						},
+						"install": map[string]any{
+							"disk": "/dev/sda",
+							"wipe": nil,
+						},
+						"network": map[string]any{
+							"hostname": "rack9",
+						},
+					},
+				},
+			}, sideroLinkCfgs...),
+		},
+		{
+			name:         "missing machine config patch",
+			path:         "/configdata?uuid=1010-1111-2222",
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "machine config patch \"missing\" referenced by server 1010-1111-2222 is not found\n",
+		},
+		{
+			name:         "install disk selector",
+			path:         "/configdata?uuid=1212-1313-1414",
+			expectedCode: http.StatusOK,
+			expectedConfigs: append([]map[string]any{
+				{
+					"version": "v1alpha1",
+					"cluster": nil,
+					"machine": map[string]any{
+						"token":    "",
+						"type":     "",
+						"certSANs": []any{},
+						"kubelet": map[string]any{
+							"extraArgs": map[string]any{
+								"node-labels": "metal.sidero.dev/uuid=1212-1313-1414",
+							},
+						},
+						"install": map[string]any{
+							"diskSelector": map[string]any{
+								"wwid": "eui.0001",
+							},
+							"wipe": nil,
+						},
+					},
+				},
+			}, sideroLinkCfgs...),
+		},
+		{
+			name:         "install disk selector by serial overriding the install disk patch",
+			path:         "/configdata?uuid=1515-1616-1717",
+			expectedCode: http.StatusOK,
+			expectedConfigs: append([]map[string]any{
+				{
+					"version": "v1alpha1",
+					"cluster": nil,
+					"machine": map[string]any{
+						"token":    "",
+						"type":     "",
+						"certSANs": []any{},
+						"kubelet": map[string]any{
+							"extraArgs": map[string]any{
+								"node-labels": "metal.sidero.dev/uuid=1515-1616-1717",
+							},
+						},
+						"install": map[string]any{
+							"diskSelector": map[string]any{
+								"serial": "S3Z0002",
+							},
+							"wipe": nil,
+						},
+					},
+				},
+			}, sideroLinkCfgs...),
+		},
+		{
+			name:         "install disk not found",
+			path:         "/configdata?uuid=1313-1414-1515",
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "failure selecting install disk of server 1313-1414-1515: none of 1 storage devices matches the install disk selector\n",
+		},
+		{
+			name:         "templated patch with variables outside of machine namespace",
+			path:         "/configdata?uuid=1414-1515-1616",
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: "config patch variables kube-system/variables-14 should be in the namespace of machine /machine-14\n",
+		},
+		{
+			name:         "templated patch with the values containing newlines",
+			path:         "/configdata?uuid=1616-1717-1818",
+			expectedCode: http.StatusOK,
+			expectedConfigs: append([]map[string]any{
+				{
+					"version": "v1alpha1",
+					"cluster": nil,
+					"machine": map[string]any{
+						"token":    "",
+						"type":     "",
+						"certSANs": []any{},
+						"kubelet": map[string]any{
+							"extraArgs": map[string]any{
+								"node-labels": "metal.sidero.dev/uuid=1616-1717-1818",
+							},
+						},
+						"network": map[string]any{
+							"hostname":    "node-16\n  type: controlplane",
+							"nameservers": []any{"10.0.0.53\"\n      - 10.0.0.54"},
+						},
+					},
+				},
+			}, sideroLinkCfgs...),
+		},
 	}
 
 	for _, test := range tests {
@@ -272,6 +438,204 @@ func TestMetadataService(t *testing.T) {
 	}
 }
 
+func TestMetadataServiceAppliedConfigPatches(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, infrav1.AddToScheme(scheme))
+	require.NoError(t, metalv1.AddToScheme(scheme))
+	require.NoError(t, capiv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(fixture9()...).
+		WithStatusSubresource(&infrav1.ServerBinding{}).
+		Build()
+
+	mux := http.NewServeMux()
+
+	require.NoError(t, metadata.RegisterServer(mux, fakeClient, record.NewFakeRecorder(100), "192.168.1.1", 8081, true))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/configdata?uuid=9999-0000-1111") //nolint:noctx
+	require.NoError(t, err)
+
+	resp.Body.Close() //nolint:errcheck
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var serverBinding infrav1.ServerBinding
+
+	require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Name: "9999-0000-1111"}, &serverBinding))
+
+	assert.Equal(t, []infrav1.AppliedConfigPatch{
+		{Name: "class-patch-9", Generation: 3},
+		{Name: "machine-patch-9", Generation: 1},
+		{Name: "rack-patch-9", Generation: 2},
+	}, serverBinding.Status.AppliedConfigPatches)
+}
+
+func TestMetadataServiceInstallDiskNotFound(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, infrav1.AddToScheme(scheme))
+	require.NoError(t, metalv1.AddToScheme(scheme))
+	require.NoError(t, capiv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(fixture13()...).
+		Build()
+
+	recorder := record.NewFakeRecorder(100)
+
+	mux := http.NewServeMux()
+
+	require.NoError(t, metadata.RegisterServer(mux, fakeClient, recorder, "192.168.1.1", 8081, true))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/configdata?uuid=1313-1414-1515") //nolint:noctx
+	require.NoError(t, err)
+
+	resp.Body.Close() //nolint:errcheck
+
+	require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning InstallDiskNotFound Failed to select the install disk: none of 1 storage devices matches the install disk selector.", <-recorder.Events)
+}
+
+func TestMetadataServiceAuthorization(t *testing.T) {
+	t.Parallel()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, infrav1.AddToScheme(scheme))
+	require.NoError(t, metalv1.AddToScheme(scheme))
+	require.NoError(t, capiv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	const config = `
+version: v1alpha1
+machine:
+  kubelet: {}
+`
+
+	tokenServer := fixtureSimple("aaaa-0000-0000", 10, config)
+
+	// the requests from the test client come from the loopback address
+	sideroLinkServer := fixtureSimple("bbbb-0000-0000", 11, config)
+	sideroLinkServer[0].(*infrav1.ServerBinding).Spec.SideroLink.NodeAddress = "127.0.0.1/32" //nolint:forcetypeassert
+
+	// the bootstrap data is not generated yet
+	pendingServer := fixtureSimple("cccc-0000-0000", 12, config)
+	pendingServer = pendingServer[:len(pendingServer)-1]
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append(append(tokenServer, sideroLinkServer...), pendingServer...)...).
+		WithStatusSubresource(&infrav1.ServerBinding{}).
+		Build()
+
+	recorder := record.NewFakeRecorder(100)
+
+	mux := http.NewServeMux()
+
+	require.NoError(t, metadata.RegisterServer(mux, fakeClient, recorder, "192.168.1.1", 8081, false))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+
+	issue := func(uuid, address string) string {
+		var serverBinding infrav1.ServerBinding
+
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: uuid}, &serverBinding))
+
+		token, err := configtoken.Issue(ctx, fakeClient, &serverBinding, address)
+		require.NoError(t, err)
+
+		return token
+	}
+
+	fetch := func(uuid, token string) int {
+		query := url.Values{"uuid": []string{uuid}}
+
+		if token != "" {
+			query.Set(configtoken.QueryParam, token)
+		}
+
+		resp, err := http.Get(srv.URL + "/configdata?" + query.Encode()) //nolint:noctx
+		require.NoError(t, err)
+
+		resp.Body.Close() //nolint:errcheck
+
+		return resp.StatusCode
+	}
+
+	token := issue("aaaa-0000-0000", "127.0.0.1")
+
+	assert.Equal(t, http.StatusForbidden, fetch("aaaa-0000-0000", ""))
+	assert.Equal(t, http.StatusForbidden, fetch("aaaa-0000-0000", "invalid"))
+	assert.Equal(t, http.StatusOK, fetch("aaaa-0000-0000", token))
+	assert.Equal(t, http.StatusForbidden, fetch("aaaa-0000-0000", token), "token replay should be denied")
+
+	var serverBinding infrav1.ServerBinding
+
+	// the used token is not replaced until the next boot
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "aaaa-0000-0000"}, &serverBinding))
+
+	_, err := configtoken.Issue(ctx, fakeClient, &serverBinding, "127.0.0.1")
+	assert.ErrorIs(t, err, configtoken.ErrAlreadyIssued)
+
+	_, err = configtoken.Issue(ctx, fakeClient, &serverBinding, "192.0.2.1")
+	assert.ErrorIs(t, err, configtoken.ErrAlreadyIssued)
+
+	// the new boot issues the new token
+	require.NoError(t, configtoken.Reset(ctx, fakeClient, &serverBinding))
+	assert.Equal(t, http.StatusOK, fetch("aaaa-0000-0000", issue("aaaa-0000-0000", "127.0.0.1")))
+
+	// the token is accepted only from the address it was issued to
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "aaaa-0000-0000"}, &serverBinding))
+	require.NoError(t, configtoken.Reset(ctx, fakeClient, &serverBinding))
+
+	token = issue("aaaa-0000-0000", "192.0.2.1")
+
+	assert.Equal(t, http.StatusForbidden, fetch("aaaa-0000-0000", token))
+
+	// the unused token can't be replaced by another client, but it is replaced on the retry from the same client
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "aaaa-0000-0000"}, &serverBinding))
+
+	_, err = configtoken.Issue(ctx, fakeClient, &serverBinding, "127.0.0.1")
+	assert.ErrorIs(t, err, configtoken.ErrAlreadyIssued)
+	assert.NoError(t, configtoken.Verify(&serverBinding, token, "192.0.2.1"))
+
+	retried := issue("aaaa-0000-0000", "192.0.2.1")
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "aaaa-0000-0000"}, &serverBinding))
+	assert.ErrorIs(t, configtoken.Verify(&serverBinding, token, "192.0.2.1"), configtoken.ErrInvalid)
+	assert.NoError(t, configtoken.Verify(&serverBinding, retried, "192.0.2.1"))
+
+	assert.Equal(t, http.StatusOK, fetch("bbbb-0000-0000", ""))
+
+	// the token is not used until the configuration is served
+	token = issue("cccc-0000-0000", "127.0.0.1")
+
+	assert.Equal(t, http.StatusNotFound, fetch("cccc-0000-0000", token))
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "cccc-0000-0000"}, &serverBinding))
+	assert.NoError(t, configtoken.Verify(&serverBinding, token, "127.0.0.1"))
+
+	assert.Len(t, recorder.Events, 4)
+}
+
 func parseYAMLDocs(t *testing.T, data []byte) []map[string]any {
 	t.Helper()
 
@@ -0,0 +1,535 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	talosconfig "github.com/siderolabs/talos/pkg/machinery/config"
+	"github.com/siderolabs/talos/pkg/machinery/config/config"
+	"github.com/siderolabs/talos/pkg/machinery/config/configloader"
+	"github.com/siderolabs/talos/pkg/machinery/config/container"
+	"github.com/siderolabs/talos/pkg/machinery/config/encoder"
+	blocktypes "github.com/siderolabs/talos/pkg/machinery/config/types/block"
+	critypes "github.com/siderolabs/talos/pkg/machinery/config/types/cri"
+	hardwaretypes "github.com/siderolabs/talos/pkg/machinery/config/types/hardware"
+	"github.com/siderolabs/talos/pkg/machinery/config/types/meta"
+	networktypes "github.com/siderolabs/talos/pkg/machinery/config/types/network"
+	runtimetypes "github.com/siderolabs/talos/pkg/machinery/config/types/runtime"
+	"github.com/siderolabs/talos/pkg/machinery/config/types/runtime/extensions"
+	securitytypes "github.com/siderolabs/talos/pkg/machinery/config/types/security"
+	siderolinktypes "github.com/siderolabs/talos/pkg/machinery/config/types/siderolink"
+	"github.com/siderolabs/talos/pkg/machinery/config/types/v1alpha1"
+	"github.com/siderolabs/talos/pkg/machinery/config/validation"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+// PreviewSubresource is the Server subresource the users should be allowed to get to preview the machine configuration.
+const PreviewSubresource = "configpreview"
+
+// redactedValue replaces the secrets in the previewed machine configuration.
+const redactedValue = "******"
+
+// previewMode implements validation.RuntimeMode for the Talos runtime modes the configuration is validated for.
+type previewMode string
+
+const (
+	previewModeMetal     previewMode = "metal"
+	previewModeCloud     previewMode = "cloud"
+	previewModeContainer previewMode = "container"
+)
+
+func (mode previewMode) String() string {
+	return string(mode)
+}
+
+func (mode previewMode) RequiresInstall() bool {
+	return mode == previewModeMetal
+}
+
+func (mode previewMode) InContainer() bool {
+	return mode == previewModeContainer
+}
+
+// PreviewResponse is returned by the machine configuration preview endpoint.
+type PreviewResponse struct {
+	// Config is the machine configuration with the secrets redacted.
+	Config string `json:"config,omitempty"`
+	// AppliedConfigPatches lists the MachineConfigPatches applied to the configuration.
+	AppliedConfigPatches []infrav1.AppliedConfigPatch `json:"appliedConfigPatches,omitempty"`
+	// Valid is true if the configuration passed the Talos validation.
+	Valid bool `json:"valid"`
+	// Warnings are the Talos validation warnings.
+	Warnings []string `json:"warnings,omitempty"`
+	// Errors are the Talos validation errors.
+	Errors []string `json:"errors,omitempty"`
+}
+
+type previewServer struct {
+	metadataConfigs
+
+	clientset kubernetes.Interface
+}
+
+// NewPreviewHandler returns the handler of the machine configuration preview endpoint.
+//
+// The endpoint renders the machine configuration the same way as the configuration endpoint, but it redacts the secrets
+// and validates the configuration instead of serving it to the server.
+// The requests are authenticated with the Kubernetes bearer tokens, and the users should be allowed to get the
+// configpreview subresource of the Server.
+// The bearer tokens should not be sent in cleartext, so the handler should be served over TLS, and the plain HTTP requests are rejected.
+func NewPreviewHandler(k8sClient runtimeclient.Client, clientset kubernetes.Interface, apiEndpoint string, apiPort int) http.Handler {
+	ps := &previewServer{
+		metadataConfigs: metadataConfigs{
+			client:      k8sClient,
+			apiEndpoint: apiEndpoint,
+			apiPort:     apiPort,
+		},
+		clientset: clientset,
+	}
+
+	return http.HandlerFunc(ps.PreviewConfig)
+}
+
+func (ps *previewServer) PreviewConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if r.TLS == nil {
+		throwError(w, errorWithCode{http.StatusForbidden, errors.New("config preview requests are accepted over TLS only")})
+
+		return
+	}
+
+	vals := r.URL.Query()
+
+	uuid := vals.Get("uuid")
+	if uuid == "" {
+		throwError(w, errorWithCode{http.StatusBadRequest, errors.New("received config preview request with empty uuid")})
+
+		return
+	}
+
+	mode := previewMode(vals.Get("mode"))
+	switch mode {
+	case "":
+		mode = previewModeMetal
+	case previewModeMetal, previewModeCloud, previewModeContainer:
+	default:
+		throwError(w, errorWithCode{http.StatusBadRequest, fmt.Errorf("unsupported validation mode %q", mode)})
+
+		return
+	}
+
+	if ewc := ps.authorizePreview(r, uuid); ewc.errorObj != nil {
+		throwError(w, ewc)
+
+		return
+	}
+
+	metalMachine, serverBinding, ewc := ps.findMetalMachineServerBinding(ctx, uuid)
+	if ewc.errorObj != nil {
+		throwError(w, ewc)
+
+		return
+	}
+
+	data, machineConfigPatches, ewc := ps.renderConfig(ctx, uuid, &metalMachine, &serverBinding)
+	if ewc.errorObj != nil {
+		throwError(w, ewc)
+
+		return
+	}
+
+	var resp PreviewResponse
+
+	for _, patch := range machineConfigPatches {
+		resp.AppliedConfigPatches = append(resp.AppliedConfigPatches, infrav1.AppliedConfigPatch{
+			Name:       patch.Name,
+			Generation: patch.Generation,
+		})
+	}
+
+	// the configuration which can't be loaded can't be redacted either, so only the error is returned
+	cfg, err := configloader.NewFromBytes(data)
+	if err != nil {
+		resp.Errors = []string{fmt.Sprintf("failure loading machine configuration: %s", err)}
+
+		writePreview(w, &resp)
+
+		return
+	}
+
+	var opts []validation.Option
+
+	if vals.Get("strict") == "true" {
+		opts = append(opts, validation.WithStrict())
+	}
+
+	resp.Warnings, err = cfg.Validate(mode, opts...)
+	if err != nil {
+		resp.Errors = flattenErrors(err)
+	}
+
+	resp.Valid = err == nil
+
+	redacted, err := redactConfig(cfg)
+	if err != nil {
+		throwError(w, errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure encoding machine configuration: %w", err)})
+
+		return
+	}
+
+	resp.Config = string(redacted)
+
+	writePreview(w, &resp)
+}
+
+// authorizePreview authenticates the request bearer token and checks that the user is allowed to preview the server configuration.
+func (ps *previewServer) authorizePreview(r *http.Request, uuid string) errorWithCode {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return errorWithCode{http.StatusUnauthorized, errors.New("config preview request is missing the bearer token")}
+	}
+
+	tokenReview, err := ps.clientset.AuthenticationV1().TokenReviews().Create(r.Context(), &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token: token,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure reviewing config preview token: %w", err)}
+	}
+
+	if !tokenReview.Status.Authenticated {
+		return errorWithCode{http.StatusUnauthorized, errors.New("config preview request token is not valid")}
+	}
+
+	user := tokenReview.Status.User
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	accessReview, err := ps.clientset.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:        "get",
+				Group:       metalv1.GroupVersion.Group,
+				Resource:    "servers",
+				Subresource: PreviewSubresource,
+				Name:        uuid,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure reviewing config preview access: %w", err)}
+	}
+
+	if !accessReview.Status.Allowed {
+		return errorWithCode{
+			http.StatusForbidden,
+			fmt.Errorf("user %q is not allowed to get servers/%s of server %q", user.Username, PreviewSubresource, uuid),
+		}
+	}
+
+	log.Printf("user %q previews machine configuration of %q", user.Username, uuid)
+
+	return errorWithCode{}
+}
+
+// redactConfig returns the encoded machine configuration with the secrets redacted.
+//
+// Besides the secrets redacted by Talos (the CA keys, the tokens and the encryption secrets, and the secrets in the
+// multi-document registry, WireGuard and SideroLink configs), the following fields are redacted:
+//
+//   - machine.registries.config.*.auth: password, auth and identityToken;
+//   - machine.registries.config.*.tls.clientIdentity.key;
+//   - machine.network.interfaces[].wireguard.privateKey;
+//   - machine.systemDiskEncryption.*.keys[].static.passphrase;
+//   - machine.files[].content;
+//   - the values of machine.env, cluster.apiServer.env, cluster.controllerManager.env and cluster.scheduler.env;
+//   - the values of cluster.extraManifestHeaders;
+//   - cluster.inlineManifests[].contents;
+//   - the values of ExtensionServiceConfig environment and configFiles[].content;
+//   - the values of EnvironmentConfig variables;
+//   - VolumeConfig, UserVolumeConfig, RawVolumeConfig and SwapVolumeConfig encryption.keys[].static.passphrase;
+//   - HCloudVIPConfig apiToken.
+//
+// The documents of the other kinds might contain the secrets which are not known to Sidero,
+// so only their kind and name are returned.
+func redactConfig(cfg talosconfig.Provider) ([]byte, error) {
+	docs := cfg.RedactSecrets(redactedValue).Documents()
+
+	for i, doc := range docs {
+		docs[i] = redactDocument(doc)
+	}
+
+	redacted, err := container.New(docs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return redacted.EncodeBytes(encoder.WithComments(encoder.CommentsDisabled))
+}
+
+//nolint:gocyclo,cyclop
+func redactDocument(doc config.Document) config.Document {
+	switch d := doc.Clone().(type) {
+	case *v1alpha1.Config:
+		redactV1Alpha1(d)
+
+		return d
+	case *extensions.ServiceConfigV1Alpha1:
+		for i, variable := range d.ServiceEnvironment {
+			if name, _, ok := strings.Cut(variable, "="); ok {
+				d.ServiceEnvironment[i] = name + "=" + redactedValue
+			}
+		}
+
+		for i := range d.ServiceConfigFiles {
+			redactString(&d.ServiceConfigFiles[i].ConfigFileContent)
+		}
+
+		return d
+	case *runtimetypes.EnvironmentV1Alpha1:
+		redactMap(d.EnvironmentVariables)
+
+		return d
+	case *blocktypes.VolumeConfigV1Alpha1:
+		redactEncryption(&d.EncryptionSpec)
+
+		return d
+	case *blocktypes.UserVolumeConfigV1Alpha1:
+		redactEncryption(&d.EncryptionSpec)
+
+		return d
+	case *blocktypes.RawVolumeConfigV1Alpha1:
+		redactEncryption(&d.EncryptionSpec)
+
+		return d
+	case *blocktypes.SwapVolumeConfigV1Alpha1:
+		redactEncryption(&d.EncryptionSpec)
+
+		return d
+	case *networktypes.HCloudVIPConfigV1Alpha1:
+		redactString(&d.APIToken)
+
+		return d
+	case
+		// the secrets are redacted by Talos
+		*critypes.RegistryAuthConfigV1Alpha1,
+		*critypes.RegistryTLSConfigV1Alpha1,
+		*networktypes.WireguardConfigV1Alpha1,
+		*siderolinktypes.ConfigV1Alpha1,
+		// no secrets
+		*critypes.RegistryMirrorConfigV1Alpha1,
+		*blocktypes.ExistingVolumeConfigV1Alpha1,
+		*blocktypes.ExternalVolumeConfigV1Alpha1,
+		*blocktypes.ZswapConfigV1Alpha1,
+		*hardwaretypes.PCIDriverRebindConfigV1Alpha1,
+		*networktypes.BlackholeRouteConfigV1Alpha1,
+		*networktypes.BondConfigV1Alpha1,
+		*networktypes.BridgeConfigV1Alpha1,
+		*networktypes.DefaultActionConfigV1Alpha1,
+		*networktypes.DHCPv4ConfigV1Alpha1,
+		*networktypes.DHCPv6ConfigV1Alpha1,
+		*networktypes.DummyLinkConfigV1Alpha1,
+		*networktypes.EthernetConfigV1Alpha1,
+		*networktypes.HostnameConfigV1Alpha1,
+		*networktypes.KubeSpanConfigV1Alpha1,
+		*networktypes.KubespanEndpointsConfigV1Alpha1,
+		*networktypes.Layer2VIPConfigV1Alpha1,
+		*networktypes.LinkAliasConfigV1Alpha1,
+		*networktypes.LinkConfigV1Alpha1,
+		*networktypes.ResolverConfigV1Alpha1,
+		*networktypes.RoutingRuleConfigV1Alpha1,
+		*networktypes.RuleConfigV1Alpha1,
+		*networktypes.StaticHostConfigV1Alpha1,
+		*networktypes.TCPProbeConfigV1Alpha1,
+		*networktypes.TimeSyncConfigV1Alpha1,
+		*networktypes.VLANConfigV1Alpha1,
+		*networktypes.VRFConfigV1Alpha1,
+		*runtimetypes.EventSinkV1Alpha1,
+		*runtimetypes.KmsgLogV1Alpha1,
+		*runtimetypes.OOMV1Alpha1,
+		*runtimetypes.WatchdogTimerV1Alpha1,
+		*securitytypes.ImageVerificationConfigV1Alpha1,
+		*securitytypes.TrustedRootsConfigV1Alpha1:
+		return d
+	default:
+		hidden := &hiddenDocument{
+			Meta: meta.Meta{
+				MetaAPIVersion: doc.APIVersion(),
+				MetaKind:       doc.Kind(),
+			},
+		}
+
+		if named, ok := doc.(config.NamedDocument); ok {
+			hidden.MetaName = named.Name()
+		}
+
+		return hidden
+	}
+}
+
+func redactV1Alpha1(cfg *v1alpha1.Config) {
+	if machine := cfg.MachineConfig; machine != nil {
+		for _, registry := range machine.MachineRegistries.RegistryConfig {
+			if registry == nil {
+				continue
+			}
+
+			if auth := registry.RegistryAuth; auth != nil {
+				redactString(&auth.RegistryPassword)
+				redactString(&auth.RegistryAuth)
+				redactString(&auth.RegistryIdentityToken)
+			}
+
+			if tls := registry.RegistryTLS; tls != nil && tls.TLSClientIdentity != nil && len(tls.TLSClientIdentity.Key) > 0 {
+				tls.TLSClientIdentity.Key = []byte(redactedValue)
+			}
+		}
+
+		if machine.MachineNetwork != nil {
+			for _, device := range machine.MachineNetwork.NetworkInterfaces {
+				if device != nil && device.DeviceWireguardConfig != nil {
+					redactString(&device.DeviceWireguardConfig.WireguardPrivateKey)
+				}
+			}
+		}
+
+		if encryption := machine.MachineSystemDiskEncryption; encryption != nil {
+			for _, partition := range []*v1alpha1.EncryptionConfig{encryption.StatePartition, encryption.EphemeralPartition} {
+				if partition == nil {
+					continue
+				}
+
+				for _, key := range partition.EncryptionKeys {
+					if key != nil && key.KeyStatic != nil {
+						redactString(&key.KeyStatic.KeyData)
+					}
+				}
+			}
+		}
+
+		for _, file := range machine.MachineFiles {
+			if file != nil {
+				redactString(&file.FileContent)
+			}
+		}
+
+		redactMap(machine.MachineEnv)
+	}
+
+	if cluster := cfg.ClusterConfig; cluster != nil {
+		for i := range cluster.ClusterInlineManifests {
+			redactString(&cluster.ClusterInlineManifests[i].InlineManifestContents)
+		}
+
+		redactMap(cluster.ExtraManifestHeaders)
+
+		if cluster.APIServerConfig != nil {
+			redactMap(cluster.APIServerConfig.EnvConfig)
+		}
+
+		if cluster.ControllerManagerConfig != nil {
+			redactMap(cluster.ControllerManagerConfig.EnvConfig)
+		}
+
+		if cluster.SchedulerConfig != nil {
+			redactMap(cluster.SchedulerConfig.EnvConfig)
+		}
+	}
+}
+
+func redactEncryption(spec *blocktypes.EncryptionSpec) {
+	for i := range spec.EncryptionKeys {
+		if spec.EncryptionKeys[i].KeyStatic != nil {
+			redactString(&spec.EncryptionKeys[i].KeyStatic.KeyData)
+		}
+	}
+}
+
+func redactMap(values map[string]string) {
+	for key, value := range values {
+		redactString(&value)
+
+		values[key] = value
+	}
+}
+
+func redactString(value *string) {
+	if *value != "" {
+		*value = redactedValue
+	}
+}
+
+// hiddenDocument replaces the documents which are not known to be redacted in the previewed machine configuration.
+type hiddenDocument struct {
+	meta.Meta `yaml:",inline"`
+
+	MetaName string `yaml:"name,omitempty"`
+}
+
+// Clone implements config.Document.
+func (doc *hiddenDocument) Clone() config.Document {
+	clone := *doc
+
+	return &clone
+}
+
+// Name implements config.NamedDocument.
+func (doc *hiddenDocument) Name() string {
+	return doc.MetaName
+}
+
+func writePreview(w http.ResponseWriter, resp *PreviewResponse) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("failed to write config preview: %v", err)
+	}
+}
+
+// flattenErrors returns the messages of the validation errors, nested multierrors are flattened.
+func flattenErrors(err error) []string {
+	var multiErr *multierror.Error
+
+	if !errors.As(err, &multiErr) {
+		return []string{err.Error()}
+	}
+
+	// keep the context of the wrapped multierror, e.g. "v1alpha1.Config: "
+	prefix := strings.TrimSuffix(err.Error(), multiErr.Error())
+
+	var messages []string
+
+	for _, wrapped := range multiErr.WrappedErrors() {
+		for _, message := range flattenErrors(wrapped) {
+			messages = append(messages, prefix+message)
+		}
+	}
+
+	return messages
+}
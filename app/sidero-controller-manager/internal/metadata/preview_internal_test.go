@@ -0,0 +1,51 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metadata
+
+import (
+	"testing"
+
+	"github.com/siderolabs/talos/pkg/machinery/config/config"
+	"github.com/siderolabs/talos/pkg/machinery/config/types/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type unknownDocument struct {
+	meta.Meta `yaml:",inline"`
+
+	MetaName string `yaml:"name"`
+	Secret   string `yaml:"secret"`
+}
+
+func (doc *unknownDocument) Clone() config.Document {
+	clone := *doc
+
+	return &clone
+}
+
+func (doc *unknownDocument) Name() string {
+	return doc.MetaName
+}
+
+func TestRedactDocumentUnknown(t *testing.T) {
+	doc := &unknownDocument{
+		Meta:     meta.Meta{MetaAPIVersion: "v1alpha1", MetaKind: "UnknownConfig"},
+		MetaName: "unknown",
+		Secret:   "unknownsecret",
+	}
+
+	redacted := redactDocument(doc)
+
+	hidden, ok := redacted.(*hiddenDocument)
+	require.True(t, ok, "unexpected document type %T", redacted)
+
+	assert.Equal(t, "v1alpha1", hidden.APIVersion())
+	assert.Equal(t, "UnknownConfig", hidden.Kind())
+	assert.Equal(t, "unknown", hidden.Name())
+
+	// the original document is not modified
+	assert.Equal(t, "unknownsecret", doc.Secret)
+}
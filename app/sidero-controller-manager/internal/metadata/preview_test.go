@@ -0,0 +1,168 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metadata_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/metadata"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/siderolink"
+)
+
+func TestPreviewConfig(t *testing.T) {
+	oldSideroCfg := siderolink.Cfg
+	siderolink.Cfg = siderolink.Config{
+		ServerAddress: netip.PrefixFrom(netip.AddrFrom4([4]byte{192, 168, 1, 1}), 32),
+	}
+	// restore config after test
+	t.Cleanup(func() {
+		siderolink.Cfg = oldSideroCfg
+	})
+
+	scheme := k8sruntime.NewScheme()
+	require.NoError(t, infrav1.AddToScheme(scheme))
+	require.NoError(t, metalv1.AddToScheme(scheme))
+	require.NoError(t, capiv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(fixture11()...).
+		Build()
+
+	clientset := kubefake.NewClientset()
+
+	// the token is the name of the user, only the "admin" user is allowed to preview the configuration
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview) //nolint:forcetypeassert,errcheck
+
+		review.Status.Authenticated = review.Spec.Token != "invalid"
+		review.Status.User.Username = review.Spec.Token
+
+		return true, review, nil
+	})
+
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, k8sruntime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview) //nolint:forcetypeassert,errcheck
+
+		attrs := review.Spec.ResourceAttributes
+
+		review.Status.Allowed = review.Spec.User == "admin" &&
+			attrs.Group == metalv1.GroupVersion.Group &&
+			attrs.Resource == "servers" &&
+			attrs.Subresource == metadata.PreviewSubresource &&
+			attrs.Verb == "get"
+
+		return true, review, nil
+	})
+
+	handler := metadata.NewPreviewHandler(fakeClient, clientset, "192.168.1.1", 8081)
+
+	srv := httptest.NewTLSServer(handler)
+	t.Cleanup(srv.Close)
+
+	plainSrv := httptest.NewServer(handler)
+	t.Cleanup(plainSrv.Close)
+
+	request := func(t *testing.T, srv *httptest.Server, query, token string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL+"/configpreview?"+query, nil)
+		require.NoError(t, err)
+
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := srv.Client().Do(req)
+		require.NoError(t, err)
+
+		t.Cleanup(func() { resp.Body.Close() }) //nolint:errcheck
+
+		return resp
+	}
+
+	preview := func(t *testing.T, query, token string) *http.Response {
+		t.Helper()
+
+		return request(t, srv, query, token)
+	}
+
+	t.Run("plain HTTP", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, request(t, plainSrv, "uuid=1111-0000-1111", "admin").StatusCode)
+	})
+
+	for _, tt := range []struct {
+		name     string
+		query    string
+		token    string
+		expected int
+	}{
+		{name: "no token", query: "uuid=1111-0000-1111", expected: http.StatusUnauthorized},
+		{name: "invalid token", query: "uuid=1111-0000-1111", token: "invalid", expected: http.StatusUnauthorized},
+		{name: "not allowed", query: "uuid=1111-0000-1111", token: "viewer", expected: http.StatusForbidden},
+		{name: "invalid mode", query: "uuid=1111-0000-1111&mode=vm", token: "admin", expected: http.StatusBadRequest},
+		{name: "not found", query: "uuid=0000-0000-0000", token: "admin", expected: http.StatusNotFound},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, preview(t, tt.query, tt.token).StatusCode)
+		})
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		resp := preview(t, "uuid=1111-0000-1111&mode=metal", "admin")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result metadata.PreviewResponse
+
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+		for _, secret := range []string{
+			"supersecret",
+			"registrypassword",
+			"cmVnaXN0cnlhdXRo",
+			"registrytoken",
+			"cmVnaXN0cnlrZXk=",
+			"wireguardkey",
+			"filecontent",
+			"manifestcontents",
+			"proxypassword",
+			"manifesttoken",
+			"tailscalekey",
+			"extensioncontent",
+			"volumepassphrase",
+		} {
+			assert.NotContains(t, result.Config, secret)
+		}
+
+		assert.Contains(t, result.Config, "username: preview")
+		assert.Contains(t, result.Config, "registry.example.com")
+		assert.Contains(t, result.Config, "hostname: preview11")
+		assert.Contains(t, result.Config, "SideroLinkConfig")
+		assert.Contains(t, result.Config, "TS_AUTHKEY=******")
+		assert.Contains(t, result.Config, "mountPath: /etc/tailscale/config")
+
+		// the configuration lacks the cluster settings
+		assert.False(t, result.Valid)
+		assert.NotEmpty(t, result.Errors)
+	})
+}
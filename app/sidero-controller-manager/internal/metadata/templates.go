@@ -0,0 +1,404 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/siderolabs/sidero/app/caps-controller-manager/api/v1alpha3"
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+// templateData is available in the config patch templates.
+type templateData struct {
+	Server    templateServer
+	Addresses []string
+	Cluster   templateObject
+	Machine   templateObject
+	Vars      map[string]string
+}
+
+type templateServer struct {
+	Name        string
+	Hostname    string
+	Labels      map[string]string
+	Annotations map[string]string
+	Hardware    *metalv1.HardwareInformation
+}
+
+type templateObject struct {
+	Name      string
+	Namespace string
+}
+
+var templateFuncs = template.FuncMap{
+	"default": func(def, value any) any {
+		if value == nil || value == "" {
+			return def
+		}
+
+		return value
+	},
+	"required": func(msg string, value any) (any, error) {
+		if value == nil || value == "" {
+			return nil, errors.New(msg)
+		}
+
+		return value, nil
+	},
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"replace":   strings.ReplaceAll,
+	"trimSpace": strings.TrimSpace,
+	"split":     strings.Split,
+	"join": func(sep string, elems []string) string {
+		return strings.Join(elems, sep)
+	},
+	"toJson": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+
+		return string(data), err
+	},
+}
+
+// newTemplateData collects the data for the config patch templates.
+//
+// The variables of the server override the variables of the server class.
+func (m *metadataConfigs) newTemplateData(
+	ctx context.Context,
+	serverObj *metalv1.Server,
+	serverClassObj *metalv1.ServerClass,
+	serverBinding *infrav1.ServerBinding,
+	ownerMachine *capiv1.Machine,
+) (*templateData, errorWithCode) {
+	data := &templateData{
+		Server: templateServer{
+			Name:        serverObj.Name,
+			Hostname:    serverObj.Spec.Hostname,
+			Labels:      serverObj.Labels,
+			Annotations: serverObj.Annotations,
+			Hardware:    serverObj.Spec.Hardware,
+		},
+		Addresses: serverBinding.Spec.Addresses,
+		Cluster: templateObject{
+			Name:      ownerMachine.Spec.ClusterName,
+			Namespace: ownerMachine.Namespace,
+		},
+		Machine: templateObject{
+			Name:      ownerMachine.Name,
+			Namespace: ownerMachine.Namespace,
+		},
+		Vars: map[string]string{},
+	}
+
+	for _, templating := range []*metalv1.PatchTemplating{serverClassObj.Spec.PatchTemplating, serverObj.Spec.PatchTemplating} {
+		if templating == nil || templating.VariablesRef == nil {
+			continue
+		}
+
+		var configMap corev1.ConfigMap
+
+		key := types.NamespacedName{Namespace: templating.VariablesRef.Namespace, Name: templating.VariablesRef.Name}
+		if key.Namespace == "" {
+			key.Namespace = ownerMachine.Namespace
+		}
+
+		// the variables are read with the controller permissions, so they are confined to the namespace of the cluster
+		if key.Namespace != ownerMachine.Namespace {
+			return nil, errorWithCode{
+				http.StatusUnprocessableEntity,
+				fmt.Errorf("config patch variables %s should be in the namespace of machine %s/%s", key, ownerMachine.Namespace, ownerMachine.Name),
+			}
+		}
+
+		if err := m.client.Get(ctx, key, &configMap); err != nil {
+			code := http.StatusInternalServerError
+			if apierrors.IsNotFound(err) {
+				code = http.StatusUnprocessableEntity
+			}
+
+			return nil, errorWithCode{code, fmt.Errorf("failure fetching config patch variables %s: %w", key, err)}
+		}
+
+		maps.Copy(data.Vars, configMap.Data)
+	}
+
+	return data, errorWithCode{}
+}
+
+// renderPatches renders the config patches as Go templates.
+//
+// In the RFC 6902 patches only the path and the string values are rendered, and the strategic merge patches are rendered
+// with the values substituted into the parsed YAML, so that the rendered values don't need to be escaped.
+func renderPatches(data *templateData, source string, patches []metalv1.ConfigPatches, strategicPatches []string) ([]metalv1.ConfigPatches, []string, errorWithCode) {
+	renderedPatches := make([]metalv1.ConfigPatches, 0, len(patches))
+
+	for i, patch := range patches {
+		path, err := renderTemplate(fmt.Sprintf("%s configPatches[%d].path", source, i), patch.Path, data)
+		if err != nil {
+			return nil, nil, templateError(err)
+		}
+
+		rendered := metalv1.ConfigPatches{
+			Op:   patch.Op,
+			Path: path,
+		}
+
+		if patch.Value.Raw != nil {
+			var value any
+
+			if err = json.Unmarshal(patch.Value.Raw, &value); err != nil {
+				return nil, nil, errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure decoding %s configPatches[%d].value: %w", source, i, err)}
+			}
+
+			if value, err = renderValue(fmt.Sprintf("%s configPatches[%d].value", source, i), value, data); err != nil {
+				return nil, nil, templateError(err)
+			}
+
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, nil, errorWithCode{http.StatusInternalServerError, fmt.Errorf("failure encoding %s configPatches[%d].value: %w", source, i, err)}
+			}
+
+			rendered.Value = apiextensions.JSON{Raw: raw}
+		}
+
+		renderedPatches = append(renderedPatches, rendered)
+	}
+
+	renderedStrategicPatches := make([]string, 0, len(strategicPatches))
+
+	for i, patch := range strategicPatches {
+		rendered, err := renderStrategicPatch(fmt.Sprintf("%s strategicPatches[%d]", source, i), patch, data)
+		if err != nil {
+			return nil, nil, templateError(err)
+		}
+
+		renderedStrategicPatches = append(renderedStrategicPatches, rendered)
+	}
+
+	return renderedPatches, renderedStrategicPatches, errorWithCode{}
+}
+
+// renderValue renders the strings in the decoded JSON value.
+func renderValue(name string, value any, data *templateData) (any, error) {
+	var err error
+
+	switch v := value.(type) {
+	case string:
+		return renderTemplate(name, v, data)
+	case []any:
+		for i := range v {
+			if v[i], err = renderValue(fmt.Sprintf("%s[%d]", name, i), v[i], data); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		for key := range v {
+			if v[key], err = renderValue(name+"."+key, v[key], data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return value, nil
+}
+
+// parseTemplate parses the config patch template.
+//
+// The missing map keys (variables, labels, annotations) are rendered as empty strings, so that `default` and `required` can handle them,
+// while the missing fields are still an error.
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+func renderTemplate(name, text string, data *templateData) (string, error) {
+	// skip parsing the strings which are not templates
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// placeholderFunc is the name of the function which replaces the output of the strategic merge patch template actions.
+const placeholderFunc = "sideroPlaceholder"
+
+// renderStrategicPatch renders the strategic merge patch template.
+//
+// The outputs of the template actions are rendered as placeholders, and the rendered values are substituted
+// into the scalars of the parsed YAML, so that a value is always a part of a single scalar, e.g. a value with a newline
+// can't add keys to the patch.
+func renderStrategicPatch(name, text string, data *templateData) (string, error) {
+	// skip parsing the strings which are not templates
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	tmpl, err := parseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	prefix := "sidero-" + strings.ToLower(rand.Text()) + "-"
+
+	var values []string
+
+	tmpl.Funcs(template.FuncMap{
+		placeholderFunc: func(value any) string {
+			values = append(values, fmt.Sprint(value))
+
+			return fmt.Sprintf("%s%d-", prefix, len(values)-1)
+		},
+	})
+
+	for _, t := range tmpl.Templates() {
+		addPlaceholders(t.Tree.Root)
+	}
+
+	var buf bytes.Buffer
+
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	if len(values) == 0 {
+		return buf.String(), nil
+	}
+
+	oldnew := make([]string, 0, len(values)*2)
+
+	for i, value := range values {
+		oldnew = append(oldnew, fmt.Sprintf("%s%d-", prefix, i), value)
+	}
+
+	replacer := strings.NewReplacer(oldnew...)
+
+	var out bytes.Buffer
+
+	decoder := yaml.NewDecoder(&buf)
+
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+
+	for {
+		var doc yaml.Node
+
+		if err = decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return "", fmt.Errorf("failure parsing rendered %s: %w", name, err)
+		}
+
+		substitutePlaceholders(&doc, prefix, replacer)
+
+		if err = encoder.Encode(&doc); err != nil {
+			return "", fmt.Errorf("failure encoding rendered %s: %w", name, err)
+		}
+	}
+
+	if err = encoder.Close(); err != nil {
+		return "", fmt.Errorf("failure encoding rendered %s: %w", name, err)
+	}
+
+	return out.String(), nil
+}
+
+// addPlaceholders pipes the output of the template actions to the placeholder function.
+func addPlaceholders(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			addPlaceholders(child)
+		}
+	case *parse.ActionNode:
+		// the variable declarations have no output
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(placeholderFunc).SetPos(n.Pos)},
+		})
+	case *parse.IfNode:
+		addPlaceholders(n.List)
+		addPlaceholders(n.ElseList)
+	case *parse.RangeNode:
+		addPlaceholders(n.List)
+		addPlaceholders(n.ElseList)
+	case *parse.WithNode:
+		addPlaceholders(n.List)
+		addPlaceholders(n.ElseList)
+	}
+}
+
+// substitutePlaceholders replaces the placeholders with the rendered values in the scalars of the YAML node.
+func substitutePlaceholders(node *yaml.Node, prefix string, replacer *strings.Replacer) {
+	if node.Kind == yaml.ScalarNode && strings.Contains(node.Value, prefix) {
+		node.Value = replacer.Replace(node.Value)
+
+		// the type of the plain scalars is resolved from the rendered value, e.g. a port number is rendered as an integer
+		if node.Style&(yaml.TaggedStyle|yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			node.Tag = ""
+		}
+	}
+
+	// the comments are not a part of the patch, so they are dropped instead of rendering the values into them
+	for _, comment := range []*string{&node.HeadComment, &node.LineComment, &node.FootComment} {
+		if strings.Contains(*comment, prefix) {
+			*comment = ""
+		}
+	}
+
+	// the aliased nodes are substituted where they are defined
+	if node.Kind == yaml.AliasNode {
+		return
+	}
+
+	for _, child := range node.Content {
+		substitutePlaceholders(child, prefix, replacer)
+	}
+}
+
+func templateError(err error) errorWithCode {
+	return errorWithCode{http.StatusUnprocessableEntity, fmt.Errorf("failure rendering config patch template: %w", err)}
+}
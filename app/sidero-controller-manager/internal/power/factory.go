@@ -14,12 +14,38 @@ import (
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/power/api"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/power/ipmi"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/power/metal"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/power/redfish"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/constants"
 )
 
 // NewManagementClient builds ManagementClient from the server spec.
 func NewManagementClient(ctx context.Context, client client.Client, spec *metalv1.ServerSpec) (metal.ManagementClient, error) {
 	switch {
+	case spec.Redfish != nil:
+		var err error
+
+		redfishSpec := *spec.Redfish
+
+		if redfishSpec.User == "" {
+			redfishSpec.User, err = redfishSpec.UserFrom.Resolve(ctx, client)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if redfishSpec.Pass == "" {
+			redfishSpec.Pass, err = redfishSpec.PassFrom.Resolve(ctx, client)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if redfishSpec.User == "" || redfishSpec.Pass == "" {
+			// no username and password, Redfish information is not fully populated yet
+			return fakeClient{}, nil
+		}
+
+		return redfish.NewClient(redfishSpec)
 	case spec.BMC != nil:
 		var err error
 
@@ -0,0 +1,292 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package redfish provides metal machine management via Redfish API.
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+// Link to the Redfish spec: https://www.dmtf.org/standards/redfish
+// Only the subset of ComputerSystem resource required for power and boot management is used.
+
+const (
+	systemsPath = "/redfish/v1/Systems"
+
+	requestTimeout = 30 * time.Second
+)
+
+// Reset types as defined by the ComputerSystem.Reset action.
+const (
+	resetOn           = "On"
+	resetForceOff     = "ForceOff"
+	resetForceRestart = "ForceRestart"
+	resetPowerCycle   = "PowerCycle"
+)
+
+// Client provides management via Redfish API.
+type Client struct {
+	httpClient *http.Client
+
+	endpoint *url.URL
+	user     string
+	pass     string
+	systemID string
+
+	systemPath string
+}
+
+type odataID struct {
+	ID string `json:"@odata.id"`
+}
+
+type systemCollection struct {
+	Members []odataID `json:"Members"`
+}
+
+type resetAction struct {
+	Target              string   `json:"target"`
+	AllowableResetTypes []string `json:"ResetType@Redfish.AllowableValues"`
+}
+
+type computerSystem struct {
+	PowerState string `json:"PowerState"`
+	Actions    struct {
+		Reset resetAction `json:"#ComputerSystem.Reset"`
+	} `json:"Actions"`
+}
+
+// NewClient returns new Redfish client to manage metal machine.
+func NewClient(spec metalv1.Redfish) (*Client, error) {
+	endpoint := spec.Endpoint
+
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing Redfish endpoint %q: %w", spec.Endpoint, err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert,errcheck
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: spec.InsecureSkipVerify, //nolint:gosec
+	}
+
+	return &Client{
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+		endpoint: u,
+		user:     spec.User,
+		pass:     spec.Pass,
+		systemID: spec.SystemID,
+	}, nil
+}
+
+// Close the client.
+func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
+
+	return nil
+}
+
+// PowerOn will power on a given machine.
+func (c *Client) PowerOn() error {
+	return c.reset(resetOn)
+}
+
+// PowerOff will power off a given machine.
+func (c *Client) PowerOff() error {
+	return c.reset(resetForceOff)
+}
+
+// PowerCycle will power cycle a given machine.
+//
+// PowerCycle reset type is optional in Redfish, so ForceRestart is used as a fallback.
+func (c *Client) PowerCycle() error {
+	system, err := c.getSystem()
+	if err != nil {
+		return err
+	}
+
+	resetType := resetForceRestart
+
+	if slices.Contains(system.Actions.Reset.AllowableResetTypes, resetPowerCycle) {
+		resetType = resetPowerCycle
+	}
+
+	return c.resetWith(system, resetType)
+}
+
+// IsPoweredOn checks current power state.
+func (c *Client) IsPoweredOn() (bool, error) {
+	system, err := c.getSystem()
+	if err != nil {
+		return false, err
+	}
+
+	return system.PowerState == "On", nil
+}
+
+// SetPXE makes sure the node will pxe boot next time.
+func (c *Client) SetPXE(mode types.PXEMode) error {
+	var overrideMode string
+
+	switch mode {
+	case types.PXEModeBIOS:
+		overrideMode = "Legacy"
+	case types.PXEModeUEFI:
+		overrideMode = "UEFI"
+	default:
+		return fmt.Errorf("unsupported mode %q", mode)
+	}
+
+	path, err := c.getSystemPath()
+	if err != nil {
+		return err
+	}
+
+	return c.do(http.MethodPatch, path, map[string]any{
+		"Boot": map[string]string{
+			"BootSourceOverrideEnabled": "Once",
+			"BootSourceOverrideTarget":  "Pxe",
+			"BootSourceOverrideMode":    overrideMode,
+		},
+	}, nil)
+}
+
+// IsFake returns false.
+func (c *Client) IsFake() bool {
+	return false
+}
+
+func (c *Client) reset(resetType string) error {
+	system, err := c.getSystem()
+	if err != nil {
+		return err
+	}
+
+	return c.resetWith(system, resetType)
+}
+
+func (c *Client) resetWith(system *computerSystem, resetType string) error {
+	target := system.Actions.Reset.Target
+	if target == "" {
+		target = c.systemPath + "/Actions/ComputerSystem.Reset"
+	}
+
+	return c.do(http.MethodPost, target, map[string]string{
+		"ResetType": resetType,
+	}, nil)
+}
+
+func (c *Client) getSystem() (*computerSystem, error) {
+	path, err := c.getSystemPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var system computerSystem
+
+	if err = c.do(http.MethodGet, path, nil, &system); err != nil {
+		return nil, err
+	}
+
+	return &system, nil
+}
+
+func (c *Client) getSystemPath() (string, error) {
+	if c.systemPath != "" {
+		return c.systemPath, nil
+	}
+
+	if c.systemID != "" {
+		c.systemPath = systemsPath + "/" + c.systemID
+
+		return c.systemPath, nil
+	}
+
+	var systems systemCollection
+
+	if err := c.do(http.MethodGet, systemsPath, nil, &systems); err != nil {
+		return "", err
+	}
+
+	if len(systems.Members) == 0 {
+		return "", fmt.Errorf("no systems found at Redfish endpoint %q", c.endpoint.Host)
+	}
+
+	c.systemPath = systems.Members[0].ID
+
+	return c.systemPath, nil
+}
+
+func (c *Client) do(method, path string, in, out any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	var body io.Reader
+
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(encoded)
+	}
+
+	u := c.endpoint.JoinPath(path)
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.pass)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("redfish error: %s %s: %s", method, path, resp.Status)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
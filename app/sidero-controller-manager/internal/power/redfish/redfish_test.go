@@ -0,0 +1,216 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package redfish_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/power/redfish"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/pkg/types"
+)
+
+type mockRedfish struct {
+	mu sync.Mutex
+
+	powerState string
+	resetTypes []string
+	boot       map[string]string
+	allowed    []string
+}
+
+func (m *mockRedfish) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /redfish/v1/Systems", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+			"Members": []map[string]string{
+				{"@odata.id": "/redfish/v1/Systems/System.Embedded.1"},
+			},
+		})
+	})
+
+	mux.HandleFunc("GET /redfish/v1/Systems/System.Embedded.1", func(w http.ResponseWriter, _ *http.Request) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+			"PowerState": m.powerState,
+			"Actions": map[string]any{
+				"#ComputerSystem.Reset": map[string]any{
+					"target":                            "/redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset",
+					"ResetType@Redfish.AllowableValues": m.allowed,
+				},
+			},
+		})
+	})
+
+	mux.HandleFunc("PATCH /redfish/v1/Systems/System.Embedded.1", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Boot map[string]string
+		}
+
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		m.mu.Lock()
+		m.boot = req.Boot
+		m.mu.Unlock()
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST /redfish/v1/Systems/System.Embedded.1/Actions/ComputerSystem.Reset", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ResetType string
+		}
+
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		m.resetTypes = append(m.resetTypes, req.ResetType)
+
+		switch req.ResetType {
+		case "On", "ForceRestart", "PowerCycle":
+			m.powerState = "On"
+		case "ForceOff":
+			m.powerState = "Off"
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "admin" || pass != "password" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func setup(t *testing.T, allowed ...string) (*mockRedfish, *redfish.Client) {
+	mock := &mockRedfish{
+		powerState: "Off",
+		allowed:    allowed,
+	}
+
+	srv := httptest.NewTLSServer(mock.handler(t))
+	t.Cleanup(srv.Close)
+
+	client, err := redfish.NewClient(metalv1.Redfish{
+		Endpoint:           srv.Listener.Addr().String(),
+		User:               "admin",
+		Pass:               "password",
+		InsecureSkipVerify: true,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { client.Close() }) //nolint:errcheck
+
+	return mock, client
+}
+
+func TestPower(t *testing.T) {
+	mock, client := setup(t, "On", "ForceOff", "ForceRestart")
+
+	assert.False(t, client.IsFake())
+
+	poweredOn, err := client.IsPoweredOn()
+	require.NoError(t, err)
+	assert.False(t, poweredOn)
+
+	require.NoError(t, client.PowerOn())
+
+	poweredOn, err = client.IsPoweredOn()
+	require.NoError(t, err)
+	assert.True(t, poweredOn)
+
+	require.NoError(t, client.PowerCycle())
+	require.NoError(t, client.PowerOff())
+
+	poweredOn, err = client.IsPoweredOn()
+	require.NoError(t, err)
+	assert.False(t, poweredOn)
+
+	assert.Equal(t, []string{"On", "ForceRestart", "ForceOff"}, mock.resetTypes)
+}
+
+func TestPowerCycleSupported(t *testing.T) {
+	mock, client := setup(t, "On", "ForceOff", "PowerCycle")
+
+	require.NoError(t, client.PowerCycle())
+
+	assert.Equal(t, []string{"PowerCycle"}, mock.resetTypes)
+}
+
+func TestSetPXE(t *testing.T) {
+	for _, tt := range []struct {
+		mode         types.PXEMode
+		expectedMode string
+	}{
+		{
+			mode:         types.PXEModeUEFI,
+			expectedMode: "UEFI",
+		},
+		{
+			mode:         types.PXEModeBIOS,
+			expectedMode: "Legacy",
+		},
+	} {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			mock, client := setup(t)
+
+			require.NoError(t, client.SetPXE(tt.mode))
+
+			assert.Equal(t, map[string]string{
+				"BootSourceOverrideEnabled": "Once",
+				"BootSourceOverrideTarget":  "Pxe",
+				"BootSourceOverrideMode":    tt.expectedMode,
+			}, mock.boot)
+		})
+	}
+
+	_, client := setup(t)
+
+	assert.Error(t, client.SetPXE("floppy"))
+}
+
+func TestUnauthorized(t *testing.T) {
+	mock := &mockRedfish{powerState: "On"}
+
+	srv := httptest.NewTLSServer(mock.handler(t))
+	defer srv.Close()
+
+	client, err := redfish.NewClient(metalv1.Redfish{
+		Endpoint:           srv.URL,
+		User:               "admin",
+		Pass:               "wrong",
+		InsecureSkipVerify: true,
+	})
+	require.NoError(t, err)
+
+	_, err = client.IsPoweredOn()
+	assert.ErrorContains(t, err, "401")
+}
@@ -0,0 +1,168 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot
+
+import (
+	"bytes"
+	"crypto"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+const (
+	// winCertTypePKCSSignedData is the WIN_CERTIFICATE type of the Authenticode signatures.
+	winCertTypePKCSSignedData = 0x0002
+	// winCertHeaderSize is the size of the WIN_CERTIFICATE header: dwLength, wRevision, wCertificateType.
+	winCertHeaderSize = 8
+)
+
+// peImage is the PE image with the offsets excluded from the Authenticode digest.
+type peImage struct {
+	data []byte
+
+	checksumOffset  int
+	certDirOffset   int
+	sizeOfHeaders   int
+	certTableOffset int
+	certTableSize   int
+
+	sections []*pe.Section
+}
+
+func parsePE(data []byte) (*peImage, error) {
+	f, err := pe.NewFile(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PE image: %w", err)
+	}
+
+	defer f.Close() //nolint:errcheck
+
+	if len(data) < 0x40 {
+		return nil, errors.New("PE image is truncated")
+	}
+
+	// the optional header follows the PE signature and the COFF file header
+	optionalHeaderOffset := int(binary.LittleEndian.Uint32(data[0x3c:])) + 4 + 20
+
+	img := &peImage{
+		data:           data,
+		checksumOffset: optionalHeaderOffset + 64,
+		sections:       f.Sections,
+	}
+
+	var certDir pe.DataDirectory
+
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		img.certDirOffset = optionalHeaderOffset + 96 + pe.IMAGE_DIRECTORY_ENTRY_SECURITY*8
+		img.sizeOfHeaders = int(oh.SizeOfHeaders)
+
+		if oh.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_SECURITY {
+			certDir = oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_SECURITY]
+		}
+	case *pe.OptionalHeader64:
+		img.certDirOffset = optionalHeaderOffset + 112 + pe.IMAGE_DIRECTORY_ENTRY_SECURITY*8
+		img.sizeOfHeaders = int(oh.SizeOfHeaders)
+
+		if oh.NumberOfRvaAndSizes > pe.IMAGE_DIRECTORY_ENTRY_SECURITY {
+			certDir = oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_SECURITY]
+		}
+	default:
+		return nil, errors.New("PE image has no optional header")
+	}
+
+	// the certificate table directory entry holds the file offset, not the RVA
+	img.certTableOffset = int(certDir.VirtualAddress)
+	img.certTableSize = int(certDir.Size)
+
+	if img.sizeOfHeaders > len(data) || img.certDirOffset+8 > img.sizeOfHeaders || img.certTableOffset+img.certTableSize > len(data) {
+		return nil, errors.New("PE image headers are malformed")
+	}
+
+	return img, nil
+}
+
+// signatures returns the PKCS#7 signatures from the certificate table.
+func (img *peImage) signatures() ([][]byte, error) {
+	var signatures [][]byte
+
+	table := img.data[img.certTableOffset : img.certTableOffset+img.certTableSize]
+
+	for len(table) >= winCertHeaderSize {
+		length := int(binary.LittleEndian.Uint32(table))
+		certType := binary.LittleEndian.Uint16(table[6:])
+
+		if length < winCertHeaderSize || length > len(table) {
+			return nil, errors.New("malformed certificate table entry")
+		}
+
+		if certType == winCertTypePKCSSignedData {
+			signatures = append(signatures, table[winCertHeaderSize:length])
+		}
+
+		// entries are aligned on the 8-byte boundary
+		length = (length + 7) &^ 7
+		if length > len(table) {
+			break
+		}
+
+		table = table[length:]
+	}
+
+	if len(signatures) == 0 {
+		return nil, errors.New("PE image is not signed")
+	}
+
+	return signatures, nil
+}
+
+// digest calculates the Authenticode digest of the image.
+//
+// The digest covers the headers excluding the checksum and the certificate table directory entry,
+// the sections in the file order, and the data following the sections excluding the certificate table.
+func (img *peImage) digest(hash crypto.Hash) []byte {
+	h := hash.New()
+
+	h.Write(img.data[:img.checksumOffset])
+	h.Write(img.data[img.checksumOffset+4 : img.certDirOffset])
+	h.Write(img.data[img.certDirOffset+8 : img.sizeOfHeaders])
+
+	sections := make([]*pe.Section, 0, len(img.sections))
+
+	for _, section := range img.sections {
+		if section.Size > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	sort.Slice(sections, func(i, j int) bool { return sections[i].Offset < sections[j].Offset })
+
+	hashed := img.sizeOfHeaders
+
+	for _, section := range sections {
+		start, end := int(section.Offset), int(section.Offset)+int(section.Size)
+		if end > len(img.data) {
+			end = len(img.data)
+		}
+
+		h.Write(img.data[start:end])
+
+		hashed = max(hashed, end)
+	}
+
+	end := len(img.data)
+	if img.certTableSize > 0 && img.certTableOffset >= hashed {
+		end = img.certTableOffset
+	}
+
+	if hashed < end {
+		h.Write(img.data[hashed:end])
+	}
+
+	return h.Sum(nil)
+}
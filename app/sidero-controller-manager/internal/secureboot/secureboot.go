@@ -0,0 +1,214 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package secureboot verifies the signatures of the Secure Boot assets against the trust bundle.
+package secureboot
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/smallstep/pkcs7"
+)
+
+var (
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+// LoadTrustBundle loads the PEM-encoded certificates the signatures are verified against.
+func LoadTrustBundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	certs, err := ParseCertificates(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing trust bundle %q: %w", path, err)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found in trust bundle %q", path)
+	}
+
+	pool := x509.NewCertPool()
+
+	for _, cert := range certs {
+		pool.AddCert(cert)
+	}
+
+	return pool, nil
+}
+
+// ParseCertificates parses the PEM-encoded certificates.
+func ParseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	for {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs, nil
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, cert)
+	}
+}
+
+// VerifyOptions configures the signature verification.
+type VerifyOptions struct {
+	// Roots are the trusted certificates.
+	Roots *x509.CertPool
+	// Intermediates are used in addition to the certificates included into the signature.
+	Intermediates []*x509.Certificate
+	// CheckExpiry verifies the certificate chain at the current time, so the images signed with the expired certificates are rejected.
+	//
+	// UEFI firmware doesn't check the certificate validity period, so by default the chain is verified at the time
+	// the signing certificate was issued, and the expired certificates are accepted the same way the firmware does.
+	CheckExpiry bool
+}
+
+// VerifyPE verifies the Authenticode signature embedded into the PE image (UKI, shim).
+//
+// The image is trusted if any of its signatures is valid.
+func VerifyPE(image []byte, opts VerifyOptions) error {
+	img, err := parsePE(image)
+	if err != nil {
+		return err
+	}
+
+	signatures, err := img.signatures()
+	if err != nil {
+		return err
+	}
+
+	var errs error
+
+	for _, signature := range signatures {
+		err = verifyAuthenticode(img, signature, opts)
+		if err == nil {
+			return nil
+		}
+
+		errs = errors.Join(errs, err)
+	}
+
+	return errs
+}
+
+// VerifyDetached verifies the detached PKCS#7 signature (DER or PEM) of the data.
+func VerifyDetached(data, signature []byte, opts VerifyOptions) error {
+	if block, _ := pem.Decode(signature); block != nil {
+		signature = block.Bytes
+	}
+
+	p7, err := pkcs7.Parse(signature)
+	if err != nil {
+		return fmt.Errorf("error parsing signature: %w", err)
+	}
+
+	p7.Content = data
+
+	return verify(p7, opts)
+}
+
+func verifyAuthenticode(img *peImage, signature []byte, opts VerifyOptions) error {
+	// strip the padding of the certificate table entry
+	var raw asn1.RawValue
+
+	if _, err := asn1.Unmarshal(signature, &raw); err == nil {
+		signature = raw.FullBytes
+	}
+
+	p7, err := pkcs7.Parse(signature)
+	if err != nil {
+		return fmt.Errorf("error parsing signature: %w", err)
+	}
+
+	// the content is SpcIndirectDataContent without the SEQUENCE header, which is what the signature covers
+	var (
+		data       asn1.RawValue
+		digestInfo struct {
+			Algorithm pkix.AlgorithmIdentifier
+			Digest    []byte
+		}
+	)
+
+	rest, err := asn1.Unmarshal(p7.Content, &data)
+	if err != nil {
+		return fmt.Errorf("error parsing signed content: %w", err)
+	}
+
+	if _, err = asn1.Unmarshal(rest, &digestInfo); err != nil {
+		return fmt.Errorf("error parsing signed digest: %w", err)
+	}
+
+	hash, err := hashForOID(digestInfo.Algorithm.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(img.digest(hash), digestInfo.Digest) {
+		return errors.New("PE image digest mismatch")
+	}
+
+	return verify(p7, opts)
+}
+
+func verify(p7 *pkcs7.PKCS7, opts VerifyOptions) error {
+	p7.Certificates = append(p7.Certificates, opts.Intermediates...)
+
+	verifyTime := time.Now()
+
+	if !opts.CheckExpiry {
+		signer := p7.GetOnlySigner()
+		if signer == nil {
+			return errors.New("error verifying signature: expected exactly one signer")
+		}
+
+		verifyTime = signer.NotBefore
+	}
+
+	if err := p7.VerifyWithChainAtTime(opts.Roots, verifyTime); err != nil {
+		return fmt.Errorf("error verifying signature: %w", err)
+	}
+
+	return nil
+}
+
+func hashForOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	switch {
+	case oid.Equal(oidSHA1):
+		return crypto.SHA1, nil
+	case oid.Equal(oidSHA256):
+		return crypto.SHA256, nil
+	case oid.Equal(oidSHA384):
+		return crypto.SHA384, nil
+	case oid.Equal(oidSHA512):
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported digest algorithm %s", oid)
+	}
+}
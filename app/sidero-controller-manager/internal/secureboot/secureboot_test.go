@@ -0,0 +1,286 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package secureboot
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"debug/pe"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/smallstep/pkcs7"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var oidSpcIndirectData = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 1, 4}
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCert, isCA bool) *testCert {
+	return newTestCertificateValidFor(t, name, parent, isCA, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+}
+
+func newTestCertificateValidFor(t *testing.T, name string, parent *testCert, isCA bool, notBefore, notAfter time.Time) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	issuer, issuerKey := template, key
+	if parent != nil {
+		issuer, issuerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, issuerKey)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCert{cert: cert, key: key}
+}
+
+// newTestPE builds a minimal PE32+ image with a single section.
+func newTestPE(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	dosHeader := make([]byte, 0x40)
+	copy(dosHeader, "MZ")
+	binary.LittleEndian.PutUint32(dosHeader[0x3c:], 0x40)
+
+	buf.Write(dosHeader)
+	buf.WriteString("PE\x00\x00")
+
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, pe.FileHeader{
+		Machine:              pe.IMAGE_FILE_MACHINE_AMD64,
+		NumberOfSections:     1,
+		SizeOfOptionalHeader: 240,
+		Characteristics:      pe.IMAGE_FILE_EXECUTABLE_IMAGE,
+	}))
+
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, pe.OptionalHeader64{
+		Magic:               0x20b,
+		SectionAlignment:    0x1000,
+		FileAlignment:       0x200,
+		SizeOfImage:         0x2000,
+		SizeOfHeaders:       0x200,
+		Subsystem:           pe.IMAGE_SUBSYSTEM_EFI_APPLICATION,
+		NumberOfRvaAndSizes: 16,
+	}))
+
+	require.NoError(t, binary.Write(&buf, binary.LittleEndian, pe.SectionHeader32{
+		Name:             [8]uint8{'.', 't', 'e', 'x', 't'},
+		VirtualSize:      0x200,
+		VirtualAddress:   0x1000,
+		SizeOfRawData:    0x200,
+		PointerToRawData: 0x200,
+	}))
+
+	buf.Write(make([]byte, 0x200-buf.Len()))
+	buf.Write(bytes.Repeat([]byte{0xcc}, 0x200))
+
+	return buf.Bytes()
+}
+
+// signTestPE appends the Authenticode signature to the image.
+func signTestPE(t *testing.T, image []byte, signer *testCert) []byte {
+	img, err := parsePE(image)
+	require.NoError(t, err)
+
+	spc, err := asn1.Marshal(struct {
+		Data struct {
+			Type asn1.ObjectIdentifier
+		}
+		DigestInfo struct {
+			Algorithm pkix.AlgorithmIdentifier
+			Digest    []byte
+		}
+	}{
+		Data: struct{ Type asn1.ObjectIdentifier }{Type: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 2, 1, 15}},
+		DigestInfo: struct {
+			Algorithm pkix.AlgorithmIdentifier
+			Digest    []byte
+		}{
+			Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256},
+			Digest:    img.digest(crypto.SHA256),
+		},
+	})
+	require.NoError(t, err)
+
+	var spcValue asn1.RawValue
+
+	_, err = asn1.Unmarshal(spc, &spcValue)
+	require.NoError(t, err)
+
+	// the signature covers the SpcIndirectDataContent value without the SEQUENCE header
+	sd, err := pkcs7.NewSignedData(spcValue.Bytes)
+	require.NoError(t, err)
+
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	sd.GetSignedData().ContentInfo.ContentType = oidSpcIndirectData
+	sd.GetSignedData().ContentInfo.Content = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: spc}
+
+	require.NoError(t, sd.AddSigner(signer.cert, signer.key, pkcs7.SignerInfoConfig{}))
+
+	signature, err := sd.Finish()
+	require.NoError(t, err)
+
+	signed := append([]byte(nil), image...)
+	certTableOffset := len(signed)
+
+	entry := make([]byte, winCertHeaderSize, winCertHeaderSize+len(signature)+8)
+	binary.LittleEndian.PutUint32(entry, uint32(winCertHeaderSize+len(signature)))
+	binary.LittleEndian.PutUint16(entry[4:], 0x0200)
+	binary.LittleEndian.PutUint16(entry[6:], winCertTypePKCSSignedData)
+	entry = append(entry, signature...)
+	entry = append(entry, make([]byte, (8-len(entry)%8)%8)...)
+
+	signed = append(signed, entry...)
+
+	binary.LittleEndian.PutUint32(signed[img.certDirOffset:], uint32(certTableOffset))
+	binary.LittleEndian.PutUint32(signed[img.certDirOffset+4:], uint32(len(entry)))
+
+	return signed
+}
+
+func TestVerifyPE(t *testing.T) {
+	ca := newTestCertificate(t, "db", nil, true)
+	otherCA := newTestCertificate(t, "other", nil, true)
+	signer := newTestCertificate(t, "signer", ca, false)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherCA.cert)
+
+	image := newTestPE(t)
+
+	assert.ErrorContains(t, VerifyPE(image, VerifyOptions{Roots: roots}), "not signed")
+
+	signed := signTestPE(t, image, signer)
+
+	assert.NoError(t, VerifyPE(signed, VerifyOptions{Roots: roots}))
+	assert.Error(t, VerifyPE(signed, VerifyOptions{Roots: otherRoots}))
+
+	tampered := append([]byte(nil), signed...)
+	tampered[0x300] ^= 0xff
+
+	assert.ErrorContains(t, VerifyPE(tampered, VerifyOptions{Roots: roots}), "digest mismatch")
+
+	assert.Error(t, VerifyPE([]byte("not a PE image"), VerifyOptions{Roots: roots}))
+}
+
+// TestVerifyPEFixture verifies the image signed with openssl, see testdata/generate.sh.
+func TestVerifyPEFixture(t *testing.T) {
+	// calculated by testdata/generate.sh with sha256sum
+	const expectedDigest = "6cf371e1f6afe5f93840fbbda88155965bab56485f6202c06d51ddedc2d57c2d"
+
+	signed, err := os.ReadFile("testdata/signed.efi")
+	require.NoError(t, err)
+
+	roots, err := LoadTrustBundle("testdata/db.pem")
+	require.NoError(t, err)
+
+	img, err := parsePE(signed)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedDigest, hex.EncodeToString(img.digest(crypto.SHA256)))
+
+	assert.NoError(t, VerifyPE(signed, VerifyOptions{Roots: roots}))
+	assert.NoError(t, VerifyPE(signed, VerifyOptions{Roots: roots, CheckExpiry: true}))
+
+	// .data section
+	tampered := append([]byte(nil), signed...)
+	tampered[0x500] ^= 0xff
+
+	assert.ErrorContains(t, VerifyPE(tampered, VerifyOptions{Roots: roots}), "digest mismatch")
+
+	// trailing data after the sections
+	tampered = append([]byte(nil), signed...)
+	tampered[0x650] ^= 0xff
+
+	assert.ErrorContains(t, VerifyPE(tampered, VerifyOptions{Roots: roots}), "digest mismatch")
+
+	// the checksum is not covered by the digest
+	tampered = append([]byte(nil), signed...)
+	tampered[0x98] ^= 0xff
+
+	assert.NoError(t, VerifyPE(tampered, VerifyOptions{Roots: roots}))
+}
+
+func TestVerifyExpiry(t *testing.T) {
+	ca := newTestCertificateValidFor(t, "db", nil, true, time.Now().Add(-72*time.Hour), time.Now().Add(time.Hour))
+	signer := newTestCertificateValidFor(t, "signer", ca, false, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	data := []byte("unified kernel image")
+
+	// the signing time attribute is omitted, as the signer has already expired
+	sd, err := pkcs7.NewSignedData(data)
+	require.NoError(t, err)
+
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	require.NoError(t, sd.SignWithoutAttr(signer.cert, signer.key, pkcs7.SignerInfoConfig{}))
+	sd.Detach()
+
+	signature, err := sd.Finish()
+	require.NoError(t, err)
+
+	// the validity period is ignored by default, the same way UEFI firmware does
+	assert.NoError(t, VerifyDetached(data, signature, VerifyOptions{Roots: roots}))
+	assert.ErrorContains(t, VerifyDetached(data, signature, VerifyOptions{Roots: roots, CheckExpiry: true}), "expired")
+}
+
+func TestVerifyDetached(t *testing.T) {
+	ca := newTestCertificate(t, "db", nil, true)
+	intermediate := newTestCertificate(t, "intermediate", ca, true)
+	signer := newTestCertificate(t, "signer", intermediate, false)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	data := []byte("unified kernel image")
+
+	sd, err := pkcs7.NewSignedData(data)
+	require.NoError(t, err)
+
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	require.NoError(t, sd.AddSigner(signer.cert, signer.key, pkcs7.SignerInfoConfig{}))
+	sd.Detach()
+
+	signature, err := sd.Finish()
+	require.NoError(t, err)
+
+	// the intermediate is not included into the signature
+	assert.Error(t, VerifyDetached(data, signature, VerifyOptions{Roots: roots}))
+	assert.NoError(t, VerifyDetached(data, signature, VerifyOptions{Roots: roots, Intermediates: []*x509.Certificate{intermediate.cert}}))
+	assert.Error(t, VerifyDetached([]byte("other data"), signature, VerifyOptions{Roots: roots, Intermediates: []*x509.Certificate{intermediate.cert}}))
+}
@@ -0,0 +1,116 @@
+#!/usr/bin/env bash
+
+# Generates the Authenticode-signed PE image used by TestVerifyPEFixture.
+#
+# The image digest is calculated with sha256sum over the byte ranges defined by the Authenticode specification,
+# and the signature is created with openssl, so the fixture doesn't depend on the code under test.
+
+set -euo pipefail
+
+cd "$(dirname "$0")"
+
+tmp=$(mktemp -d)
+trap 'rm -rf "${tmp}"' EXIT
+
+# the certificates are valid for 100 years
+openssl req -x509 -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes -days 36500 \
+  -subj "/CN=Sidero Test DB" \
+  -addext "basicConstraints=critical,CA:TRUE" -addext "keyUsage=critical,keyCertSign,digitalSignature" \
+  -keyout "${tmp}/db.key" -out db.pem 2>/dev/null
+
+openssl req -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+  -subj "/CN=Sidero Test Signer" \
+  -keyout "${tmp}/signer.key" -out "${tmp}/signer.csr" 2>/dev/null
+
+printf 'basicConstraints=CA:FALSE\nkeyUsage=digitalSignature\nextendedKeyUsage=codeSigning\n' > "${tmp}/signer.ext"
+
+openssl x509 -req -days 36500 -in "${tmp}/signer.csr" -CA db.pem -CAkey "${tmp}/db.key" -set_serial 2 \
+  -extfile "${tmp}/signer.ext" -out "${tmp}/signer.pem" 2>/dev/null
+
+# PE32+ image: headers (0x000-0x200), .data (0x400-0x600) listed before .text (0x200-0x400), trailing data (0x600-0x700)
+python3 - "${tmp}/image.efi" <<'PY'
+import struct, sys
+
+image = bytearray(0x700)
+image[0:2] = b"MZ"
+struct.pack_into("<I", image, 0x3c, 0x40)
+image[0x40:0x44] = b"PE\0\0"
+# COFF file header: machine, number of sections, size of optional header, characteristics
+struct.pack_into("<HHIIIHH", image, 0x44, 0x8664, 2, 0, 0, 0, 240, 0x0002)
+# optional header: magic, section alignment, file alignment, size of image, size of headers, checksum, subsystem
+struct.pack_into("<H", image, 0x58, 0x20b)
+struct.pack_into("<II", image, 0x58 + 32, 0x1000, 0x200)
+struct.pack_into("<II", image, 0x58 + 56, 0x3000, 0x200)
+struct.pack_into("<IH", image, 0x58 + 64, 0x12345678, 10)
+struct.pack_into("<I", image, 0x58 + 108, 16)
+# section headers: name, virtual size, virtual address, size of raw data, pointer to raw data
+struct.pack_into("<8sIIII", image, 0x148, b".data", 0x200, 0x2000, 0x200, 0x400)
+struct.pack_into("<8sIIII", image, 0x170, b".text", 0x200, 0x1000, 0x200, 0x200)
+image[0x200:0x400] = b"\xcc" * 0x200
+image[0x400:0x600] = bytes(range(256)) * 2
+image[0x600:0x700] = b"Z" * 0x100
+
+open(sys.argv[1], "wb").write(image)
+PY
+
+# the digest skips the checksum (0x98-0x9c) and the certificate table directory entry (0xe8-0xf0)
+digest=$({
+  head -c $((0x98)) "${tmp}/image.efi"
+  tail -c +$((0x9c + 1)) "${tmp}/image.efi" | head -c $((0xe8 - 0x9c))
+  tail -c +$((0xf0 + 1)) "${tmp}/image.efi"
+} | sha256sum | cut -d' ' -f1)
+
+echo "Authenticode digest: ${digest}"
+
+cat > "${tmp}/spc.cnf" <<CNF
+asn1=SEQUENCE:spc
+
+[spc]
+data=SEQUENCE:data
+digest=SEQUENCE:digest
+
+[data]
+type=OID:1.3.6.1.4.1.311.2.1.15
+value=SEQUENCE:peimage
+
+[peimage]
+flags=FORMAT:HEX,BITSTRING:00
+
+[digest]
+algorithm=SEQUENCE:algorithm
+digest=FORMAT:HEX,OCTETSTRING:${digest}
+
+[algorithm]
+oid=OID:sha256
+parameters=NULL
+CNF
+
+openssl asn1parse -genconf "${tmp}/spc.cnf" -out "${tmp}/spc.der" -noout
+
+# the signature covers SpcIndirectDataContent without the SEQUENCE header
+header=$(openssl asn1parse -inform DER -in "${tmp}/spc.der" | head -1 | sed -E 's/.*hl= *([0-9]+).*/\1/')
+tail -c +$((header + 1)) "${tmp}/spc.der" > "${tmp}/content.der"
+
+openssl cms -sign -binary -nodetach -nosmimecap -md sha256 -outform DER \
+  -econtent_type 1.3.6.1.4.1.311.2.1.4 \
+  -signer "${tmp}/signer.pem" -inkey "${tmp}/signer.key" \
+  -in "${tmp}/content.der" -out "${tmp}/signature.der"
+
+# openssl encapsulates the content as OCTET STRING, Authenticode encapsulates it as SEQUENCE
+offset=$(openssl asn1parse -inform DER -in "${tmp}/signature.der" | grep -A2 '1.3.6.1.4.1.311.2.1.4' | grep 'OCTET STRING' | head -1 | cut -d: -f1 | tr -d ' ')
+printf '\x30' | dd of="${tmp}/signature.der" bs=1 seek="${offset}" conv=notrunc status=none
+
+# WIN_CERTIFICATE: length, revision 2.0, type PKCS_SIGNED_DATA, aligned to 8 bytes
+python3 - "${tmp}/image.efi" "${tmp}/signature.der" signed.efi <<'PY'
+import struct, sys
+
+image = bytearray(open(sys.argv[1], "rb").read())
+signature = open(sys.argv[2], "rb").read()
+
+entry = struct.pack("<IHH", 8 + len(signature), 0x0200, 0x0002) + signature
+entry += b"\0" * (-len(entry) % 8)
+
+struct.pack_into("<II", image, 0xe8, len(image), len(entry))
+
+open(sys.argv[3], "wb").write(image + entry)
+PY
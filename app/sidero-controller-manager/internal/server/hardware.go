@@ -0,0 +1,177 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"fmt"
+	"slices"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+)
+
+// hardwareComponent is a hardware component identified by the stable key.
+type hardwareComponent struct {
+	key         string
+	description string
+}
+
+func processorComponents(hw *metalv1.HardwareInformation) []hardwareComponent {
+	if hw == nil || hw.Compute == nil {
+		return nil
+	}
+
+	var components []hardwareComponent
+
+	for _, processor := range hw.Compute.Processors {
+		if processor == nil {
+			continue
+		}
+
+		components = append(components, hardwareComponent{
+			key:         fmt.Sprintf("%s|%s|%s", processor.Manufacturer, processor.ProductName, processor.SerialNumber),
+			description: fmt.Sprintf("%s (serial %q)", processor.ProductName, processor.SerialNumber),
+		})
+	}
+
+	return components
+}
+
+func memoryComponents(hw *metalv1.HardwareInformation) []hardwareComponent {
+	if hw == nil || hw.Memory == nil {
+		return nil
+	}
+
+	var components []hardwareComponent
+
+	for _, module := range hw.Memory.Modules {
+		if module == nil || module.Size == 0 {
+			continue
+		}
+
+		components = append(components, hardwareComponent{
+			key:         fmt.Sprintf("%s|%s|%s|%d", module.Manufacturer, module.ProductName, module.SerialNumber, module.Size),
+			description: fmt.Sprintf("%s %s %d MB (serial %q)", module.Manufacturer, module.ProductName, module.Size, module.SerialNumber),
+		})
+	}
+
+	return components
+}
+
+func storageComponents(hw *metalv1.HardwareInformation) []hardwareComponent {
+	if hw == nil || hw.Storage == nil {
+		return nil
+	}
+
+	var components []hardwareComponent
+
+	for _, device := range hw.Storage.Devices {
+		if device == nil {
+			continue
+		}
+
+		// device names are not stable across reboots, so they are not a part of the key
+		components = append(components, hardwareComponent{
+			key:         fmt.Sprintf("%s|%s|%s|%d", device.Model, device.Serial, device.WWID, device.Size),
+			description: fmt.Sprintf("%s %s (serial %q)", device.DeviceName, device.Model, device.Serial),
+		})
+	}
+
+	return components
+}
+
+func networkComponents(hw *metalv1.HardwareInformation) []hardwareComponent {
+	if hw == nil || hw.Network == nil {
+		return nil
+	}
+
+	var components []hardwareComponent
+
+	for _, iface := range hw.Network.Interfaces {
+		if iface == nil {
+			continue
+		}
+
+		components = append(components, hardwareComponent{
+			key:         fmt.Sprintf("%s|%s", iface.Name, iface.MAC),
+			description: fmt.Sprintf("%s (MAC %s)", iface.Name, iface.MAC),
+		})
+	}
+
+	return components
+}
+
+func pciComponents(hw *metalv1.HardwareInformation) []hardwareComponent {
+	if hw == nil || hw.PCI == nil {
+		return nil
+	}
+
+	var components []hardwareComponent
+
+	for _, device := range hw.PCI.Devices {
+		if device == nil {
+			continue
+		}
+
+		components = append(components, hardwareComponent{
+			key:         fmt.Sprintf("%s|%s|%s", device.Address, device.VendorID, device.DeviceID),
+			description: fmt.Sprintf("%s (vendor %s, device %s)", device.Address, device.VendorID, device.DeviceID),
+		})
+	}
+
+	return components
+}
+
+// diffComponents returns the components present only in a and only in b, duplicates are counted.
+func diffComponents(a, b []hardwareComponent) (onlyA, onlyB []hardwareComponent) {
+	remaining := slices.Clone(b)
+
+	for _, component := range a {
+		idx := slices.IndexFunc(remaining, func(c hardwareComponent) bool { return c.key == component.key })
+		if idx == -1 {
+			onlyA = append(onlyA, component)
+
+			continue
+		}
+
+		remaining = slices.Delete(remaining, idx, idx+1)
+	}
+
+	return onlyA, remaining
+}
+
+// HardwareChanges returns the list of the hardware components added or removed in the new hardware information.
+//
+// Only the properties which are stable across reboots are compared.
+func HardwareChanges(oldHW, newHW *metalv1.HardwareInformation) []string {
+	var changes []string
+
+	for _, kind := range []struct {
+		name       string
+		components func(*metalv1.HardwareInformation) []hardwareComponent
+	}{
+		{"processor", processorComponents},
+		{"memory module", memoryComponents},
+		{"storage device", storageComponents},
+		{"network interface", networkComponents},
+		{"PCI device", pciComponents},
+	} {
+		// PCI devices are not reported by the older agents, so don't treat all of them as added
+		if kind.name == "PCI device" && (oldHW == nil || oldHW.PCI == nil) {
+			continue
+		}
+
+		removed, added := diffComponents(kind.components(oldHW), kind.components(newHW))
+
+		for _, component := range removed {
+			changes = append(changes, fmt.Sprintf("%s removed: %s", kind.name, component.description))
+		}
+
+		for _, component := range added {
+			changes = append(changes, fmt.Sprintf("%s added: %s", kind.name, component.description))
+		}
+	}
+
+	return changes
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/siderolabs/grpc-proxy/proxy"
@@ -34,9 +36,10 @@ import (
 type server struct {
 	api.UnimplementedAgentServer
 
-	autoAccept   bool
-	insecureWipe bool
-	autoBMC      bool
+	autoAccept                 bool
+	insecureWipe               bool
+	autoBMC                    bool
+	autoCordonOnHardwareChange bool
 
 	c             controllerclient.Client
 	scheme        *runtime.Scheme
@@ -81,6 +84,8 @@ func (s *server) CreateServer(ctx context.Context, in *api.CreateServerRequest)
 		s.recorder.Event(ref, corev1.EventTypeNormal, "Server Registration", "Server auto-registered via API.")
 
 		log.Printf("Added %s", uuid)
+	} else if err = s.updateHardware(ctx, obj, MapHardwareInformation(in.GetHardware())); err != nil {
+		return nil, err
 	}
 
 	resp := &api.CreateServerResponse{}
@@ -90,7 +95,7 @@ func (s *server) CreateServer(ctx context.Context, in *api.CreateServerRequest)
 	if obj.Spec.Accepted {
 		// Respond to agent whether it should attempt bmc setup
 		// We will only tell it to attempt autoconfig if there's not already data there.
-		if obj.Spec.BMC == nil && s.autoBMC {
+		if obj.Spec.BMC == nil && obj.Spec.Redfish == nil && s.autoBMC {
 			log.Printf("Server %q needs BMC setup", obj.Name)
 
 			resp.SetupBmc = true
@@ -104,12 +109,262 @@ func (s *server) CreateServer(ctx context.Context, in *api.CreateServerRequest)
 			resp.Wipe = true
 			resp.InsecureWipe = s.insecureWipe
 			resp.RebootTimeout = s.rebootTimeout.Seconds()
+
+			wipePolicy, err := s.resolveWipePolicy(ctx, obj)
+			if err != nil {
+				return nil, err
+			}
+
+			resp.WipePolicy = MapWipePolicy(wipePolicy)
+
+			validation, err := s.resolveValidation(ctx, obj)
+			if err != nil {
+				return nil, err
+			}
+
+			resp.Validation = MapHardwareValidation(validation)
+
+			// new wipe is about to start, so drop the progress of the previous one
+			if obj.Status.WipeProgress != nil || validation != nil {
+				patchHelper, err := patch.NewHelper(obj, s.c)
+				if err != nil {
+					return nil, err
+				}
+
+				obj.Status.WipeProgress = nil
+
+				if validation != nil {
+					conditions.MarkFalse(obj, metalv1.ConditionHardwareValidated, metalv1.HardwareValidationInProgressReason, clusterv1.ConditionSeverityInfo, "Hardware validation in progress.")
+				}
+
+				if err := patchHelper.Patch(ctx, obj, patch.WithOwnedConditions{
+					Conditions: []clusterv1.ConditionType{metalv1.ConditionHardwareValidated},
+				}); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 
 	return resp, nil
 }
 
+// updateHardware updates the hardware information of the registered server recording the hardware changes.
+func (s *server) updateHardware(ctx context.Context, obj *metalv1.Server, hw *metalv1.HardwareInformation) error {
+	if reflect.DeepEqual(obj.Spec.Hardware, hw) {
+		return nil
+	}
+
+	patchHelper, err := patch.NewHelper(obj, s.c)
+	if err != nil {
+		return err
+	}
+
+	var changes []string
+
+	if obj.Spec.Hardware != nil {
+		changes = HardwareChanges(obj.Spec.Hardware, hw)
+	}
+
+	obj.Spec.Hardware = hw
+
+	var message string
+
+	if len(changes) > 0 {
+		reason := metalv1.HardwareChangedReason
+
+		switch {
+		case s.autoCordonOnHardwareChange && !obj.Spec.Cordoned:
+			obj.Spec.Cordoned = true
+			reason = metalv1.HardwareChangedAutoCordonedReason
+		case conditions.IsTrue(obj, metalv1.ConditionHardwareChanged) && conditions.GetReason(obj, metalv1.ConditionHardwareChanged) == metalv1.HardwareChangedAutoCordonedReason:
+			// the server is still cordoned because of the previous change
+			reason = metalv1.HardwareChangedAutoCordonedReason
+		}
+
+		message = fmt.Sprintf("Hardware changed: %s.", strings.Join(changes, "; "))
+
+		conditions.Set(obj, &clusterv1.Condition{
+			Type:     metalv1.ConditionHardwareChanged,
+			Status:   corev1.ConditionTrue,
+			Severity: clusterv1.ConditionSeverityWarning,
+			Reason:   reason,
+			Message:  message,
+		})
+
+		if reason == metalv1.HardwareChangedAutoCordonedReason {
+			message += " Server is cordoned until the change is acknowledged."
+		}
+	}
+
+	if err = patchHelper.Patch(ctx, obj, patch.WithOwnedConditions{
+		Conditions: []clusterv1.ConditionType{metalv1.ConditionHardwareChanged},
+	}); err != nil {
+		return err
+	}
+
+	if message != "" {
+		ref, err := reference.GetReference(s.scheme, obj)
+		if err != nil {
+			return err
+		}
+
+		s.recorder.Event(ref, corev1.EventTypeWarning, "Server Hardware Changed", message)
+
+		log.Printf("Hardware of %s changed: %s", obj.Name, strings.Join(changes, "; "))
+	}
+
+	return nil
+}
+
+// matchingServerClass returns the first (sorted by name) ServerClass matching the server which passes the filter.
+func (s *server) matchingServerClass(ctx context.Context, obj *metalv1.Server, filter func(*metalv1.ServerClass) bool) (*metalv1.ServerClass, error) {
+	serverClasses := &metalv1.ServerClassList{}
+
+	if err := s.c.List(ctx, serverClasses); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(serverClasses.Items, func(a, b metalv1.ServerClass) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for i := range serverClasses.Items {
+		serverClass := &serverClasses.Items[i]
+
+		if !filter(serverClass) {
+			continue
+		}
+
+		matching, err := metalv1.FilterServers([]metalv1.Server{*obj},
+			serverClass.SelectorFilter(),
+			serverClass.QualifiersFilter(),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matching) > 0 {
+			return serverClass, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// resolveWipePolicy returns the wipe policy of the server.
+//
+// Server's own policy takes precedence, otherwise the policy of the first (sorted by name) matching ServerClass is used.
+// Policy method defaults to the controller setting.
+func (s *server) resolveWipePolicy(ctx context.Context, obj *metalv1.Server) (*metalv1.WipePolicy, error) {
+	var policy *metalv1.WipePolicy
+
+	if obj.Spec.WipePolicy != nil {
+		policy = obj.Spec.WipePolicy.DeepCopy()
+	} else {
+		serverClass, err := s.matchingServerClass(ctx, obj, func(sc *metalv1.ServerClass) bool {
+			return sc.Spec.WipePolicy != nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if serverClass != nil {
+			policy = serverClass.Spec.WipePolicy.DeepCopy()
+		}
+	}
+
+	if policy == nil {
+		return nil, nil
+	}
+
+	if policy.Method == "" {
+		policy.Method = metalv1.WipeMethodZero
+
+		if s.insecureWipe {
+			policy.Method = metalv1.WipeMethodFast
+		}
+	}
+
+	return policy, nil
+}
+
+// resolveValidation returns the hardware validation of the server.
+//
+// Server's own validation takes precedence, otherwise the validation of the first (sorted by name) matching ServerClass is used.
+func (s *server) resolveValidation(ctx context.Context, obj *metalv1.Server) (*metalv1.HardwareValidation, error) {
+	if obj.Spec.Validation != nil {
+		return obj.Spec.Validation, nil
+	}
+
+	serverClass, err := s.matchingServerClass(ctx, obj, func(sc *metalv1.ServerClass) bool {
+		return sc.Spec.Validation != nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if serverClass == nil {
+		return nil, nil
+	}
+
+	return serverClass.Spec.Validation, nil
+}
+
+// MapHardwareValidation converts the hardware validation to the agent API representation.
+func MapHardwareValidation(validation *metalv1.HardwareValidation) *api.HardwareValidation {
+	if validation == nil {
+		return nil
+	}
+
+	result := &api.HardwareValidation{
+		MemoryTestPasses: validation.MemoryTestPasses,
+		DiskHealth:       validation.DiskHealth,
+		MinLinkSpeed:     validation.MinLinkSpeed,
+	}
+
+	if validation.CPUStressDuration != nil {
+		result.CpuStressDuration = validation.CPUStressDuration.Seconds()
+	}
+
+	return result
+}
+
+// MapWipePolicy converts the wipe policy to the agent API representation.
+func MapWipePolicy(policy *metalv1.WipePolicy) *api.WipePolicy {
+	if policy == nil {
+		return nil
+	}
+
+	mapSelectors := func(selectors []metalv1.DiskSelector) []*api.DiskSelector {
+		result := make([]*api.DiskSelector, 0, len(selectors))
+
+		for _, selector := range selectors {
+			result = append(result, &api.DiskSelector{
+				Wwid:   selector.WWID,
+				Serial: selector.Serial,
+				Model:  selector.Model,
+			})
+		}
+
+		return result
+	}
+
+	storageTypeMethods := make(map[string]string, len(policy.StorageTypeMethods))
+
+	for storageType, method := range policy.StorageTypeMethods {
+		storageTypeMethods[storageType] = string(method)
+	}
+
+	return &api.WipePolicy{
+		Method:             string(policy.Method),
+		StorageTypeMethods: storageTypeMethods,
+		Include:            mapSelectors(policy.Include),
+		Exclude:            mapSelectors(policy.Exclude),
+		SkipBootMedia:      policy.SkipBootMedia,
+	}
+}
+
 // MarkServerAsWiped implements api.AgentServer.
 func (s *server) MarkServerAsWiped(ctx context.Context, in *api.MarkServerAsWipedRequest) (*api.MarkServerAsWipedResponse, error) {
 	obj := &metalv1.Server{}
@@ -259,6 +514,102 @@ func (s *server) Heartbeat(ctx context.Context, in *api.HeartbeatRequest) (*api.
 	return resp, nil
 }
 
+// ReportHardwareValidation implements api.AgentServer.
+func (s *server) ReportHardwareValidation(ctx context.Context, in *api.ReportHardwareValidationRequest) (*api.ReportHardwareValidationResponse, error) {
+	obj := &metalv1.Server{}
+
+	if err := s.c.Get(ctx, types.NamespacedName{Name: in.GetUuid()}, obj); err != nil {
+		return nil, err
+	}
+
+	patchHelper, err := patch.NewHelper(obj, s.c)
+	if err != nil {
+		return nil, err
+	}
+
+	obj.Status.HardwareValidation = make([]metalv1.HardwareValidationResult, 0, len(in.GetResults()))
+
+	var failed []string
+
+	for _, result := range in.GetResults() {
+		obj.Status.HardwareValidation = append(obj.Status.HardwareValidation, metalv1.HardwareValidationResult{
+			Name:    result.GetName(),
+			Passed:  result.GetPassed(),
+			Message: result.GetMessage(),
+		})
+
+		if !result.GetPassed() {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.GetName(), result.GetMessage()))
+		}
+	}
+
+	if len(failed) == 0 {
+		conditions.MarkTrue(obj, metalv1.ConditionHardwareValidated)
+	} else {
+		conditions.MarkFalse(obj, metalv1.ConditionHardwareValidated, metalv1.HardwareValidationFailedReason, clusterv1.ConditionSeverityError,
+			"Hardware validation failed: %s.", strings.Join(failed, "; "))
+	}
+
+	if err := patchHelper.Patch(ctx, obj, patch.WithOwnedConditions{
+		Conditions: []clusterv1.ConditionType{metalv1.ConditionHardwareValidated},
+	}); err != nil {
+		return nil, err
+	}
+
+	ref, err := reference.GetReference(s.scheme, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failed) == 0 {
+		s.recorder.Event(ref, corev1.EventTypeNormal, "Server Hardware Validation", "Hardware validation passed.")
+	} else {
+		s.recorder.Event(ref, corev1.EventTypeWarning, "Server Hardware Validation", fmt.Sprintf("Hardware validation failed: %s.", strings.Join(failed, "; ")))
+	}
+
+	return &api.ReportHardwareValidationResponse{}, nil
+}
+
+// ReportWipeProgress implements api.AgentServer.
+//
+// Wipe progress acts as a heartbeat, but the wipe timeout is only extended if the wipe actually progresses.
+func (s *server) ReportWipeProgress(ctx context.Context, in *api.ReportWipeProgressRequest) (*api.ReportWipeProgressResponse, error) {
+	obj := &metalv1.Server{}
+
+	if err := s.c.Get(ctx, types.NamespacedName{Name: in.GetUuid()}, obj); err != nil {
+		return nil, err
+	}
+
+	patchHelper, err := patch.NewHelper(obj, s.c)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := MapWipeProgress(in.GetDisks())
+
+	if obj.Status.WipeProgress == nil || progress.BytesWiped > obj.Status.WipeProgress.BytesWiped {
+		progress.LastProgressTime = metav1.Now()
+
+		// remove the condition in case it was already set to make sure LastTransitionTime will be updated
+		conditions.Delete(obj, metalv1.ConditionPowerCycle)
+		conditions.MarkFalse(obj, metalv1.ConditionPowerCycle, "InProgress", clusterv1.ConditionSeverityInfo, "Server wipe in progress (%d%%).", progress.Percentage)
+	} else {
+		progress.LastProgressTime = obj.Status.WipeProgress.LastProgressTime
+	}
+
+	obj.Status.WipeProgress = progress
+
+	if err := patchHelper.Patch(ctx, obj, patch.WithOwnedConditions{
+		Conditions: []clusterv1.ConditionType{metalv1.ConditionPowerCycle},
+	}); err != nil {
+		return nil, err
+	}
+
+	resp := &api.ReportWipeProgressResponse{}
+
+	return resp, nil
+}
+
 func (s *server) UpdateBMCInfo(ctx context.Context, in *api.UpdateBMCInfoRequest) (*api.UpdateBMCInfoResponse, error) {
 	bmcInfo := in.GetBmcInfo()
 
@@ -369,7 +720,7 @@ func (s *server) UpdateBMCInfo(ctx context.Context, in *api.UpdateBMCInfoRequest
 	return resp, nil
 }
 
-func CreateServer(c controllerclient.Client, recorder record.EventRecorder, scheme *runtime.Scheme, autoAccept, insecureWipe, autoBMC bool, rebootTimeout time.Duration) *grpc.Server {
+func CreateServer(c controllerclient.Client, recorder record.EventRecorder, scheme *runtime.Scheme, autoAccept, insecureWipe, autoBMC, autoCordonOnHardwareChange bool, rebootTimeout time.Duration) *grpc.Server {
 	s := grpc.NewServer(
 		// proxy pass unknown requests to sub-components
 		grpc.ForceServerCodecV2(proxy.Codec()),
@@ -380,13 +731,14 @@ func CreateServer(c controllerclient.Client, recorder record.EventRecorder, sche
 	)
 
 	api.RegisterAgentServer(s, &server{
-		autoAccept:    autoAccept,
-		insecureWipe:  insecureWipe,
-		autoBMC:       autoBMC,
-		c:             c,
-		scheme:        scheme,
-		recorder:      recorder,
-		rebootTimeout: rebootTimeout,
+		autoAccept:                 autoAccept,
+		insecureWipe:               insecureWipe,
+		autoBMC:                    autoBMC,
+		autoCordonOnHardwareChange: autoCordonOnHardwareChange,
+		c:                          c,
+		scheme:                     scheme,
+		recorder:                   recorder,
+		rebootTimeout:              rebootTimeout,
 	})
 
 	return s
@@ -420,26 +772,73 @@ func MapHardwareInformation(hw *api.HardwareInformation) *metalv1.HardwareInform
 	storageDevices := make([]*metalv1.StorageDevice, hw.GetStorage().GetDeviceCount())
 	for i, v := range hw.GetStorage().GetDevices() {
 		storageDevices[i] = &metalv1.StorageDevice{
-			Type:       v.GetType().String(),
-			Size:       v.GetSize(),
-			Model:      v.GetModel(),
-			Serial:     v.GetSerial(),
-			Name:       v.GetName(),
-			DeviceName: v.GetDeviceName(),
-			UUID:       v.GetUuid(),
-			WWID:       v.GetWwid(),
+			Type:        v.GetType().String(),
+			Size:        v.GetSize(),
+			Model:       v.GetModel(),
+			Serial:      v.GetSerial(),
+			Name:        v.GetName(),
+			DeviceName:  v.GetDeviceName(),
+			UUID:        v.GetUuid(),
+			WWID:        v.GetWwid(),
+			Rotational:  v.GetRotational(),
+			Transport:   v.GetTransport(),
+			SMARTHealth: v.GetSmartHealth(),
 		}
 	}
 
 	networkInterfaces := make([]*metalv1.NetworkInterface, hw.GetNetwork().GetInterfaceCount())
 	for i, v := range hw.GetNetwork().GetInterfaces() {
 		networkInterfaces[i] = &metalv1.NetworkInterface{
-			Index:     v.GetIndex(),
-			Name:      v.GetName(),
-			Flags:     v.GetFlags(),
-			MTU:       v.GetMtu(),
-			MAC:       v.GetMac(),
-			Addresses: v.GetAddresses(),
+			Index:           v.GetIndex(),
+			Name:            v.GetName(),
+			Flags:           v.GetFlags(),
+			MTU:             v.GetMtu(),
+			MAC:             v.GetMac(),
+			Addresses:       v.GetAddresses(),
+			Driver:          v.GetDriver(),
+			FirmwareVersion: v.GetFirmwareVersion(),
+			LinkSpeed:       v.GetLinkSpeed(),
+		}
+	}
+
+	var pci *metalv1.PCIInformation
+
+	if hw.GetPci() != nil {
+		pci = &metalv1.PCIInformation{
+			DeviceCount: hw.GetPci().GetDeviceCount(),
+			GPUCount:    hw.GetPci().GetGpuCount(),
+			Devices:     make([]*metalv1.PCIDevice, 0, len(hw.GetPci().GetDevices())),
+		}
+
+		for _, v := range hw.GetPci().GetDevices() {
+			pci.Devices = append(pci.Devices, &metalv1.PCIDevice{
+				Address:           v.GetAddress(),
+				VendorID:          v.GetVendorId(),
+				DeviceID:          v.GetDeviceId(),
+				SubsystemVendorID: v.GetSubsystemVendorId(),
+				SubsystemDeviceID: v.GetSubsystemDeviceId(),
+				Class:             v.GetClass(),
+				Driver:            v.GetDriver(),
+			})
+		}
+	}
+
+	var bios *metalv1.BIOSInformation
+
+	if hw.GetBios() != nil {
+		bios = &metalv1.BIOSInformation{
+			Vendor:      hw.GetBios().GetVendor(),
+			Version:     hw.GetBios().GetVersion(),
+			ReleaseDate: hw.GetBios().GetReleaseDate(),
+		}
+	}
+
+	var tpm *metalv1.TPMInformation
+
+	if hw.GetTpm() != nil {
+		tpm = &metalv1.TPMInformation{
+			Present: hw.GetTpm().GetPresent(),
+			Version: hw.GetTpm().GetVersion(),
 		}
 	}
 
@@ -473,5 +872,33 @@ func MapHardwareInformation(hw *api.HardwareInformation) *metalv1.HardwareInform
 			InterfaceCount: hw.GetNetwork().GetInterfaceCount(),
 			Interfaces:     networkInterfaces,
 		},
+		PCI:  pci,
+		BIOS: bios,
+		TPM:  tpm,
+	}
+}
+
+func MapWipeProgress(disks []*api.DiskWipeProgress) *metalv1.WipeProgress {
+	progress := &metalv1.WipeProgress{
+		Disks: make([]metalv1.DiskWipeProgress, 0, len(disks)),
+	}
+
+	for _, v := range disks {
+		progress.BytesWiped += v.GetBytesWiped()
+		progress.TotalBytes += v.GetTotalBytes()
+
+		progress.Disks = append(progress.Disks, metalv1.DiskWipeProgress{
+			DeviceName: v.GetDeviceName(),
+			BytesWiped: v.GetBytesWiped(),
+			TotalBytes: v.GetTotalBytes(),
+			Method:     v.GetMethod(),
+			Complete:   v.GetComplete(),
+		})
 	}
+
+	if progress.TotalBytes > 0 {
+		progress.Percentage = int32(progress.BytesWiped * 100 / progress.TotalBytes)
+	}
+
+	return progress
 }
@@ -4,11 +4,118 @@
 
 package server_test
 
-import "testing"
+import (
+	"testing"
 
-func TestEmpty(t *testing.T) {
-	// added for accurate coverage estimation
-	//
-	// please remove it once any unit-test is added
-	// for this package
+	"github.com/stretchr/testify/assert"
+
+	metalv1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/api"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/server"
+)
+
+func TestMapWipeProgress(t *testing.T) {
+	progress := server.MapWipeProgress([]*api.DiskWipeProgress{
+		{
+			DeviceName: "/dev/sda",
+			BytesWiped: 1000,
+			TotalBytes: 1000,
+			Method:     "blkzeroout",
+			Complete:   true,
+		},
+		{
+			DeviceName: "/dev/sdb",
+			BytesWiped: 500,
+			TotalBytes: 3000,
+			Method:     "writezeroes",
+		},
+	})
+
+	assert.Equal(t, int32(37), progress.Percentage)
+	assert.Equal(t, uint64(1500), progress.BytesWiped)
+	assert.Equal(t, uint64(4000), progress.TotalBytes)
+	assert.Equal(t, []metalv1.DiskWipeProgress{
+		{
+			DeviceName: "/dev/sda",
+			BytesWiped: 1000,
+			TotalBytes: 1000,
+			Method:     "blkzeroout",
+			Complete:   true,
+		},
+		{
+			DeviceName: "/dev/sdb",
+			BytesWiped: 500,
+			TotalBytes: 3000,
+			Method:     "writezeroes",
+		},
+	}, progress.Disks)
+
+	assert.Equal(t, int32(0), server.MapWipeProgress(nil).Percentage)
+}
+
+func TestHardwareChanges(t *testing.T) {
+	oldHW := &metalv1.HardwareInformation{
+		Compute: &metalv1.ComputeInformation{
+			Processors: []*metalv1.Processor{
+				{Manufacturer: "Intel", ProductName: "Xeon", SerialNumber: "1"},
+				{Manufacturer: "Intel", ProductName: "Xeon", SerialNumber: "1"},
+			},
+		},
+		Memory: &metalv1.MemoryInformation{
+			Modules: []*metalv1.MemoryModule{
+				{Manufacturer: "Samsung", ProductName: "M393", SerialNumber: "A", Size: 16384},
+				{Manufacturer: "Samsung", ProductName: "M393", SerialNumber: "B", Size: 16384},
+				{},
+			},
+		},
+		Storage: &metalv1.StorageInformation{
+			Devices: []*metalv1.StorageDevice{
+				{DeviceName: "/dev/sda", Model: "ST4000", Serial: "Z1", Size: 4000},
+				{DeviceName: "/dev/sdb", Model: "ST4000", Serial: "Z2", Size: 4000},
+			},
+		},
+		Network: &metalv1.NetworkInformation{
+			Interfaces: []*metalv1.NetworkInterface{
+				{Name: "eth0", MAC: "00:00:00:00:00:01", Addresses: []string{"10.5.0.2/24"}},
+			},
+		},
+	}
+
+	newHW := oldHW.DeepCopy()
+
+	assert.Empty(t, server.HardwareChanges(oldHW, newHW))
+
+	// device names and addresses might change between boots
+	newHW.Storage.Devices[0].DeviceName = "/dev/sdb"
+	newHW.Storage.Devices[1].DeviceName = "/dev/sda"
+	newHW.Network.Interfaces[0].Addresses = []string{"10.5.0.3/24"}
+
+	assert.Empty(t, server.HardwareChanges(oldHW, newHW))
+
+	// PCI devices are not compared if the previous inventory doesn't have them
+	newHW.PCI = &metalv1.PCIInformation{
+		Devices: []*metalv1.PCIDevice{
+			{Address: "0000:01:00.0", VendorID: "0x10de", DeviceID: "0x2330", Class: "0x030200"},
+		},
+	}
+
+	assert.Empty(t, server.HardwareChanges(oldHW, newHW))
+
+	oldHW.PCI = &metalv1.PCIInformation{}
+
+	newHW.Compute.Processors = newHW.Compute.Processors[:1]
+	newHW.Memory.Modules[1].SerialNumber = "C"
+	newHW.Storage.Devices = newHW.Storage.Devices[1:]
+	newHW.Network.Interfaces = append(newHW.Network.Interfaces, &metalv1.NetworkInterface{Name: "eth1", MAC: "00:00:00:00:00:02"})
+
+	assert.Equal(t, []string{
+		`processor removed: Xeon (serial "1")`,
+		`memory module removed: Samsung M393 16384 MB (serial "B")`,
+		`memory module added: Samsung M393 16384 MB (serial "C")`,
+		`storage device removed: /dev/sda ST4000 (serial "Z1")`,
+		"network interface added: eth1 (MAC 00:00:00:00:00:02)",
+		"PCI device added: 0000:01:00.0 (vendor 0x10de, device 0x2330)",
+	}, server.HardwareChanges(oldHW, newHW))
+
+	assert.Empty(t, server.HardwareChanges(nil, nil))
 }
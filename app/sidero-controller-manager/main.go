@@ -6,6 +6,7 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"net/http"
@@ -39,10 +40,12 @@ import (
 	metalv1alpha1 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha1"
 	metalv1alpha2 "github.com/siderolabs/sidero/app/sidero-controller-manager/api/v1alpha2"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/controllers"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/assets"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/dhcp"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/ipxe"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/metadata"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/power/api"
+	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/secureboot"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/server"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/siderolink"
 	"github.com/siderolabs/sidero/app/sidero-controller-manager/internal/tftp"
@@ -85,10 +88,20 @@ var (
 	enableLeaderElection bool
 	autoAcceptServers    bool
 	insecureWipe         bool
+	insecureConfigData   bool
 	autoBMCSetup         bool
+	autoCordonOnHWChange bool
 	serverRebootTimeout  time.Duration
 	ipmiPXEMethod        string
 	disableDHCPProxy     bool
+	dhcpMode             string
+	dhcpBootEndpoints    []string
+	httpBootMode         string
+	trustBundlePath      string
+	checkSignatureExpiry bool
+	assetGCInterval      time.Duration
+	assetNamespaces      []string
+	assetFileRoot        string
 	webhookPort          int
 	webhookCertDir       string
 
@@ -115,10 +128,20 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&enableLeaderElection, "enable-leader-election", true, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	fs.BoolVar(&autoAcceptServers, "auto-accept-servers", false, "Add servers as 'accepted' when they register with Sidero API.")
 	fs.BoolVar(&insecureWipe, "insecure-wipe", true, "Wipe head of the disk only (if false, wipe whole disk).")
+	fs.BoolVar(&insecureConfigData, "insecure-configdata", false, "Serve the machine configuration to any client which knows the server UUID (if false, require the SideroLink address or the one-time token).")
 	fs.BoolVar(&autoBMCSetup, "auto-bmc-setup", true, "Attempt to setup BMC info automatically when agent boots.")
+	fs.BoolVar(&autoCordonOnHWChange, "auto-cordon-on-hardware-change", false, "Cordon the server when the hardware reported by the agent changes until the change is acknowledged.")
 	fs.DurationVar(&serverRebootTimeout, "server-reboot-timeout", constants.DefaultServerRebootTimeout, "Timeout to wait for the server to restart and start wipe.")
 	fs.StringVar(&ipmiPXEMethod, "ipmi-pxe-method", string(siderotypes.PXEModeUEFI), fmt.Sprintf("Default method to use to set server to boot from PXE via IPMI: %s.", []string{siderotypes.PXEModeUEFI, siderotypes.PXEModeBIOS}))
 	fs.BoolVar(&disableDHCPProxy, "disable-dhcp-proxy", false, "Disable DHCP Proxy service.")
+	fs.StringSliceVar(&dhcpBootEndpoints, "dhcp-boot-endpoints", nil, "Boot server addresses advertised by the DHCP proxy to the clients on the subnets: <subnet>=<endpoint>,... (e.g. '10.0.1.0/24=10.0.1.5').")
+	fs.StringVar(&httpBootMode, "http-boot-mode", string(siderotypes.HTTPBootIPXE), fmt.Sprintf("Boot method for the UEFI HTTP boot clients: %s.", []siderotypes.HTTPBootMode{siderotypes.HTTPBootIPXE, siderotypes.HTTPBootNative}))
+	fs.StringVar(&trustBundlePath, "secure-boot-trust-bundle", "", "Path to the PEM-encoded certificates to verify the signatures of the Environment UKI and shim assets against, verification is disabled if not set.")
+	fs.BoolVar(&checkSignatureExpiry, "secure-boot-check-expiry", false, "Reject the Environment UKI and shim assets signed with the expired certificates, by default the certificate validity period is ignored the same way UEFI firmware does.")
+	fs.DurationVar(&assetGCInterval, "asset-gc-interval", time.Hour, "Interval to garbage collect the Environment assets which are no longer referenced, disabled if zero.")
+	fs.StringSliceVar(&assetNamespaces, "asset-namespaces", nil, "Namespaces the Environment assets are allowed to be read from the Secrets and ConfigMaps in, in addition to the controller manager namespace.")
+	fs.StringVar(&assetFileRoot, "asset-file-root", "", "Directory the Environment file assets are confined to, the file assets are disabled if not set.")
+	fs.StringVar(&dhcpMode, "dhcp-mode", string(dhcp.ModeProxy), fmt.Sprintf("DHCP service mode: %s.", []dhcp.Mode{dhcp.ModeProxy, dhcp.ModeAuthoritative}))
 	fs.Float64Var(&testPowerSimulatedExplicitFailureProb, "test-power-simulated-explicit-failure-prob", 0, "Test failure simulation setting.")
 	fs.Float64Var(&testPowerSimulatedSilentFailureProb, "test-power-simulated-silent-failure-prob", 0, "Test failure simulation setting.")
 
@@ -231,6 +254,27 @@ func main() {
 
 	ctx := ctrl.SetupSignalHandler()
 
+	var trustBundle *x509.CertPool
+
+	if trustBundlePath != "" {
+		if trustBundle, err = secureboot.LoadTrustBundle(trustBundlePath); err != nil {
+			setupLog.Error(err, "unable to load Secure Boot trust bundle")
+			os.Exit(1)
+		}
+	}
+
+	// every replica serves the assets from its local copy, so the replicas are identified by the pod name
+	replica := os.Getenv("POD_NAME")
+	if replica == "" {
+		replica, _ = os.Hostname() //nolint:errcheck
+	}
+
+	assetStore, err := assets.NewStore(constants.AssetsDirectory)
+	if err != nil {
+		setupLog.Error(err, "unable to create asset store")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.EnvironmentReconciler{
 		Client:       mgr.GetClient(),
 		Log:          ctrl.Log.WithName("controllers").WithName("Environment"),
@@ -238,6 +282,17 @@ func main() {
 		TalosRelease: TalosRelease,
 		APIEndpoint:  apiEndpoint,
 		APIPort:      uint16(apiPort),
+		TrustBundle:  trustBundle,
+		Store:        assetStore,
+		GCInterval:   assetGCInterval,
+		Replica:      replica,
+
+		ReplicaNamespace:     os.Getenv("POD_NAMESPACE"),
+		APIReader:            mgr.GetAPIReader(),
+		CheckSignatureExpiry: checkSignatureExpiry,
+		AssetNamespaces:      append(assetNamespaces, os.Getenv("POD_NAMESPACE")),
+		AssetFileRoot:        assetFileRoot,
+		Elected:              mgr.Elected(),
 	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: defaultMaxConcurrentReconciles}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Environment")
 		os.Exit(1)
@@ -265,6 +320,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.DHCPPoolReconciler{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("DHCPPool"),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: defaultMaxConcurrentReconciles}); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "DHCPPool")
+		os.Exit(1)
+	}
+
 	setupWebhooks(mgr)
 	setupChecks(mgr, httpPort)
 
@@ -272,16 +336,43 @@ func main() {
 
 	errCh := make(chan error)
 
-	if !disableDHCPProxy {
-		setupLog.Info("starting proxy DHCP server")
-
-		go func() {
-			if err := dhcp.ServeDHCP(ctrl.Log.WithName("dhcp-proxy"), apiEndpoint, apiPort); err != nil {
-				setupLog.Error(err, "unable to start proxy DHCP server", "controller", "Environment")
+	if !siderotypes.HTTPBootMode(httpBootMode).IsValid() {
+		setupLog.Error(fmt.Errorf("unknown HTTP boot mode %q", httpBootMode), "invalid HTTP boot mode")
+		os.Exit(1)
+	}
 
-				errCh <- err
+	if !disableDHCPProxy {
+		switch dhcp.Mode(dhcpMode) {
+		case dhcp.ModeProxy:
+			setupLog.Info("starting proxy DHCP server")
+
+			bootEndpoints, err := dhcp.ParseBootEndpoints(dhcpBootEndpoints)
+			if err != nil {
+				setupLog.Error(err, "invalid DHCP boot endpoints")
+				os.Exit(1)
 			}
-		}()
+
+			go func() {
+				if err := dhcp.ServeDHCP(ctrl.Log.WithName("dhcp-proxy"), apiEndpoint, apiPort, siderotypes.HTTPBootMode(httpBootMode), bootEndpoints); err != nil {
+					setupLog.Error(err, "unable to start proxy DHCP server", "controller", "Environment")
+
+					errCh <- err
+				}
+			}()
+		case dhcp.ModeAuthoritative:
+			setupLog.Info("starting authoritative DHCP server")
+
+			go func() {
+				if err := dhcp.ServeAuthoritativeDHCP(ctx, ctrl.Log.WithName("dhcp-server"), mgr.GetClient(), mgr.GetAPIReader(), apiEndpoint, apiPort, siderotypes.HTTPBootMode(httpBootMode)); err != nil {
+					setupLog.Error(err, "unable to start authoritative DHCP server", "controller", "DHCPPool")
+
+					errCh <- err
+				}
+			}()
+		default:
+			setupLog.Error(fmt.Errorf("unknown DHCP mode %q", dhcpMode), "invalid DHCP mode")
+			os.Exit(1)
+		}
 	}
 
 	setupLog.Info("starting TFTP server")
@@ -298,25 +389,32 @@ func main() {
 
 	setupLog.Info("starting iPXE server")
 
-	if err := ipxe.RegisterIPXE(httpMux, apiEndpoint, apiPort, extraAgentKernelArgs, siderotypes.BootFromDisk(bootFromDiskMethod), apiPort, mgr.GetClient()); err != nil {
+	if err := ipxe.RegisterIPXE(httpMux, apiEndpoint, apiPort, extraAgentKernelArgs, siderotypes.BootFromDisk(bootFromDiskMethod), apiPort, replica, !insecureConfigData, mgr.GetClient()); err != nil {
 		setupLog.Error(err, "unable to start iPXE server", "controller", "Environment")
 		os.Exit(1)
 	}
 
 	setupLog.Info("starting metadata server")
 
-	if err := metadata.RegisterServer(httpMux, mgr.GetClient(), apiEndpoint, apiPort); err != nil {
+	metadataRecorder := eventBroadcaster.NewRecorder(
+		mgr.GetScheme(),
+		corev1.EventSource{Component: "sidero-metadata"})
+
+	if err := metadata.RegisterServer(httpMux, mgr.GetClient(), metadataRecorder, apiEndpoint, apiPort, insecureConfigData); err != nil {
 		setupLog.Error(err, "unable to start metadata server", "controller", "Environment")
 		os.Exit(1)
 	}
 
+	// the preview requests carry the bearer tokens, so they are served by the TLS webhook server
+	mgr.GetWebhookServer().Register("/configpreview", metadata.NewPreviewHandler(mgr.GetClient(), clientset, apiEndpoint, apiPort))
+
 	setupLog.Info("starting internal API server")
 
 	apiRecorder := eventBroadcaster.NewRecorder(
 		mgr.GetScheme(),
 		corev1.EventSource{Component: "sidero-server"})
 
-	grpcServer := server.CreateServer(mgr.GetClient(), apiRecorder, mgr.GetScheme(), autoAcceptServers, insecureWipe, autoBMCSetup, serverRebootTimeout)
+	grpcServer := server.CreateServer(mgr.GetClient(), apiRecorder, mgr.GetScheme(), autoAcceptServers, insecureWipe, autoBMCSetup, autoCordonOnHWChange, serverRebootTimeout)
 
 	if err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
 		return siderolink.Cfg.LoadOrCreate(ctx, mgr.GetClient())
@@ -414,6 +512,16 @@ func setupWebhooks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Server")
 		os.Exit(1)
 	}
+
+	if err := (&metalv1alpha2.DHCPPool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "DHCPPool")
+		os.Exit(1)
+	}
+
+	if err := (&metalv1alpha2.MachineConfigPatch{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "MachineConfigPatch")
+		os.Exit(1)
+	}
 }
 
 func setupChecks(mgr ctrl.Manager, httpPort int) {
@@ -8,11 +8,16 @@ import "time"
 
 const (
 	DataDirectory    = "/var/lib/sidero"
+	AssetsDirectory  = DataDirectory + "/assets"
 	AgentEndpointArg = "sidero.endpoint"
 	AgentMACArg      = "sidero.mac"
 
-	KernelAsset = "vmlinuz"
-	InitrdAsset = "initramfs.xz"
+	KernelAsset         = "vmlinuz"
+	InitrdAsset         = "initramfs.xz"
+	UKIAsset            = "uki.efi"
+	UKISignatureAsset   = "uki.efi.p7s"
+	UKICertificateAsset = "uki.pem"
+	ShimAsset           = "shim.efi"
 
 	DefaultRequeueAfter = time.Second * 20
 	PowerCheckPeriod    = 5 * time.Minute
@@ -31,3 +31,20 @@ func (mode PXEMode) IsValid() bool {
 		return false
 	}
 }
+
+// HTTPBootMode specifies how the UEFI HTTP boot clients are booted.
+type HTTPBootMode string
+
+const (
+	HTTPBootIPXE   HTTPBootMode = "ipxe"   // Chainload iPXE, which boots the environment kernel and initrd.
+	HTTPBootNative HTTPBootMode = "native" // Boot the environment UKI directly, falling back to iPXE if the environment has no UKI.
+)
+
+func (mode HTTPBootMode) IsValid() bool {
+	switch mode {
+	case HTTPBootIPXE, HTTPBootNative:
+		return true
+	default:
+		return false
+	}
+}